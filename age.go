@@ -0,0 +1,167 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/knadh/koanf/v2"
+)
+
+var (
+	BadAgeFileError     = errors.New("malformed age encrypted file")
+	BadAgeIdentityError = errors.New("malformed age identity")
+	NoAgeIdentityError  = errors.New("no age identity can decrypt the file")
+)
+
+// AgeExtension marks a config file as encrypted with age, as in
+// config.yaml.age. The file is parsed according to the extension before it.
+const AgeExtension = ".age"
+
+// Environment variables, following the prefix, that supply age identities.
+const (
+	// AgeIdentityEnv holds identities, in the format written by
+	// age-keygen.
+	AgeIdentityEnv = "AGE_IDENTITY"
+	// AgeIdentityFileEnv names a file holding identities.
+	AgeIdentityFileEnv = "AGE_IDENTITY_FILE"
+)
+
+// WithAgeIdentities adds identities, in the format written by age-keygen, used
+// to decrypt config files ending in AgeExtension. Identities are also read
+// from the environment variables named by the prefix followed by
+// AgeIdentityEnv or AgeIdentityFileEnv, such as APP_AGE_IDENTITY, which are
+// not loaded as configuration. Only X25519 identities are supported.
+func WithAgeIdentities(identities ...string) Option {
+	return func(c *Config) {
+		c.ageIdentities = append(c.ageIdentities, identities...)
+	}
+}
+
+// isAgeFile reports whether name is a config file encrypted with age.
+func isAgeFile(name string) bool {
+	return strings.HasSuffix(strings.ToLower(name), AgeExtension)
+}
+
+// trimAgeExtension returns name without AgeExtension.
+func trimAgeExtension(name string) string {
+	if isAgeFile(name) {
+		return name[:len(name)-len(AgeExtension)]
+	}
+	return name
+}
+
+// ageEnv reports whether the environment variable name supplies age
+// identities rather than configuration.
+func (c Config) ageEnv(name string) bool {
+	return name == c.prefix+AgeIdentityEnv || name == c.prefix+AgeIdentityFileEnv
+}
+
+// ageKeys returns the X25519 identities from WithAgeIdentities and the
+// environment.
+func (c Config) ageKeys() ([]age.Identity, error) {
+	texts := append([]string(nil), c.ageIdentities...)
+	if v := os.Getenv(c.prefix + AgeIdentityEnv); v != "" {
+		texts = append(texts, v)
+	}
+	if name := os.Getenv(c.prefix + AgeIdentityFileEnv); name != "" {
+		b, err := os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("read age identities: %w", err)
+		}
+		texts = append(texts, string(b))
+	}
+	var keys []age.Identity
+	for _, text := range texts {
+		for _, line := range strings.Split(text, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			key, err := age.ParseX25519Identity(line)
+			if err != nil {
+				return nil, fmt.Errorf("%v: %w", err, BadAgeIdentityError)
+			}
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+// ageProvider returns the provider and parser for the age encrypted config
+// file name. The decrypted contents are never streamed or cached.
+func (c Config) ageProvider(ctx context.Context, name string) (koanf.Provider, koanf.Parser, error) {
+	p, err := c.provider(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	parser, err := c.parserFor(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return bytesProvider(func() ([]byte, error) {
+		b, err := p.ReadBytes()
+		if err != nil {
+			return nil, err
+		}
+		return c.decryptAge(b)
+	}), parser, nil
+}
+
+// decryptAge returns the plaintext of the age encrypted file b, which may be
+// armored, using the identities configured for c.
+func (c Config) decryptAge(b []byte) ([]byte, error) {
+	keys, err := c.ageKeys()
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, NoAgeIdentityError
+	}
+	var in io.Reader = bytes.NewReader(b)
+	if bytes.HasPrefix(bytes.TrimSpace(b), []byte(armor.Header)) {
+		in = armor.NewReader(bufio.NewReader(bytes.NewReader(bytes.TrimSpace(b))))
+	}
+	r, err := age.Decrypt(in, keys...)
+	var noMatch *age.NoIdentityMatchError
+	switch {
+	case errors.As(err, &noMatch):
+		return nil, NoAgeIdentityError
+	case err != nil:
+		return nil, fmt.Errorf("%v: %w", err, BadAgeFileError)
+	}
+	plain, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", err, BadAgeFileError)
+	}
+	return plain, nil
+}
@@ -0,0 +1,151 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/pflag"
+)
+
+const (
+	// testAgeIdentity decrypts the age encrypted files in testDataDir.
+	testAgeIdentity = "AGE-SECRET-KEY-1NDLXP5Q02UG8X0QDXM92VKZYTMMV34DEZ0NTXMXLQQAEPVGLVJAQYJK040"
+	testAgeOther    = "AGE-SECRET-KEY-1RFPS8TGG0T3W5XQWPRP0Q3EFQD8JT9P9A9YLH3WMFDKKDYDN50CS38FH6W"
+)
+
+func TestLoadAge(t *testing.T) {
+	cases := []struct {
+		name     string
+		file     string
+		opts     []Option
+		env      string
+		wantName string
+		wantErr  error
+	}{
+		{
+			name:     "armored",
+			file:     "secret.yaml.age",
+			opts:     []Option{WithAgeIdentities("# created: today\n" + testAgeIdentity + "\n")},
+			wantName: "secret",
+		},
+		{
+			name:     "several chunks",
+			file:     "big.json.age",
+			opts:     []Option{WithAgeIdentities(testAgeOther, testAgeIdentity)},
+			wantName: "big",
+		},
+		{
+			name:     "identity from env",
+			file:     "secret.yaml.age",
+			env:      testAgeIdentity,
+			wantName: "secret",
+		},
+		{
+			name:    "wrong identity",
+			file:    "secret.yaml.age",
+			opts:    []Option{WithAgeIdentities(testAgeOther)},
+			wantErr: NoAgeIdentityError,
+		},
+		{
+			name:    "bad identity",
+			file:    "secret.yaml.age",
+			opts:    []Option{WithAgeIdentities(testAgeIdentity[:len(testAgeIdentity)-1] + "q")},
+			wantErr: BadAgeIdentityError,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(testPrefix+AgeIdentityEnv, tc.env)
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			AddConfigFlag(f)
+			if err := f.Parse([]string{"--" + FileArgName + "=" + testFileName(tc.file)}); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+			c, err := New(testPrefix, testDelimiter, append(tc.opts, WithStrict())...)
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+			var got struct {
+				Name string `koanf:"name"`
+				Pad  string `koanf:"pad"`
+				DB   struct {
+					Host string `koanf:"host"`
+				} `koanf:"db"`
+			}
+			err = c.Load(f, &got)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Load err: got=%v want=%v", err, tc.wantErr)
+			}
+			if err == nil && got.Name != tc.wantName {
+				t.Errorf("Load name: got=%q want=%q", got.Name, tc.wantName)
+			}
+		})
+	}
+}
+
+func TestDecryptAgeTampered(t *testing.T) {
+	b, err := os.ReadFile(testFileName("big.json.age"))
+	if err != nil {
+		t.Fatalf("os.ReadFile failed unexpectedly: %v", err)
+	}
+	c, err := New(testPrefix, testDelimiter, WithAgeIdentities(testAgeIdentity))
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	for _, i := range []int{60, len(b) - 1} {
+		tampered := append([]byte(nil), b...)
+		tampered[i] ^= 1
+		if _, err := c.decryptAge(tampered); !errors.Is(err, BadAgeFileError) && !errors.Is(err, NoAgeIdentityError) {
+			t.Errorf("decryptAge(byte %d flipped) err: got=%v want=%v", i, err, BadAgeFileError)
+		}
+	}
+	if _, err := c.decryptAge(b[:len(b)-100]); !errors.Is(err, BadAgeFileError) {
+		t.Errorf("decryptAge(truncated) err: got=%v want=%v", err, BadAgeFileError)
+	}
+}
+
+func TestKnownExtensionAge(t *testing.T) {
+	dir := t.TempDir()
+	b, err := os.ReadFile(testFileName("secret.yaml.age"))
+	if err != nil {
+		t.Fatalf("os.ReadFile failed unexpectedly: %v", err)
+	}
+	writeTestFile(t, filepath.Join(dir, "secret.yaml.age"), string(b))
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	got, err := c.expandDir(fileArg{name: dir})
+	if err != nil {
+		t.Fatalf("expandDir err: got=%v want=nil", err)
+	}
+	want := []fileArg{{name: filepath.Join(dir, "secret.yaml.age")}}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(fileArg{})); diff != "" {
+		t.Errorf("expandDir mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,104 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"strings"
+)
+
+// LoadErrors is returned by Load, when WithAllErrors is used, if more than
+// one problem was found. errors.Is and errors.As match any of Errors.
+type LoadErrors struct {
+	Errors []error
+}
+
+func (e *LoadErrors) Error() string {
+	ss := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		ss[i] = err.Error()
+	}
+	return strings.Join(ss, "\n")
+}
+
+// Is reports whether any of the errors matches target.
+func (e *LoadErrors) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first of the errors that matches target.
+func (e *LoadErrors) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithAllErrors makes Load report every problem it finds, in every config
+// file, the environment, the flags and the values themselves, rather than
+// stopping at the first one, so that they can all be fixed at once. If there
+// is more than one problem Load returns a *LoadErrors.
+func WithAllErrors() Option {
+	return func(c *Config) {
+		c.allErrors = true
+	}
+}
+
+// collector gathers the errors found by Load.
+type collector struct {
+	all  bool
+	errs []error
+}
+
+// add records err, if it is not nil, and reports whether loading should
+// continue.
+func (ec *collector) add(err error) bool {
+	if err == nil {
+		return true
+	}
+	var le *LoadErrors
+	if errors.As(err, &le) {
+		ec.errs = append(ec.errs, le.Errors...)
+	} else {
+		ec.errs = append(ec.errs, err)
+	}
+	return ec.all
+}
+
+// err returns the errors that were recorded.
+func (ec *collector) err() error {
+	switch len(ec.errs) {
+	case 0:
+		return nil
+	case 1:
+		return ec.errs[0]
+	}
+	return &LoadErrors{Errors: ec.errs}
+}
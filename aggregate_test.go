@@ -0,0 +1,103 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+type testAllErrorsConfig struct {
+	Value1 int    `koanf:"value1"`
+	Value2 int    `koanf:"value2"`
+	Name   string `koanf:"name,required"`
+}
+
+func TestLoadWithAllErrors(t *testing.T) {
+	dir := t.TempDir()
+	bad := filepath.Join(dir, "bad.json")
+	writeTestFile(t, bad, "{not json")
+	missing := filepath.Join(dir, "missing.json")
+	t.Setenv(strings.ToUpper(testPrefix+testKey1), "one")
+	t.Setenv(strings.ToUpper(testPrefix+testKey2), "two")
+
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	f.StringSlice(FileArgName, nil, testNoHelpMessage)
+	args := []string{
+		fmt.Sprintf("--%s=%s", FileArgName, bad),
+		fmt.Sprintf("--%s=%s", FileArgName, missing),
+	}
+	if err := f.Parse(args); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+
+	for _, all := range []bool{false, true} {
+		t.Run(fmt.Sprintf("all=%v", all), func(t *testing.T) {
+			var opts []Option
+			if all {
+				opts = append(opts, WithAllErrors())
+			}
+			c, err := New(testPrefix, testDelimiter, opts...)
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+
+			var cfg testAllErrorsConfig
+			err = c.Load(f, &cfg)
+			var le *LoadErrors
+			if got := errors.As(err, &le); got != all {
+				t.Fatalf("Load err: got=%v want *LoadErrors=%v", err, all)
+			}
+			if !all {
+				return
+			}
+
+			var files []string
+			var keys []string
+			for _, err := range le.Errors {
+				var fe *FileLoadError
+				var ue *UnmarshalError
+				switch {
+				case errors.As(err, &fe):
+					files = append(files, fe.File)
+				case errors.As(err, &ue):
+					keys = append(keys, ue.Key)
+				}
+			}
+			if got, want := strings.Join(files, ","), bad+","+missing; got != want {
+				t.Errorf("files: got=%q want=%q", got, want)
+			}
+			if got, want := strings.Join(keys, ","), testKey1+","+testKey2; got != want {
+				t.Errorf("keys: got=%q want=%q", got, want)
+			}
+			if !errors.Is(err, MissingRequiredError) {
+				t.Errorf("Load err: got=%v want wrapping %v", err, MissingRequiredError)
+			}
+		})
+	}
+}
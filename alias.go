@@ -0,0 +1,123 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// alias is the key an old key was renamed to.
+type alias struct {
+	key string
+	// warn is set to log a warning when the old key is used.
+	warn bool
+}
+
+// WithAliases renames keys in every source, so that a key can be renamed
+// without breaking existing config files, environment variables and flags. The
+// map is from old keys to new keys. An old key also renames the keys below it,
+// so that the alias "database" to "db" loads database.host as db.host. If a
+// source supplies both, the new key is used. Keys are matched ignoring case.
+func WithAliases(aliases map[string]string) Option {
+	return withAliases(aliases, false)
+}
+
+// WithDeprecatedAliases is like WithAliases, but also logs a warning naming the
+// source, the old key and the new key when an old key is used.
+func WithDeprecatedAliases(aliases map[string]string) Option {
+	return withAliases(aliases, true)
+}
+
+func withAliases(aliases map[string]string, warn bool) Option {
+	return func(c *Config) {
+		if c.aliases == nil {
+			c.aliases = make(map[string]alias)
+		}
+		for old, key := range aliases {
+			c.aliases[strings.ToLower(old)] = alias{key: key, warn: warn}
+		}
+	}
+}
+
+// aliasKey returns the new key for key and whether key is, or is below, an old
+// key. The longest matching old key wins.
+func (c Config) aliasKey(key string) (alias, bool) {
+	lower := strings.ToLower(key)
+	if a, ok := c.aliases[lower]; ok {
+		return a, true
+	}
+	best := ""
+	for old := range c.aliases {
+		if strings.HasPrefix(lower, old+c.delimiter) && len(old) > len(best) {
+			best = old
+		}
+	}
+	if best == "" {
+		return alias{}, false
+	}
+	a := c.aliases[best]
+	a.key += key[len(best):]
+	return a, true
+}
+
+// renameAliases returns k, supplied by the layer ly, with its old keys
+// renamed. Values supplied under the new key take precedence.
+func (c Config) renameAliases(ly layer, k *koanf.Koanf) (*koanf.Koanf, error) {
+	if len(c.aliases) == 0 {
+		return k, nil
+	}
+	all := k.All()
+	keys := make([]string, 0, len(all))
+	for key := range all {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	renamed := make(map[string]interface{}, len(all))
+	found := false
+	for _, key := range keys {
+		v := all[key]
+		a, ok := c.aliasKey(key)
+		if !ok {
+			renamed[key] = v
+			continue
+		}
+		found = true
+		if a.warn {
+			c.logf("%s: key %q is deprecated, use %q", ly.desc, key, a.key)
+		}
+		if _, ok := all[a.key]; !ok {
+			renamed[a.key] = v
+		}
+	}
+	if !found {
+		return k, nil
+	}
+	out := koanf.New(k.Delim())
+	if err := out.Load(mapProvider{m: renamed, delim: k.Delim()}, nil); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
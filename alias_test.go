@@ -0,0 +1,125 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"bytes"
+	"log"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/pflag"
+)
+
+type testAliasDB struct {
+	Host string `koanf:"host"`
+	Port int    `koanf:"port"`
+}
+
+type testAliasConfig struct {
+	Name string      `koanf:"name"`
+	DB   testAliasDB `koanf:"db"`
+}
+
+func TestAliases(t *testing.T) {
+	aliases := map[string]string{"title": "name", "database": "db"}
+	cases := []struct {
+		name    string
+		file    string
+		env     map[string]string
+		args    []string
+		want    testAliasConfig
+		wantLog string
+	}{
+		{
+			name: "file",
+			file: "title: app\ndatabase:\n  host: h\n  port: 1\n",
+			want: testAliasConfig{Name: "app", DB: testAliasDB{Host: "h", Port: 1}},
+			wantLog: "file: key \"database.host\" is deprecated, use \"db.host\"\n" +
+				"file: key \"database.port\" is deprecated, use \"db.port\"\n" +
+				"file: key \"title\" is deprecated, use \"name\"\n",
+		},
+		{
+			name:    "new key wins",
+			file:    "title: old\nname: new\n",
+			want:    testAliasConfig{Name: "new"},
+			wantLog: "file: key \"title\" is deprecated, use \"name\"\n",
+		},
+		{
+			name:    "env overrides file",
+			file:    "name: file\n",
+			env:     map[string]string{testPrefix + "TITLE": "env"},
+			want:    testAliasConfig{Name: "env"},
+			wantLog: "env: key \"title\" is deprecated, use \"name\"\n",
+		},
+		{
+			name:    "flag",
+			file:    "db:\n  host: file\n",
+			args:    []string{"--title=flag"},
+			want:    testAliasConfig{Name: "flag", DB: testAliasDB{Host: "file"}},
+			wantLog: "flags: flag --title is deprecated, use \"name\"\n",
+		},
+		{
+			name: "unchanged flag",
+			file: "name: file\n",
+			want: testAliasConfig{Name: "file"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			name := filepath.Join(t.TempDir(), "config.yaml")
+			writeTestFile(t, name, tc.file)
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			AddConfigFlag(f)
+			f.String("title", "default", "old name of name")
+			if err := f.Parse(append([]string{"--" + FileArgName + "=" + name}, tc.args...)); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+			var buf bytes.Buffer
+			c, err := New(testPrefix, testDelimiter, WithDeprecatedAliases(aliases), WithLogger(log.New(&buf, "", 0)))
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+
+			var got testAliasConfig
+			if err := c.Load(f, &got); err != nil {
+				t.Fatalf("Load err: got=%v want=nil", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Load mismatch (-want +got):\n%s", diff)
+			}
+			gotLog := buf.String()
+			if tc.file != "" {
+				gotLog = strings.ReplaceAll(gotLog, "file "+name, "file")
+			}
+			if diff := cmp.Diff(tc.wantLog, gotLog); diff != "" {
+				t.Errorf("log mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// WithBaseDir makes relative config file paths resolve against dir instead of
+// the working directory of the process, which differs between, for example,
+// systemd units and containers. dir is typically the directory of the binary
+// or a data directory chosen by the application.
+func WithBaseDir(dir string) Option {
+	return func(c *Config) {
+		c.baseDir = dir
+	}
+}
+
+// resolveFile returns the path of the local config file name, resolved against
+// the base directory if it is relative. URLs are returned unchanged.
+func (c Config) resolveFile(name string) string {
+	if c.baseDir == "" || strings.Contains(name, "://") || filepath.IsAbs(name) || isStdin(name) {
+		return c.fsPath(name)
+	}
+	return c.fsPath(filepath.Join(c.baseDir, name))
+}
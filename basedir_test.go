@@ -0,0 +1,84 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestWithBaseDir(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "config.yaml"), fmt.Sprintf("%s: %d\n", testKey1, testValue1))
+
+	cases := []struct {
+		name string
+		file string
+	}{
+		{name: "relative", file: "config.yaml"},
+		{name: "absolute", file: filepath.Join(dir, "config.yaml")},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			AddConfigFlag(f)
+			if err := f.Parse([]string{fmt.Sprintf("--%s=%s", FileArgName, tc.file)}); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+			c, err := New(testPrefix, testDelimiter, WithBaseDir(dir))
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+
+			var cfg testConfig
+			if err := c.Load(f, &cfg); err != nil {
+				t.Fatalf("Load err: got=%v want=nil", err)
+			}
+			if got, want := cfg.Value1, testValue1; got != want {
+				t.Errorf("Value1: got=%d want=%d", got, want)
+			}
+		})
+	}
+}
+
+func TestResolveFile(t *testing.T) {
+	c := Config{baseDir: "/base"}
+	cases := []struct {
+		name string
+		want string
+	}{
+		{"config.yaml", filepath.Join("/base", "config.yaml")},
+		{"sub/config.yaml", filepath.Join("/base", "sub", "config.yaml")},
+		{string(os.PathSeparator) + "abs.yaml", string(os.PathSeparator) + "abs.yaml"},
+		{"https://example.com/config.yaml", "https://example.com/config.yaml"},
+	}
+	for _, tc := range cases {
+		if got := c.resolveFile(tc.name); got != tc.want {
+			t.Errorf("resolveFile(%q): got=%q want=%q", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/knadh/koanf/maps"
+	"github.com/knadh/koanf/v2"
+)
+
+// cacheVersion is stored in cache files. Changing it invalidates every
+// existing cache.
+const cacheVersion = 2
+
+func init() {
+	// Types that appear inside parsed configuration values.
+	gob.Register(map[string]interface{}{})
+	gob.Register([]interface{}{})
+	gob.Register(time.Time{})
+	// Blocks parsed from HCL.
+	gob.Register([]map[string]interface{}{})
+	gob.Register(cacheNull{})
+}
+
+// cacheNull stands for a nil value, such as an empty YAML value, in the
+// cache, since gob cannot encode nil interface values.
+type cacheNull struct{}
+
+// toCache returns a copy of v with nil values replaced by cacheNull, so that
+// gob can encode it.
+func toCache(v interface{}) interface{} {
+	switch v := v.(type) {
+	case nil:
+		return cacheNull{}
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[k] = toCache(e)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, e := range v {
+			s[i] = toCache(e)
+		}
+		return s
+	case []map[string]interface{}:
+		s := make([]map[string]interface{}, len(v))
+		for i, e := range v {
+			s[i] = toCache(e).(map[string]interface{})
+		}
+		return s
+	}
+	return v
+}
+
+// fromCache returns a copy of v, read from the cache, with cacheNull replaced
+// by nil.
+func fromCache(v interface{}) interface{} {
+	switch v := v.(type) {
+	case cacheNull:
+		return nil
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[k] = fromCache(e)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, e := range v {
+			s[i] = fromCache(e)
+		}
+		return s
+	case []map[string]interface{}:
+		s := make([]map[string]interface{}, len(v))
+		for i, e := range v {
+			s[i] = fromCache(e).(map[string]interface{})
+		}
+		return s
+	}
+	return v
+}
+
+// WithCache makes Load keep the parsed contents of local config files in the
+// file named by path. A config file whose contents have not changed since it
+// was cached is not parsed again, which reduces the start up time of processes
+// that load large configurations repeatedly, for example in CI or serverless
+// functions. The environment, flags and remote config files are always read.
+//
+// Failing to read or write the cache is not an error: the files are parsed and
+// the failure is logged. A file whose values cannot be cached is logged and
+// parsed on every Load, without keeping the other files from being cached.
+func WithCache(path string) Option {
+	return func(c *Config) {
+		c.cachePath = path
+	}
+}
+
+// parseCache holds the parsed contents of config files keyed by file name.
+type parseCache struct {
+	Version int
+	Files   map[string]cacheEntry
+	// dirty is set when Files has changed since it was read.
+	dirty bool
+}
+
+// cacheEntry is the parsed contents of a config file.
+type cacheEntry struct {
+	Hash   [sha256.Size]byte
+	Values map[string]interface{}
+}
+
+// readCache returns the cache stored in path, or an empty one if it does not
+// exist or cannot be read.
+func (c Config) readCache(path string) *parseCache {
+	empty := &parseCache{Version: cacheVersion, Files: make(map[string]cacheEntry)}
+	f, err := os.Open(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			c.logf("read cache: %v", err)
+		}
+		return empty
+	}
+	defer f.Close()
+
+	var pc parseCache
+	if err := gob.NewDecoder(bufio.NewReader(f)).Decode(&pc); err != nil {
+		c.logf("read cache %s: %v", path, err)
+		return empty
+	}
+	if pc.Version != cacheVersion || pc.Files == nil {
+		return empty
+	}
+	return &pc
+}
+
+// write stores pc in path if it has changed. The file is replaced atomically
+// so that concurrent processes never read a partial cache.
+func (pc *parseCache) write(path string) error {
+	if !pc.dirty {
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(pc); err != nil {
+		return fmt.Errorf("encode: %w", err)
+	}
+	if err := writeAtomic(path, buf.Bytes(), 0o600); err != nil {
+		return err
+	}
+	pc.dirty = false
+	return nil
+}
+
+// cachedProvider returns a provider that parses the local config file named
+// by name using the cache, and whether the cache applies to name.
+func (c Config) cachedProvider(l *loaded, name string) (koanf.Provider, bool, error) {
+	if l.cache == nil || c.fsys != nil || isStdin(name) || strings.Contains(name, "://") {
+		return nil, false, nil
+	}
+	if err := c.policy.checkScheme(FileScheme); err != nil {
+		return nil, false, err
+	}
+	parser, err := c.parserFor(name)
+	if err != nil {
+		return nil, false, err
+	}
+	return cachedFileProvider{name: name, parser: parser, cache: l.cache, logf: c.logf}, true, nil
+}
+
+// cachedFileProvider is a koanf.Provider that reads a local config file and
+// parses it unless its parsed contents are cached.
+type cachedFileProvider struct {
+	name   string
+	parser koanf.Parser
+	cache  *parseCache
+	logf   func(format string, v ...interface{})
+}
+
+// ReadBytes is not supported because the file is read as a map.
+func (cachedFileProvider) ReadBytes() ([]byte, error) {
+	return nil, errors.New("cachedFileProvider does not support ReadBytes")
+}
+
+// Read returns the parsed contents of the file.
+func (p cachedFileProvider) Read() (map[string]interface{}, error) {
+	b, err := os.ReadFile(p.name)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(b)
+	// Copies are returned because merging modifies the nested maps.
+	if e, ok := p.cache.Files[p.name]; ok && e.Hash == hash {
+		return fromCache(e.Values).(map[string]interface{}), nil
+	}
+	m, err := p.parser.Unmarshal(b)
+	if err != nil {
+		return nil, err
+	}
+	e := cacheEntry{Hash: hash, Values: toCache(m).(map[string]interface{})}
+	// An entry that cannot be encoded would stop the whole cache from being
+	// written, so it is left out.
+	if err := gob.NewEncoder(io.Discard).Encode(e); err != nil {
+		p.logf("cache %s: encode: %v", p.name, err)
+		return m, nil
+	}
+	p.cache.Files[p.name] = e
+	p.cache.dirty = true
+	return maps.Copy(m), nil
+}
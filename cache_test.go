@@ -0,0 +1,130 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/pflag"
+)
+
+func TestLoadWithCache(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "config.yaml")
+	cachePath := filepath.Join(dir, "config.cache")
+	writeTestFile(t, name, fmt.Sprintf("%s: %d\n", testKey1, testValue1))
+
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	f.StringSlice(FileArgName, nil, testNoHelpMessage)
+	if err := f.Parse([]string{fmt.Sprintf("--%s=%s", FileArgName, name)}); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	c, err := New(testPrefix, testDelimiter, WithCache(cachePath))
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	load := func() int {
+		t.Helper()
+		var cfg testConfig
+		if err := c.Load(f, &cfg); err != nil {
+			t.Fatalf("Load err: got=%v want=nil", err)
+		}
+		return cfg.Value1
+	}
+
+	if got, want := load(), testValue1; got != want {
+		t.Errorf("first Load Value1: got=%d want=%d", got, want)
+	}
+
+	// Replace the cached value without changing the file, so the next Load
+	// only sees it if the cache is used.
+	pc := c.readCache(cachePath)
+	e, ok := pc.Files[name]
+	if !ok {
+		t.Fatalf("cache has no entry for %s", name)
+	}
+	e.Values = map[string]interface{}{testKey1: testValue3}
+	pc.Files[name], pc.dirty = e, true
+	if err := pc.write(cachePath); err != nil {
+		t.Fatalf("write failed unexpectedly: %v", err)
+	}
+	if got, want := load(), testValue3; got != want {
+		t.Errorf("cached Load Value1: got=%d want=%d", got, want)
+	}
+
+	writeTestFile(t, name, fmt.Sprintf("%s: %d\n", testKey1, testValue2))
+	if got, want := load(), testValue2; got != want {
+		t.Errorf("changed Load Value1: got=%d want=%d", got, want)
+	}
+}
+
+func TestLoadWithCacheHCL(t *testing.T) {
+	dir := t.TempDir()
+	hclName := filepath.Join(dir, "app.hcl")
+	yamlName := filepath.Join(dir, "config.yaml")
+	cachePath := filepath.Join(dir, "config.cache")
+	// Repeated blocks are parsed as a list of maps, and an empty YAML value
+	// as nil, neither of which gob can encode as is.
+	writeTestFile(t, hclName, `svc "a" { port = 1 }
+svc "b" { port = 2 }
+`)
+	writeTestFile(t, yamlName, "empty:\n")
+
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	f.StringSlice(FileArgName, nil, testNoHelpMessage)
+	if err := f.Parse([]string{fmt.Sprintf("--%s=%s,%s", FileArgName, hclName, yamlName)}); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	var buf bytes.Buffer
+	c, err := New(testPrefix, testDelimiter, WithCache(cachePath), WithLogger(log.New(&buf, "", 0)))
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	want, err := c.All(f)
+	if err != nil {
+		t.Fatalf("first All err: got=%v want=nil", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("log: got=%q want empty", buf.String())
+	}
+	pc := c.readCache(cachePath)
+	for _, name := range []string{hclName, yamlName} {
+		if _, ok := pc.Files[name]; !ok {
+			t.Errorf("cache has no entry for %s", name)
+		}
+	}
+
+	got, err := c.All(f)
+	if err != nil {
+		t.Fatalf("cached All err: got=%v want=nil", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("cached All mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,81 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"reflect"
+	"sort"
+)
+
+// KeyChange describes a configuration key whose value changed during a
+// reload. Old is nil if the key was added and New is nil if it was removed.
+// Source names the layer that supplied New, or Old if the key was removed:
+// a config file, a Source name, SourceEnv or SourceFlags. The values of fields
+// tagged secret are RedactedValue.
+type KeyChange struct {
+	Path   string
+	Old    interface{}
+	New    interface{}
+	Source string
+}
+
+// WithOnKeyChange sets a function that is called with the keys that changed
+// every time Watch or ReloadOnSignal successfully reloads the configuration
+// and the change was accepted by onChange. It is not called if nothing
+// changed.
+func WithOnKeyChange(fn func(changes []KeyChange)) Option {
+	return func(c *Config) {
+		c.onKeyChange = fn
+	}
+}
+
+// keyChanges returns the keys whose values differ between old and new, sorted
+// by path.
+func keyChanges(old, new *loaded) []KeyChange {
+	oldValues, newValues := old.k.All(), new.k.All()
+
+	var changes []KeyChange
+	for path, nv := range newValues {
+		ov, ok := oldValues[path]
+		if ok && reflect.DeepEqual(ov, nv) {
+			continue
+		}
+		changes = append(changes, KeyChange{
+			Path:   path,
+			Old:    new.redact(path, ov),
+			New:    new.redact(path, nv),
+			Source: new.sources[path],
+		})
+	}
+	for path, ov := range oldValues {
+		if _, ok := newValues[path]; !ok {
+			changes = append(changes, KeyChange{
+				Path:   path,
+				Old:    old.redact(path, ov),
+				Source: old.sources[path],
+			})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
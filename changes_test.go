@@ -0,0 +1,70 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/pflag"
+)
+
+func Test_keyChanges(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "config.json")
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	f.StringSlice(FileArgName, nil, testNoHelpMessage)
+	if err := f.Parse([]string{fmt.Sprintf("--%s=%s", FileArgName, name)}); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	writeTestFile(t, name, fmt.Sprintf(`{"%s": %d, "%s": %d}`, testKey1, testValue1, testKey2, testValue2))
+	old, err := c.merge(context.Background(), f, nil)
+	if err != nil {
+		t.Fatalf("merge failed unexpectedly: %v", err)
+	}
+
+	writeTestFile(t, name, fmt.Sprintf(`{"%s": %d, "%s": %d}`, testKey1, testValue1, testKey3, testValue3))
+	t.Setenv(strings.ToUpper(testPrefix+testKey1), "from env")
+	new, err := c.merge(context.Background(), f, nil)
+	if err != nil {
+		t.Fatalf("merge failed unexpectedly: %v", err)
+	}
+
+	want := []KeyChange{
+		{Path: testKey1, Old: float64(testValue1), New: "from env", Source: SourceEnv},
+		{Path: testKey2, Old: float64(testValue2), Source: name},
+		{Path: testKey3, New: float64(testValue3), Source: name},
+	}
+	if diff := cmp.Diff(want, keyChanges(old, new)); diff != "" {
+		t.Errorf("keyChanges mismatch (-want +got):\n%s", diff)
+	}
+}
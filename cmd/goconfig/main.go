@@ -0,0 +1,160 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Command goconfig checks, converts and merges config files without writing
+// Go. The structure of the configuration is read from a JSON Schema written by
+// Config.JSONSchema, so a program can export it, for example with a hidden
+// flag, for the people who operate it:
+//
+//	$ goconfig validate --schema=schema.json base.yaml prod.yaml
+//	$ goconfig dump --schema=schema.json --format=json base.yaml prod.yaml
+//	$ goconfig convert --to=yaml config.json
+//
+// Files are JSON or YAML, selected by their extension, and are merged in
+// order, later files taking precedence, as Load does.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bretmckee/goconfig"
+	kjson "github.com/knadh/koanf/parsers/json"
+	kyaml "github.com/knadh/koanf/parsers/yaml"
+	"github.com/knadh/koanf/providers/file"
+	"github.com/knadh/koanf/v2"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	UsageError   = errors.New("usage: goconfig validate|dump|convert [flags] files...")
+	InvalidError = errors.New("configuration is not valid")
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// run runs the command named by args[0] with the remaining args, writing its
+// output to out.
+func run(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return UsageError
+	}
+	cmd, args := args[0], args[1:]
+	f := pflag.NewFlagSet("goconfig "+cmd, pflag.ContinueOnError)
+	schemaFile := f.String("schema", "", "JSON Schema describing the configuration")
+	format := goconfig.FormatYAML
+	switch cmd {
+	case "validate":
+	case "dump":
+		f.StringVar(&format, "format", format, "output format, yaml or json")
+	case "convert":
+		f.StringVar(&format, "to", format, "output format, yaml or json")
+	default:
+		return fmt.Errorf("unknown command %q: %w", cmd, UsageError)
+	}
+	if err := f.Parse(args); err != nil {
+		return err
+	}
+	files := f.Args()
+	if len(files) == 0 || cmd == "convert" && len(files) != 1 {
+		return UsageError
+	}
+
+	m, err := merge(files)
+	if err != nil {
+		return err
+	}
+	var s *schema
+	if *schemaFile != "" {
+		if s, err = readSchema(*schemaFile); err != nil {
+			return err
+		}
+	}
+
+	switch cmd {
+	case "validate":
+		if problems := s.check("", m); len(problems) > 0 {
+			return fmt.Errorf("%s: %w", strings.Join(problems, "; "), InvalidError)
+		}
+		fmt.Fprintf(out, "%s: ok\n", strings.Join(files, ", "))
+		return nil
+	case "dump":
+		s.applyDefaults(m)
+	}
+	b, err := marshal(m, format)
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(b)
+	return err
+}
+
+// merge returns the values of files merged in order.
+func merge(files []string) (map[string]interface{}, error) {
+	k := koanf.New(".")
+	for _, name := range files {
+		var p koanf.Parser
+		switch strings.ToLower(filepath.Ext(name)) {
+		case ".yaml", ".yml":
+			p = kyaml.Parser()
+		case ".json":
+			p = kjson.Parser()
+		default:
+			return nil, fmt.Errorf("%s: %w", name, goconfig.UnknownFormatError)
+		}
+		if err := k.Load(file.Provider(name), p); err != nil {
+			return nil, fmt.Errorf("load %s: %w", name, err)
+		}
+	}
+	return k.Raw(), nil
+}
+
+// marshal encodes m in format.
+func marshal(m map[string]interface{}, format string) ([]byte, error) {
+	switch strings.ToLower(format) {
+	case goconfig.FormatYAML, "yml":
+		var buf bytes.Buffer
+		e := yaml.NewEncoder(&buf)
+		e.SetIndent(2)
+		if err := e.Encode(m); err != nil {
+			return nil, err
+		}
+		err := e.Close()
+		return buf.Bytes(), err
+	case goconfig.FormatJSON:
+		b, err := json.MarshalIndent(m, "", "  ")
+		return append(b, '\n'), err
+	}
+	return nil, fmt.Errorf("format %q: %w", format, goconfig.UnknownFormatError)
+}
@@ -0,0 +1,171 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bretmckee/goconfig"
+	"github.com/google/go-cmp/cmp"
+)
+
+type testConfig struct {
+	Name  string `koanf:"name,required"`
+	Port  int    `koanf:"port" default:"8080"`
+	Level string `koanf:"level" oneof:"debug,info"`
+	DB    struct {
+		Host string `koanf:"host,required"`
+	} `koanf:"db"`
+}
+
+func writeFile(t *testing.T, name, data string) string {
+	t.Helper()
+	if err := os.WriteFile(name, []byte(data), 0o600); err != nil {
+		t.Fatalf("WriteFile(%q) failed unexpectedly: %v", name, err)
+	}
+	return name
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	c, err := goconfig.New("APP_", ".")
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	b, err := c.JSONSchema(testConfig{})
+	if err != nil {
+		t.Fatalf("JSONSchema failed unexpectedly: %v", err)
+	}
+	schema := "--schema=" + writeFile(t, filepath.Join(dir, "schema.json"), string(b))
+	base := writeFile(t, filepath.Join(dir, "base.yaml"), "name: app\ndb:\n  host: localhost\n")
+	prod := writeFile(t, filepath.Join(dir, "prod.json"), `{"port": "9090", "level": "info"}`)
+	bad := writeFile(t, filepath.Join(dir, "bad.yaml"), "nmae: app\nport: x\nlevel: trace\n")
+
+	cases := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "validate",
+			args: []string{"validate", schema, base, prod},
+			want: base + ", " + prod + ": ok\n",
+		},
+		{
+			name:    "validate invalid",
+			args:    []string{"validate", schema, bad},
+			wantErr: InvalidError,
+		},
+		{
+			name: "validate without schema",
+			args: []string{"validate", bad},
+			want: bad + ": ok\n",
+		},
+		{
+			name: "dump",
+			args: []string{"dump", schema, base},
+			want: "db:\n  host: localhost\nname: app\nport: 8080\n",
+		},
+		{
+			name: "dump json",
+			args: []string{"dump", "--format=json", base, prod},
+			want: `{
+  "db": {
+    "host": "localhost"
+  },
+  "level": "info",
+  "name": "app",
+  "port": "9090"
+}
+`,
+		},
+		{
+			name: "convert",
+			args: []string{"convert", "--to=yaml", prod},
+			want: "level: info\nport: \"9090\"\n",
+		},
+		{
+			name:    "convert unknown format",
+			args:    []string{"convert", "--to=toml", prod},
+			wantErr: goconfig.UnknownFormatError,
+		},
+		{
+			name:    "convert two files",
+			args:    []string{"convert", base, prod},
+			wantErr: UsageError,
+		},
+		{
+			name:    "unknown command",
+			args:    []string{"lint", base},
+			wantErr: UsageError,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := run(tc.args, &out)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("run(%q) err: got=%v want=%v", tc.args, err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, out.String()); diff != "" {
+				t.Errorf("run(%q) mismatch (-want +got):\n%s", tc.args, diff)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	s, err := readSchema(writeFile(t, filepath.Join(t.TempDir(), "schema.json"), `{
+  "type": "object",
+  "properties": {
+    "level": {"type": "string", "enum": ["debug", "info"]},
+    "port": {"type": "integer"},
+    "hosts": {"type": "array", "items": {"type": "string"}},
+    "db": {"type": "object", "properties": {"host": {"type": "string"}}, "required": ["host"]}
+  },
+  "required": ["port"]
+}`))
+	if err != nil {
+		t.Fatalf("readSchema failed unexpectedly: %v", err)
+	}
+	got := s.check("", map[string]interface{}{
+		"nmae":  "app",
+		"level": "trace",
+		"hosts": []interface{}{"a", map[string]interface{}{}},
+	})
+	want := []string{
+		`hosts[1]: map[] is not a string`,
+		`level: "trace" not in [debug, info]`,
+		`nmae: unknown key`,
+		`port: missing required key`,
+		`db.host: missing required key`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("check mismatch (-want +got):\n%s", diff)
+	}
+}
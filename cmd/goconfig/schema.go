@@ -0,0 +1,266 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// schema is the subset of JSON Schema written by Config.JSONSchema.
+type schema struct {
+	Type                 string             `json:"type"`
+	Enum                 []interface{}      `json:"enum"`
+	Default              interface{}        `json:"default"`
+	Properties           map[string]*schema `json:"properties"`
+	Required             []string           `json:"required"`
+	Items                *schema            `json:"items"`
+	AdditionalProperties *schema            `json:"additionalProperties"`
+}
+
+// readSchema reads the schema in the file name.
+func readSchema(name string) (*schema, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	var s schema
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("schema %s: %w", name, err)
+	}
+	return &s, nil
+}
+
+// property returns the schema of the property name of s, which is matched
+// ignoring case as Load does.
+func (s *schema) property(name string) (*schema, bool) {
+	if p, ok := s.Properties[name]; ok {
+		return p, true
+	}
+	for k, p := range s.Properties {
+		if strings.EqualFold(k, name) {
+			return p, true
+		}
+	}
+	if s.AdditionalProperties != nil {
+		return s.AdditionalProperties, true
+	}
+	return nil, false
+}
+
+// check returns a description of every problem with v, the value of key. A
+// nil schema accepts every value. Values that Load converts, such as the
+// string "8080" for an integer, are accepted.
+func (s *schema) check(key string, v interface{}) []string {
+	if s == nil {
+		return nil
+	}
+	at := key
+	if at == "" {
+		at = "configuration"
+	}
+	if !s.accepts(v) {
+		return []string{fmt.Sprintf("%s: %v is not %s", at, v, article(s.Type))}
+	}
+	if len(s.Enum) > 0 {
+		if p, ok := s.checkEnum(at, v); !ok {
+			return []string{p}
+		}
+	}
+
+	var problems []string
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if s.Type != "object" {
+			break
+		}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			p, ok := s.property(name)
+			if !ok {
+				problems = append(problems, fmt.Sprintf("%s: unknown key", join(key, name)))
+				continue
+			}
+			problems = append(problems, p.check(join(key, name), v[name])...)
+		}
+		problems = append(problems, s.checkRequired(key, v)...)
+	case []interface{}:
+		for i, e := range v {
+			problems = append(problems, s.Items.check(fmt.Sprintf("%s[%d]", at, i), e)...)
+		}
+	}
+	return problems
+}
+
+// checkRequired returns a description of every required key below key that is
+// not in m, including the keys of nested objects missing from m.
+func (s *schema) checkRequired(key string, m map[string]interface{}) []string {
+	var problems []string
+	for _, name := range s.Required {
+		if _, ok := lookup(m, name); !ok {
+			problems = append(problems, fmt.Sprintf("%s: missing required key", join(key, name)))
+		}
+	}
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if p := s.Properties[name]; p.Type == "object" {
+			if _, ok := lookup(m, name); !ok {
+				problems = append(problems, p.checkRequired(join(key, name), nil)...)
+			}
+		}
+	}
+	return problems
+}
+
+// checkEnum returns a description of the problem if v, the value at at, is not
+// one of the values of s.Enum.
+func (s *schema) checkEnum(at string, v interface{}) (string, bool) {
+	allowed := make([]string, len(s.Enum))
+	for i, e := range s.Enum {
+		allowed[i] = fmt.Sprint(e)
+	}
+	for _, a := range allowed {
+		if a == fmt.Sprint(v) {
+			return "", true
+		}
+	}
+	return fmt.Sprintf("%s: %q not in [%s]", at, fmt.Sprint(v), strings.Join(allowed, ", ")), false
+}
+
+// accepts reports whether Load can convert v to the type of s.
+func (s *schema) accepts(v interface{}) bool {
+	str, isString := v.(string)
+	switch s.Type {
+	case "object":
+		_, ok := v.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := v.([]interface{})
+		return ok || isString
+	case "string":
+		switch v.(type) {
+		case map[string]interface{}, []interface{}:
+			return false
+		}
+		return true
+	case "boolean":
+		if isString {
+			_, err := strconv.ParseBool(str)
+			return err == nil
+		}
+		_, ok := v.(bool)
+		return ok
+	case "integer":
+		switch v := v.(type) {
+		case int, int64, uint64:
+			return true
+		case float64:
+			return v == float64(int64(v))
+		}
+		if isString {
+			_, err := strconv.ParseInt(str, 0, 64)
+			return err == nil
+		}
+		return false
+	case "number":
+		switch v.(type) {
+		case int, int64, uint64, float64:
+			return true
+		}
+		if isString {
+			_, err := strconv.ParseFloat(str, 64)
+			return err == nil
+		}
+		return false
+	}
+	return true
+}
+
+// applyDefaults sets the keys of m, and of the objects it contains, that are
+// not set to their defaults.
+func (s *schema) applyDefaults(m map[string]interface{}) {
+	if s == nil {
+		return
+	}
+	for name, p := range s.Properties {
+		v, ok := lookup(m, name)
+		switch {
+		case p.Type == "object" && p.Properties != nil:
+			child, isMap := v.(map[string]interface{})
+			if !ok {
+				child, isMap = make(map[string]interface{}), true
+			}
+			if !isMap {
+				continue
+			}
+			p.applyDefaults(child)
+			if !ok && len(child) > 0 {
+				m[name] = child
+			}
+		case !ok && p.Default != nil:
+			m[name] = p.Default
+		}
+	}
+}
+
+// lookup returns the value of name in m, ignoring case.
+func lookup(m map[string]interface{}, name string) (interface{}, bool) {
+	if v, ok := m[name]; ok {
+		return v, true
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// join returns the key name below key.
+func join(key, name string) string {
+	if key == "" {
+		return name
+	}
+	return key + "." + name
+}
+
+// article returns the schema type typ with an indefinite article.
+func article(typ string) string {
+	switch typ {
+	case "object", "array", "integer":
+		return "an " + typ
+	}
+	return "a " + typ
+}
@@ -0,0 +1,81 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"github.com/knadh/koanf/v2"
+)
+
+// SourceCompiled names the compiled in overlay when reporting where values
+// came from.
+const SourceCompiled = "compiled"
+
+// CompiledOverlay is a JSON document that is loaded after every other source,
+// so its values cannot be changed by config files, the environment or flags.
+// It is intended to be set at build time, for example:
+//
+//	go build -ldflags '-X github.com/bretmckee/goconfig.CompiledOverlay={"license":{"url":"https://license.example.com"}}'
+var CompiledOverlay string
+
+// compiledOverlay is an overlay linked into the binary with
+// WithCompiledOverlay.
+type compiledOverlay struct {
+	data   []byte
+	format string
+}
+
+// WithCompiledOverlay sets an overlay, typically a file included with
+// go:embed, that is loaded after every other source, including
+// CompiledOverlay. format is FormatJSON, FormatYAML, FormatHCL or FormatTOML.
+func WithCompiledOverlay(data []byte, format string) Option {
+	return func(c *Config) {
+		c.compiled = &compiledOverlay{data: data, format: format}
+	}
+}
+
+// compiledLayers returns the layers for the compiled in overlays.
+func (c Config) compiledLayers() []layer {
+	overlays := []compiledOverlay{{data: []byte(CompiledOverlay), format: FormatJSON}}
+	if c.compiled != nil {
+		overlays = append(overlays, *c.compiled)
+	}
+	var layers []layer
+	for _, o := range overlays {
+		if len(o.data) == 0 {
+			continue
+		}
+		o := o
+		layers = append(layers, layer{
+			name: SourceCompiled,
+			desc: SourceCompiled,
+			open: func(*loaded) (koanf.Provider, koanf.Parser, error) {
+				parser, err := parserForFormat(o.format)
+				if err != nil {
+					return nil, nil, err
+				}
+				return bytesProvider(func() ([]byte, error) { return o.data, nil }), parser, nil
+			},
+		})
+	}
+	return layers
+}
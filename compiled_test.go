@@ -0,0 +1,88 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestCompiledOverlayWins(t *testing.T) {
+	old := CompiledOverlay
+	CompiledOverlay = fmt.Sprintf(`{"%s": %d}`, testKey1, testValue1)
+	defer func() { CompiledOverlay = old }()
+
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	f.Int(testKey1, testDefaultValue1, testNoHelpMessage)
+	f.Int(testKey2, testDefaultValue2, testNoHelpMessage)
+	args := []string{
+		fmt.Sprintf("--%s=%d", testKey1, testValue3),
+		fmt.Sprintf("--%s=%d", testKey2, testValue3),
+	}
+	if err := f.Parse(args); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+
+	embedded := []byte(fmt.Sprintf("%s: %d\n", testKey2, testValue2))
+	c, err := New(testPrefix, testDelimiter, WithCompiledOverlay(embedded, FormatYAML))
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	var cfg testConfig
+	l, err := c.load(context.Background(), f, &cfg)
+	if err != nil {
+		t.Fatalf("load err: got=%v want=nil", err)
+	}
+	if got, want := cfg.Value1, testValue1; got != want {
+		t.Errorf("Value1: got=%d want=%d", got, want)
+	}
+	if got, want := cfg.Value2, testValue2; got != want {
+		t.Errorf("Value2: got=%d want=%d", got, want)
+	}
+	for _, k := range []string{testKey1, testKey2} {
+		if got, want := l.sources[k], SourceCompiled; got != want {
+			t.Errorf("source of %s: got=%q want=%q", k, got, want)
+		}
+	}
+}
+
+func TestCompiledOverlayBadJSON(t *testing.T) {
+	old := CompiledOverlay
+	CompiledOverlay = "{"
+	defer func() { CompiledOverlay = old }()
+
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	var cfg testConfig
+	if err := c.Load(f, &cfg); err == nil {
+		t.Fatalf("Load: got=nil want=non-nil")
+	}
+}
@@ -0,0 +1,121 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/knadh/koanf/v2"
+)
+
+var (
+	ConflictError = errors.New("config files set conflicting values")
+)
+
+// ConflictMode selects what Load does when config files disagree.
+type ConflictMode int
+
+const (
+	// ConflictIgnore lets the last config file win. This is the default.
+	ConflictIgnore ConflictMode = iota
+	// ConflictWarn logs every conflict and lets the last config file win.
+	ConflictWarn
+	// ConflictFail makes Load fail with ConflictError.
+	ConflictFail
+)
+
+// WithConflicts sets what Load does when more than one config file sets the
+// same key to different scalar values. Config files have equal standing, so
+// when they are maintained separately, for example by different teams, a
+// conflict is usually a mistake. Maps are merged and are not conflicts, and
+// neither are values that override a config file from the environment or
+// flags, from a config file overriding the files it includes, or from the
+// profile files of WithProfiles, which are meant to override the file they
+// belong to.
+func WithConflicts(mode ConflictMode) Option {
+	return func(c *Config) {
+		c.conflicts = mode
+	}
+}
+
+// checkConflicts reports the scalar keys of the file layer ly, read into k,
+// that an earlier file layer set to a different value.
+func (l *loaded) checkConflicts(ly layer, k *koanf.Koanf) error {
+	if l.conflicts == ConflictIgnore || !ly.file {
+		return nil
+	}
+	var problems []string
+	keys := k.Keys()
+	sort.Strings(keys)
+	for _, key := range keys {
+		prev, ok := l.sources[key]
+		if !ok || !l.files[prev] || l.overrides[ly.name][prev] {
+			continue
+		}
+		old, new := l.k.Get(key), k.Get(key)
+		if !isScalar(old) || !isScalar(new) || reflect.DeepEqual(old, new) {
+			continue
+		}
+		problems = append(problems, fmt.Sprintf("%s is %v in %s and %v in %s", key, l.redact(key, old), prev, l.redact(key, new), ly.name))
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	if l.conflicts == ConflictFail {
+		return fmt.Errorf("%s: %w", strings.Join(problems, "; "), ConflictError)
+	}
+	for _, p := range problems {
+		l.logf("Load: conflict: %s", p)
+	}
+	return nil
+}
+
+// overrideFile records that the config file name overrides the config file
+// base, and so the files base overrides.
+func (l *loaded) overrideFile(name, base string) {
+	if l.overrides == nil {
+		l.overrides = make(map[string]map[string]bool)
+	}
+	m := l.overrides[name]
+	if m == nil {
+		m = make(map[string]bool)
+		l.overrides[name] = m
+	}
+	m[base] = true
+	for b := range l.overrides[base] {
+		m[b] = true
+	}
+}
+
+// isScalar reports whether v is a single value rather than a map or list.
+func isScalar(v interface{}) bool {
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return false
+	}
+	return true
+}
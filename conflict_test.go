@@ -0,0 +1,108 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestLoadConflicts(t *testing.T) {
+	cases := []struct {
+		name     string
+		mode     ConflictMode
+		second   string
+		wantErr  bool
+		wantWarn bool
+	}{
+		{
+			name:   "ignore",
+			mode:   ConflictIgnore,
+			second: fmt.Sprintf("%s: %d\n", testKey1, testValue2),
+		},
+		{
+			name:     "warn",
+			mode:     ConflictWarn,
+			second:   fmt.Sprintf("%s: %d\n", testKey1, testValue2),
+			wantWarn: true,
+		},
+		{
+			name:    "fail",
+			mode:    ConflictFail,
+			second:  fmt.Sprintf("%s: %d\n", testKey1, testValue2),
+			wantErr: true,
+		},
+		{
+			name:   "same value",
+			mode:   ConflictFail,
+			second: fmt.Sprintf("%s: %d\n", testKey1, testValue1),
+		},
+		{
+			name:   "different key",
+			mode:   ConflictFail,
+			second: fmt.Sprintf("%s: %d\n", testKey2, testValue2),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			first, second := filepath.Join(dir, "a.yaml"), filepath.Join(dir, "b.yaml")
+			writeTestFile(t, first, fmt.Sprintf("%s: %d\n", testKey1, testValue1))
+			writeTestFile(t, second, tc.second)
+
+			var buf bytes.Buffer
+			defer log.SetOutput(log.Writer())
+			log.SetOutput(&buf)
+
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			f.StringSlice(FileArgName, nil, testNoHelpMessage)
+			args := []string{
+				fmt.Sprintf("--%s=%s", FileArgName, first),
+				fmt.Sprintf("--%s=%s", FileArgName, second),
+			}
+			if err := f.Parse(args); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+			c, err := New(testPrefix, testDelimiter, WithConflicts(tc.mode))
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+
+			var cfg testConfig
+			err = c.Load(f, &cfg)
+			if got := errors.Is(err, ConflictError); got != tc.wantErr {
+				t.Errorf("Load err: got=%v wantErr=%v", err, tc.wantErr)
+			}
+			if got := strings.Contains(buf.String(), "conflict"); got != tc.wantWarn {
+				t.Errorf("warning: got=%q wantWarn=%v", buf.String(), tc.wantWarn)
+			}
+		})
+	}
+}
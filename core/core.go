@@ -0,0 +1,229 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package core holds the reflection and decoding core of goconfig: the
+// struct tag conventions and the conversion of parsed values into a
+// configuration struct. It does not use the os or flag packages, so it can be
+// built by TinyGo for firmware that reads its configuration from a byte
+// slice. The file, environment and flag pipeline is in package goconfig.
+package core
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/knadh/koanf/maps"
+	"github.com/mitchellh/mapstructure"
+)
+
+var (
+	NotStructError = errors.New("configuration must be a struct or a pointer to a struct")
+)
+
+// Struct tags understood by goconfig.
+const (
+	// TagName names configuration keys.
+	TagName = "koanf"
+	// SecretTagName marks fields containing credentials with "true". The
+	// koanf tag option secret, as in `koanf:"password,secret"`, does the
+	// same.
+	SecretTagName = "secret"
+)
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// Parser parses bytes into a nested map. Every koanf.Parser is a Parser.
+type Parser interface {
+	Unmarshal(b []byte) (map[string]interface{}, error)
+}
+
+// Decode parses b with p and stores the result in cfg, which must be a
+// pointer to a struct.
+func Decode(b []byte, p Parser, cfg interface{}) error {
+	m, err := p.Unmarshal(b)
+	if err != nil {
+		return fmt.Errorf("Decode: %w", err)
+	}
+	return Unmarshal(m, cfg)
+}
+
+// Unmarshal stores the nested map m in cfg, which must be a pointer to a
+// struct, converting values the same way goconfig.Load does.
+func Unmarshal(m map[string]interface{}, cfg interface{}) error {
+	if _, err := StructValue(cfg); err != nil {
+		return err
+	}
+	d, err := mapstructure.NewDecoder(DecoderConfig(cfg))
+	if err != nil {
+		return err
+	}
+	return d.Decode(m)
+}
+
+// UnmarshalFlat stores m, whose keys are delimited by delimiter, in cfg.
+func UnmarshalFlat(m map[string]interface{}, delimiter string, cfg interface{}) error {
+	return Unmarshal(maps.Unflatten(m, delimiter), cfg)
+}
+
+// DecoderConfig returns the mapstructure configuration that decodes into
+// out.
+func DecoderConfig(out interface{}) *mapstructure.DecoderConfig {
+	return &mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			mapstructure.StringToTimeDurationHookFunc(),
+			mapstructure.StringToSliceHookFunc(","),
+			mapstructure.TextUnmarshallerHookFunc()),
+		Result:           out,
+		WeaklyTypedInput: true,
+		TagName:          TagName,
+	}
+}
+
+// FieldName returns the configuration key segment for sf and whether sf is a
+// configuration field. Fields must be exported and have a koanf tag.
+func FieldName(sf reflect.StructField) (string, bool) {
+	if !sf.IsExported() {
+		return "", false
+	}
+	name, _, _ := strings.Cut(sf.Tag.Get(TagName), ",")
+	if name == "" || name == "-" {
+		return "", false
+	}
+	return name, true
+}
+
+// HasTagOption reports whether the koanf tag of sf includes option, as in
+// `koanf:"port,required"`.
+func HasTagOption(sf reflect.StructField, option string) bool {
+	_, opts, _ := strings.Cut(sf.Tag.Get(TagName), ",")
+	for _, o := range strings.Split(opts, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSecret reports whether sf is tagged as containing a credential.
+func IsSecret(sf reflect.StructField) bool {
+	secret, _ := strconv.ParseBool(sf.Tag.Get(SecretTagName))
+	return secret || HasTagOption(sf, SecretTagName)
+}
+
+// IsTextUnmarshaler reports whether t or a pointer to t implements
+// encoding.TextUnmarshaler.
+func IsTextUnmarshaler(t reflect.Type) bool {
+	return t.Implements(textUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// IsNested reports whether values of type t are nested configuration
+// structures rather than leaf values.
+func IsNested(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t == reflect.TypeOf(time.Time{}) {
+		return false
+	}
+	return !reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// StructValue returns the struct value referred to by cfg, which must be a
+// struct or a pointer to one.
+func StructValue(cfg interface{}) (reflect.Value, error) {
+	v := reflect.ValueOf(cfg)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			v = reflect.New(v.Type().Elem()).Elem()
+			continue
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("%T: %w", cfg, NotStructError)
+	}
+	return v, nil
+}
+
+// WalkFields calls fn for every leaf configuration field in the struct v,
+// passing its delimited key. Nil nested pointers are walked as zero values,
+// except that a nil pointer to a struct type that is already being walked, as
+// in type Node struct{ Next *Node }, is skipped so that recursive types end.
+func WalkFields(v reflect.Value, prefix, delimiter string, fn func(key string, sf reflect.StructField, v reflect.Value) error) error {
+	return walkFields(v, prefix, delimiter, map[reflect.Type]bool{}, fn)
+}
+
+// walkFields is WalkFields, where walking holds the struct types being walked
+// by the callers.
+func walkFields(v reflect.Value, prefix, delimiter string, walking map[reflect.Type]bool, fn func(key string, sf reflect.StructField, v reflect.Value) error) error {
+	t := v.Type()
+	walking[t] = true
+	defer delete(walking, t)
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, ok := FieldName(sf)
+		if !ok {
+			continue
+		}
+		key := prefix + name
+		fv := v.Field(i)
+		if !IsNested(sf.Type) {
+			if err := fn(key, sf, fv); err != nil {
+				return err
+			}
+			continue
+		}
+		if fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				if walking[fv.Type().Elem()] {
+					continue
+				}
+				fv = reflect.New(fv.Type().Elem())
+			}
+			fv = fv.Elem()
+		}
+		if err := walkFields(fv, key+delimiter, delimiter, walking, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flatten returns the leaf values of cfg keyed by their keys delimited by
+// delimiter.
+func Flatten(cfg interface{}, delimiter string) (map[string]interface{}, error) {
+	v, err := StructValue(cfg)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]interface{})
+	err = WalkFields(v, "", delimiter, func(key string, _ reflect.StructField, v reflect.Value) error {
+		m[key] = v.Interface()
+		return nil
+	})
+	return m, err
+}
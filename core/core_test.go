@@ -0,0 +1,142 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knadh/koanf/parsers/json"
+)
+
+type testConfig struct {
+	Name    string        `koanf:"name"`
+	Timeout time.Duration `koanf:"timeout"`
+	Ports   []int         `koanf:"ports"`
+	DB      struct {
+		URL string `koanf:"url" secret:"true"`
+	} `koanf:"db"`
+	ignored string
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    testConfig
+		wantErr bool
+	}{
+		{
+			name:  "values",
+			input: `{"name": "n", "timeout": "2s", "ports": "80,443", "db": {"url": "u"}}`,
+			want: testConfig{
+				Name:    "n",
+				Timeout: 2 * time.Second,
+				Ports:   []int{80, 443},
+				DB: struct {
+					URL string `koanf:"url" secret:"true"`
+				}{URL: "u"},
+			},
+		},
+		{
+			name:    "bad json",
+			input:   `{`,
+			wantErr: true,
+		},
+		{
+			name:    "bad type",
+			input:   `{"timeout": "soon"}`,
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got testConfig
+			err := Decode([]byte(tc.input), json.Parser(), &got)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("Decode err: got=%v wantErr=%t", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(testConfig{})); diff != "" {
+				t.Errorf("Decode mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUnmarshalNotStruct(t *testing.T) {
+	var i int
+	if err := Unmarshal(map[string]interface{}{}, &i); !errors.Is(err, NotStructError) {
+		t.Errorf("Unmarshal err: got=%v want=%v", err, NotStructError)
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	cfg := testConfig{Name: "n"}
+	cfg.DB.URL = "u"
+	got, err := Flatten(cfg, "/")
+	if err != nil {
+		t.Fatalf("Flatten failed unexpectedly: %v", err)
+	}
+	want := map[string]interface{}{
+		"name":    "n",
+		"timeout": time.Duration(0),
+		"ports":   []int(nil),
+		"db/url":  "u",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Flatten mismatch (-want +got):\n%s", diff)
+	}
+
+	var back testConfig
+	if err := UnmarshalFlat(got, "/", &back); err != nil {
+		t.Fatalf("UnmarshalFlat failed unexpectedly: %v", err)
+	}
+	if diff := cmp.Diff(cfg, back, cmp.AllowUnexported(testConfig{})); diff != "" {
+		t.Errorf("UnmarshalFlat mismatch (-want +got):\n%s", diff)
+	}
+}
+
+type testNode struct {
+	Name string    `koanf:"name"`
+	Next *testNode `koanf:"next"`
+}
+
+func TestFlattenRecursive(t *testing.T) {
+	cfg := testNode{Name: "a", Next: &testNode{Name: "b"}}
+	got, err := Flatten(cfg, "/")
+	if err != nil {
+		t.Fatalf("Flatten failed unexpectedly: %v", err)
+	}
+	want := map[string]interface{}{
+		"name":      "a",
+		"next/name": "b",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Flatten mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,100 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// DefaultDecryptMarker is the conventional prefix of encrypted values, as in
+// enc:AAAA.
+const DefaultDecryptMarker = "enc:"
+
+// DecryptFunc returns the plaintext of value, the encrypted value of key
+// without its marker.
+type DecryptFunc func(key, value string) (string, error)
+
+// WithDecrypter makes Load decrypt string values starting with marker, such as
+// DefaultDecryptMarker, using d once every layer has been merged, so that
+// individual secrets can be kept in KMS, Vault transit or custom encryption:
+//
+//	password: enc:AQICAHh...
+//
+// Several decrypters can be registered with different markers, such as
+// "kms:" and "vault:". Values in lists are decrypted too. An error from d fails
+// Load.
+func WithDecrypter(marker string, d DecryptFunc) Option {
+	return func(c *Config) {
+		if c.decrypters == nil {
+			c.decrypters = make(map[string]DecryptFunc)
+		}
+		c.decrypters[marker] = d
+	}
+}
+
+// decryptValues replaces the values of k marked for a decrypter of
+// WithDecrypter by their plaintext.
+func (c Config) decryptValues(k *koanf.Koanf) error {
+	if len(c.decrypters) == 0 {
+		return nil
+	}
+	// The longest marker is matched first, so that markers can share a
+	// prefix.
+	markers := make([]string, 0, len(c.decrypters))
+	for m := range c.decrypters {
+		markers = append(markers, m)
+	}
+	sort.Slice(markers, func(i, j int) bool {
+		if len(markers[i]) != len(markers[j]) {
+			return len(markers[i]) > len(markers[j])
+		}
+		return markers[i] < markers[j]
+	})
+
+	keys := k.Keys()
+	sort.Strings(keys)
+	for _, key := range keys {
+		key := key
+		v, changed, err := rewriteStrings(k.Get(key), func(s string) (interface{}, error) {
+			for _, m := range markers {
+				if strings.HasPrefix(s, m) {
+					return c.decrypters[m](key, s[len(m):])
+				}
+			}
+			return s, nil
+		})
+		if err != nil {
+			return fmt.Errorf("decrypt %s: %w", key, err)
+		}
+		if changed {
+			if err := k.Set(key, v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
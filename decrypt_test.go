@@ -0,0 +1,100 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/pflag"
+)
+
+func TestWithDecrypter(t *testing.T) {
+	errDecrypt := errors.New("decrypt failed")
+	reverse := func(key, value string) (string, error) {
+		if value == "bad" {
+			return "", errDecrypt
+		}
+		r := []rune(value)
+		for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+			r[i], r[j] = r[j], r[i]
+		}
+		return string(r), nil
+	}
+	upper := func(key, value string) (string, error) {
+		return key + "=" + strings.ToUpper(value), nil
+	}
+
+	cases := []struct {
+		name     string
+		contents string
+		wantName string
+		wantHost string
+		wantErr  error
+	}{
+		{
+			name:     "markers",
+			contents: "name: enc:terces\ndb:\n  host: enc:up:db\n",
+			wantName: "secret",
+			wantHost: "db.host=DB",
+		},
+		{
+			name:     "plain",
+			contents: "name: secret\n",
+			wantName: "secret",
+		},
+		{
+			name:     "error",
+			contents: "name: enc:bad\n",
+			wantErr:  errDecrypt,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			name := filepath.Join(t.TempDir(), "config.yaml")
+			writeTestFile(t, name, tc.contents)
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			AddConfigFlag(f)
+			if err := f.Parse([]string{"--" + FileArgName + "=" + name}); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+			c, err := New(testPrefix, testDelimiter, WithDecrypter(DefaultDecryptMarker, reverse), WithDecrypter("enc:up:", upper))
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+			var got testMountConfig
+			err = c.Load(f, &got)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Load err: got=%v want=%v", err, tc.wantErr)
+			}
+			var want testMountConfig
+			want.Name, want.DB.Host = tc.wantName, tc.wantHost
+			if diff := cmp.Diff(want, got); err == nil && diff != "" {
+				t.Errorf("Load mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/knadh/koanf/maps"
+	"github.com/knadh/koanf/v2"
+)
+
+// SourceDefaults names the layer holding default values when reporting where
+// values came from.
+const SourceDefaults = "defaults"
+
+// defaultTagName is the struct tag holding the default value of a field, as in
+// `default:"8080"`. The value is converted to the type of the field like a
+// value from the environment.
+const defaultTagName = "default"
+
+// mapProvider is a koanf.Provider for a map of delimited keys.
+type mapProvider struct {
+	m     map[string]interface{}
+	delim string
+}
+
+// ReadBytes is not supported because the values are read as a map.
+func (mapProvider) ReadBytes() ([]byte, error) {
+	return nil, errors.New("mapProvider does not support ReadBytes")
+}
+
+// Read returns the values as a nested map.
+func (p mapProvider) Read() (map[string]interface{}, error) {
+	return maps.Unflatten(p.m, p.delim), nil
+}
+
+// WithDefaults sets the default values of the configuration from a struct
+// such as the one passed to Load. The fields of v that are not zero are loaded
+// with lower precedence than every other source, so that defaults can be
+// written in Go:
+//
+//	c, err := goconfig.New("APP_", ".", goconfig.WithDefaults(Config{Port: 8080}))
+//
+// default tags take precedence over the values of v.
+func WithDefaults(v interface{}) Option {
+	return func(c *Config) {
+		c.defaults = v
+	}
+}
+
+// tagDefaults returns the default tag values of the fields of cfg keyed by
+// their delimited keys.
+func (c Config) tagDefaults(cfg interface{}) (map[string]interface{}, error) {
+	v, err := structValue(cfg)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]interface{})
+	err = walkFields(v, "", c.delimiter, func(key string, sf reflect.StructField, _ reflect.Value) error {
+		if d, ok := sf.Tag.Lookup(defaultTagName); ok {
+			m[key] = d
+		}
+		return nil
+	})
+	return m, err
+}
+
+// defaultsLayer returns the lowest precedence layer, which holds the values
+// set by WithDefaults and the default tags of cfg, and whether there are any.
+func (c Config) defaultsLayer(cfg interface{}) (layer, bool) {
+	m := make(map[string]interface{})
+	if c.defaults != nil {
+		d, err := c.flatten(c.defaults)
+		if err != nil {
+			return layer{
+				name: SourceDefaults,
+				desc: SourceDefaults,
+				open: func(*loaded) (koanf.Provider, koanf.Parser, error) {
+					return nil, nil, err
+				},
+			}, true
+		}
+		for k, v := range d {
+			// Zero fields are left out so that they do not hide the
+			// defaults of flags.
+			if v != nil && !reflect.ValueOf(v).IsZero() {
+				m[k] = v
+			}
+		}
+	}
+	// A cfg that is not a struct is reported by unmarshal.
+	if tags, err := c.tagDefaults(cfg); err == nil {
+		for k, v := range tags {
+			m[k] = v
+		}
+	}
+	if len(m) == 0 {
+		return layer{}, false
+	}
+	return layer{
+		name: SourceDefaults,
+		desc: SourceDefaults,
+		open: func(*loaded) (koanf.Provider, koanf.Parser, error) {
+			return mapProvider{m: m, delim: c.delimiter}, nil, nil
+		},
+	}, true
+}
@@ -0,0 +1,148 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/pflag"
+)
+
+type testDefaultsConfig struct {
+	Port    int           `koanf:"port" default:"8080"`
+	Timeout time.Duration `koanf:"timeout" default:"5s"`
+	Hosts   []string      `koanf:"hosts" default:"a,b"`
+	Server  struct {
+		Name string `koanf:"name" default:"server"`
+	} `koanf:"server"`
+	NoDefault int `koanf:"no_default"`
+}
+
+func TestLoadDefaultTags(t *testing.T) {
+	cases := []struct {
+		name string
+		args []string
+		env  map[string]string
+		want func(cfg *testDefaultsConfig)
+	}{
+		{
+			name: "defaults",
+			want: func(*testDefaultsConfig) {},
+		},
+		{
+			name: "env overrides default",
+			env:  map[string]string{"PORT": "9090"},
+			want: func(cfg *testDefaultsConfig) { cfg.Port = 9090 },
+		},
+		{
+			name: "flag overrides default",
+			args: []string{"--server.name=flag"},
+			want: func(cfg *testDefaultsConfig) { cfg.Server.Name = "flag" },
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(strings.ToUpper(testPrefix)+k, v)
+			}
+
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			f.String("server.name", "", testNoHelpMessage)
+			if err := f.Parse(tc.args); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+
+			c, err := New(testPrefix, testDelimiter)
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+
+			var got testDefaultsConfig
+			if err := c.Load(f, &got); err != nil {
+				t.Fatalf("Load err: got=%v want=nil", err)
+			}
+
+			want := testDefaultsConfig{Port: 8080, Timeout: 5 * time.Second, Hosts: []string{"a", "b"}}
+			want.Server.Name = "server"
+			tc.want(&want)
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("Load mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLoadWithDefaults(t *testing.T) {
+	t.Setenv(strings.ToUpper(testPrefix)+"SERVER_NAME", "env")
+
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	if err := f.Parse(nil); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+
+	defaults := testDefaultsConfig{Port: 1, NoDefault: 2}
+	defaults.Server.Name = "defaults"
+	c, err := New(testPrefix, testDelimiter, WithDefaults(defaults))
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	var got testDefaultsConfig
+	if err := c.Load(f, &got); err != nil {
+		t.Fatalf("Load err: got=%v want=nil", err)
+	}
+
+	// Default tags take precedence over WithDefaults, and the environment
+	// over both.
+	want := testDefaultsConfig{Port: 8080, Timeout: 5 * time.Second, Hosts: []string{"a", "b"}, NoDefault: 2}
+	want.Server.Name = "env"
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Load mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLoadWithDefaultsKeepsFlagDefaults(t *testing.T) {
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	f.Int(testKey2, testDefaultValue2, testNoHelpMessage)
+	if err := f.Parse(nil); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+
+	c, err := New(testPrefix, testDelimiter, WithDefaults(testConfig{Value1: testValue1}))
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	var got testConfig
+	if err := c.Load(f, &got); err != nil {
+		t.Fatalf("Load err: got=%v want=nil", err)
+	}
+	// The zero Value2 of the defaults must not replace the flag default.
+	want := testConfig{Value1: testValue1, Value2: testDefaultValue2}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Load mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,102 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Diff returns the keys whose values differ between old and new, two
+// configuration structs of the same type such as the values passed to the
+// onChange function of Watch, sorted by path. The entries of maps with string
+// keys are compared one by one, so Old is nil for entries only in new and New
+// is nil for entries only in old. Source is empty. The values of fields tagged
+// secret, and their entries, are RedactedValue.
+func (c Config) Diff(old, new interface{}) ([]KeyChange, error) {
+	oldValues, err := c.diffValues(old)
+	if err != nil {
+		return nil, fmt.Errorf("Diff old: %w", err)
+	}
+	newValues, err := c.diffValues(new)
+	if err != nil {
+		return nil, fmt.Errorf("Diff new: %w", err)
+	}
+	secrets := c.secretKeys(new)
+	redact := func(key string, v interface{}) interface{} {
+		if v != nil && secretKey(secrets, key, c.delimiter) {
+			return RedactedValue
+		}
+		return v
+	}
+
+	var changes []KeyChange
+	for path, nv := range newValues {
+		ov, ok := oldValues[path]
+		if ok && reflect.DeepEqual(ov, nv) {
+			continue
+		}
+		changes = append(changes, KeyChange{Path: path, Old: redact(path, ov), New: redact(path, nv)})
+	}
+	for path, ov := range oldValues {
+		if _, ok := newValues[path]; !ok {
+			changes = append(changes, KeyChange{Path: path, Old: redact(path, ov)})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+// diffValues returns the leaf values of cfg, with the entries of maps with
+// string keys as separate keys.
+func (c Config) diffValues(cfg interface{}) (map[string]interface{}, error) {
+	flat, err := c.flatten(cfg)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]interface{}, len(flat))
+	for key, v := range flat {
+		c.addDiffValue(values, key, reflect.ValueOf(v))
+	}
+	return values, nil
+}
+
+// addDiffValue adds v to values under key, expanding maps with string keys.
+func (c Config) addDiffValue(values map[string]interface{}, key string, v reflect.Value) {
+	for v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+		if v.IsValid() {
+			values[key] = v.Interface()
+		} else {
+			values[key] = nil
+		}
+		return
+	}
+	iter := v.MapRange()
+	for iter.Next() {
+		c.addDiffValue(values, key+c.delimiter+iter.Key().String(), iter.Value())
+	}
+}
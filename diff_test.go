@@ -0,0 +1,70 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type testDiffConfig struct {
+	Name   string            `koanf:"name"`
+	Labels map[string]string `koanf:"labels"`
+	DB     struct {
+		Host     string `koanf:"host"`
+		Password string `koanf:"password,secret"`
+	} `koanf:"db"`
+}
+
+func TestDiff(t *testing.T) {
+	var old, new testDiffConfig
+	old.Name, new.Name = "app", "app"
+	old.Labels = map[string]string{"team": "a", "tier": "web"}
+	new.Labels = map[string]string{"team": "b", "zone": "eu"}
+	old.DB.Host, new.DB.Host = "db1", "db2"
+	old.DB.Password, new.DB.Password = "old", "new"
+
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	got, err := c.Diff(&old, new)
+	if err != nil {
+		t.Fatalf("Diff err: got=%v want=nil", err)
+	}
+	want := []KeyChange{
+		{Path: "db.host", Old: "db1", New: "db2"},
+		{Path: "db.password", Old: RedactedValue, New: RedactedValue},
+		{Path: "labels.team", Old: "a", New: "b"},
+		{Path: "labels.tier", Old: "web"},
+		{Path: "labels.zone", New: "eu"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Diff mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := c.Diff(1, new); err == nil {
+		t.Errorf("Diff(1) err: got=nil want=error")
+	}
+}
@@ -0,0 +1,87 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// knownExtension reports whether name has the extension of a config format
+// understood by the package, or is an env file named .env, ignoring
+// AgeExtension.
+func knownExtension(name string) bool {
+	name = trimAgeExtension(name)
+	if path.Base(name) == ".env" {
+		return true
+	}
+	switch strings.ToLower(path.Ext(name)) {
+	case ".json", ".yaml", ".yml", ".hcl", ".tfvars", ".toml", ".env":
+		return true
+	}
+	return false
+}
+
+// expandDir returns the config files in fa if it names a local directory,
+// conf.d style: every regular file, or link to one, with a known extension,
+// in sorted order. Subdirectories are not searched. Other names are returned unchanged.
+func (c Config) expandDir(fa fileArg) ([]fileArg, error) {
+	if !localFile(fa.name) {
+		return []fileArg{fa}, nil
+	}
+	stat, readDir, join := os.Stat, os.ReadDir, filepath.Join
+	if c.fsys != nil {
+		stat = func(name string) (fs.FileInfo, error) { return fs.Stat(c.fsys, name) }
+		readDir = func(name string) ([]fs.DirEntry, error) { return fs.ReadDir(c.fsys, name) }
+		join = path.Join
+	}
+	if fi, err := stat(fa.name); err != nil || !fi.IsDir() {
+		return []fileArg{fa}, nil
+	}
+	entries, err := readDir(fa.name)
+	if err != nil {
+		return nil, fmt.Errorf("read config directory %s: %w", fa.name, err)
+	}
+
+	var args []fileArg
+	for _, e := range entries {
+		if !knownExtension(e.Name()) {
+			continue
+		}
+		name := join(fa.name, e.Name())
+		regular := e.Type().IsRegular()
+		if e.Type()&fs.ModeSymlink != 0 {
+			// Packaging systems often link fragments into place.
+			fi, err := stat(name)
+			regular = err == nil && fi.Mode().IsRegular()
+		}
+		if regular {
+			args = append(args, fileArg{name: name, mount: fa.mount, optional: fa.optional})
+		}
+	}
+	return args, nil
+}
@@ -0,0 +1,108 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/pflag"
+)
+
+func TestLoadDirectory(t *testing.T) {
+	dir := t.TempDir()
+	confd := filepath.Join(dir, "conf.d")
+	if err := os.Mkdir(confd, 0o755); err != nil {
+		t.Fatalf("os.Mkdir failed unexpectedly: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(confd, "sub.yaml"), 0o755); err != nil {
+		t.Fatalf("os.Mkdir failed unexpectedly: %v", err)
+	}
+	writeTestFile(t, filepath.Join(confd, "10-base.yaml"), "name: base\ndb:\n  host: localhost\n")
+	writeTestFile(t, filepath.Join(confd, "README"), "not config")
+	linked := filepath.Join(dir, "override.json")
+	writeTestFile(t, linked, `{"name": "override"}`)
+	if err := os.Symlink(linked, filepath.Join(confd, "20-override.json")); err != nil {
+		t.Fatalf("os.Symlink failed unexpectedly: %v", err)
+	}
+
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	AddConfigFlag(f)
+	if err := f.Parse([]string{"--" + FileArgName + "=" + confd}); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	var got testMountConfig
+	if err := c.Load(f, &got); err != nil {
+		t.Fatalf("Load err: got=%v want=nil", err)
+	}
+	var want testMountConfig
+	want.Name = "override"
+	want.DB.Host = "localhost"
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Load mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestExpandDirFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"conf.d/b.yml":     {},
+		"conf.d/a.json":    {},
+		"conf.d/.env":      {},
+		"conf.d/x.txt":     {},
+		"conf.d/sub/c.hcl": {},
+		"app.yaml":         {},
+	}
+	c, err := New(testPrefix, testDelimiter, WithFS(fsys))
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	cases := []struct {
+		arg  fileArg
+		want []fileArg
+	}{
+		{
+			arg:  fileArg{name: "conf.d", optional: true},
+			want: []fileArg{{name: "conf.d/.env", optional: true}, {name: "conf.d/a.json", optional: true}, {name: "conf.d/b.yml", optional: true}},
+		},
+		{arg: fileArg{name: "app.yaml"}, want: []fileArg{{name: "app.yaml"}}},
+		{arg: fileArg{name: "missing"}, want: []fileArg{{name: "missing"}}},
+	}
+	for _, tc := range cases {
+		got, err := c.expandDir(tc.arg)
+		if err != nil {
+			t.Errorf("expandDir(%q) err: got=%v want=nil", tc.arg.name, err)
+			continue
+		}
+		if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(fileArg{})); diff != "" {
+			t.Errorf("expandDir(%q) mismatch (-want +got):\n%s", tc.arg.name, diff)
+		}
+	}
+}
@@ -30,7 +30,13 @@
 // - flags
 //
 // If multiple sources contain different values for the same configruation
-// field, the last one found is used.
+// field, the last one found is used. The order of files, sources, the
+// environment and flags can be changed with WithPrecedence. Values compiled
+// into the binary with CompiledOverlay or WithCompiledOverlay are loaded last
+// and cannot be overridden. Result.Explain, on the Result returned by
+// LoadWithResult, shows which source supplied a key and which sources it
+// overrode. LoadWithReport summarizes the files, environment variables and
+// flags that were used.
 //
 // In order for a structure field to be loaded, it must be exported (e.g start
 // with a capital letter), and contain a koanf field tag:
@@ -39,6 +45,13 @@
 //	  Value  string         `koanf:"value"`
 //	}
 //
+// The generic Load returns the loaded struct instead of filling in a pointer:
+//
+//	cfg, err := goconfig.Load[Config](c, f)
+//
+// MustLoad panics, or calls the handler set by WithMustLoadHandler, instead
+// of returning an error.
+//
 // Values are loaded from all the sources based on the tag, and would be loaded
 // from any of these sources if the were supplied:
 //
@@ -76,6 +89,181 @@
 //
 // $ export NESTED_VAL="from the environment"
 //
+// A field can be bound to an environment variable with a fixed name, without
+// the prefix or separator, using an env tag:
+//
+//	DatabaseURL string `koanf:"database_url" env:"DATABASE_URL"`
+//
+// WithSecretFiles reads the value of a field from the file named by its
+// variable followed by _FILE, as Docker secrets are injected, and a
+// secretfile tag names such a variable for one field:
+//
+//	Password string `koanf:"password" secretfile:"DB_PASSWORD_FILE"`
+//
+// Fields holding credentials are tagged secret, as in `secret:"true"` or
+// `koanf:"password,secret"`. Their values are replaced by RedactedValue in
+// errors, log messages and key changes, and in the output of Config.Dump,
+// which writes the effective configuration as YAML or JSON.
+// WithPrintConfig and AddPrintConfigFlag add a --print-config flag that makes
+// Load print it and fail with PrintedConfigError, so the program can exit.
+//
+// Use WithEnvSeparator("__") to separate nested keys with "__" instead, so that
+// keys can contain underscores: NESTED__MAX_VAL sets nested.max_val.
+//
+// Configuration files are named with the flag FileArgName, which may be a
+// string slice or a string array. AddConfigFlag defines it as a FileList, so
+// that each file is passed with a separate flag and names can contain commas:
+//
+// $ ./prog --config=base.yaml --config=prod.yaml
+//
+// A FileList with Append set adds the files given on the command line to its
+// default files instead of replacing them, and --config= clears the list, so
+// that no config file is loaded or searched for.
+//
+// WithFileFlag uses another flag, such as config-file, and Config.AddFileFlag
+// defines it with an optional shorthand such as -c.
+//
+// Files can also be listed, separated by colons or commas, in the environment
+// variable named by the prefix followed by CONFIG. They are loaded before the
+// files given with flags:
+//
+// $ export CONFIG_CONFIG=base.yaml:prod.yaml
+//
+// Configuration files are parsed as YAML if their name ends in .yaml or .yml,
+// as HCL if it ends in .hcl or .tfvars, as TOML if it ends in .toml, as an env
+// file if it ends in .env, and as JSON otherwise. Env files follow the Docker Compose env_file rules, and
+// their variables are mapped to keys the same way environment variables are.
+//
+// Files ending in .age, such as config.yaml.age, are decrypted with the age
+// identities passed to WithAgeIdentities or held in the environment variable
+// named by the prefix followed by AGE_IDENTITY, then parsed by the extension
+// before .age.
+//
+// Individual values can be encrypted instead, with a marker such as enc: that
+// selects the function passed to WithDecrypter.
+//
+// Files named by http or https URLs are fetched using the settings passed to
+// WithHTTP. Files can also be fetched from object storage by passing a URL such
+// as s3://bucket/key.yaml or gs://bucket/key.json, provided a store for the
+// scheme was registered with WithObjectStore.
+//
+// A config file can be mounted under a key by appending it after a colon, so
+// that a file holding only database settings does not need its own top level
+// key:
+//
+// $ ./prog --config=db.yaml:db
+//
+// loads the key pool.size of db.yaml as db.pool.size.
+//
+// A config file is optional if its name starts with a question mark. Optional
+// files that do not exist are skipped, while a missing required file fails
+// Load:
+//
+// $ ./prog --config=app.yaml --config=?local.yaml
+//
+// WithSearchPaths looks for optional files in /etc, the user config directory
+// and the working directory when no config file is named at all.
+//
+// WithProfiles loads, after each config file, the optional file of each active
+// profile named by --profile or the environment variable PROFILE, so that
+// config.prod.yaml overrides config.yaml:
+//
+// $ ./prog --config=config.yaml --profile=prod
+//
+// Glob patterns are expanded and the matching files loaded in lexical order,
+// so drop-in directories work without help from the shell. A pattern that
+// matches nothing loads nothing:
+//
+// $ ./prog --config='conf.d/*.yaml'
+//
+// A directory loads every config file directly inside it with a recognized
+// extension, in sorted order, as if each had been named:
+//
+// $ ./prog --config=/etc/app/conf.d
+//
+// A config file can include other files by listing them, relative to itself,
+// under the top level key include. Included files are loaded first, so the
+// including file overrides them, and files including each other fail Load
+// with IncludeCycleError:
+//
+//	include: [db.yaml, conf.d]
+//
+// WithInterpolation expands environment variables in the string values of
+// config files, with an optional default:
+//
+//	host: ${DB_HOST:-localhost}
+//
+// WithReferences replaces references to other keys once every layer has been
+// merged:
+//
+//	url: http://${nested.host}:${nested.port}
+//
+// WithAliases renames keys in every source, so that keys can be renamed
+// without breaking existing configurations. WithDeprecatedAliases also logs a
+// warning when an old key is used.
+//
+// WithMigration registers functions that migrate config files with an older
+// version key to the current format before they are used.
+//
+// WithSetFlag accepts one-off overrides, which take precedence over files,
+// the environment and flags:
+//
+// $ ./prog --set nested.val=7 --set value=x
+//
+// The config file - reads standard input, as YAML unless WithStdinFormat
+// selects another format:
+//
+// $ render-config | ./prog --config=-
+//
+// RegisterFlags defines a flag for every field of the configuration struct,
+// with its help tag as the usage and its short tag as the shorthand, so that
+// the flags cannot drift from it. hidden and deprecated tags retire flags
+// without breaking the programs that still use them.
+//
+// Save writes a configuration struct, such as one changed by a "config set"
+// command, back to a YAML, TOML or JSON config file, keeping the values of
+// secret fields and the comments of YAML files.
+//
+// Generate writes a commented sample config file from the struct tags,
+// including help tags, and defaults, for commands such as "config init".
+//
+// JSONSchema describes the configuration struct, with its types, required
+// fields, allowed values and defaults, so that editors and CI can check config
+// files before they are deployed.
+//
+// Validate checks config files against the configuration struct without the
+// environment or flags, so that the config of every environment can be
+// checked in CI.
+//
+// The goconfig command in cmd/goconfig validates, merges and converts config
+// files using a schema written by JSONSchema, for people who do not write Go.
+//
+// LoadKey loads only the keys below a prefix into a smaller struct, so that
+// libraries can own their part of the configuration.
+//
+// LoadContext stops loading when its context is done, and passes the context
+// to remote config files and to sources that are ContextProviders. Watches and
+// reloads use the context they are given in the same way.
+//
+// NewLoader remembers the flag set and options of a load, and its Reload runs
+// the full load again and returns any error, for SIGHUP handlers and
+// admin-triggered refreshes.
+//
+// Programs using github.com/urfave/cli/v2 load their configuration with the
+// urfavecli package, which has the same precedence rules.
+//
+// All returns every loaded value as a nested map, for tools that read
+// arbitrary keys rather than a struct.
+//
+// Result.Values gives viper-like accessors, such as GetString, for the loaded
+// values, to help move large programs from viper one package at a time. The
+// Values can be kept after startup and changed with Set. Result.Koanf returns
+// the underlying koanf instance for features the package does not provide.
+//
+// Programs that cannot use files, the environment or flags, such as TinyGo
+// firmware, can decode a configuration held in a byte slice with the core
+// package, which converts values exactly as Load does.
+//
 // Notes:
 //   - This requires using the pflags package instead of the built in flags
 //     package.
@@ -0,0 +1,124 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/knadh/koanf/maps"
+)
+
+// Dump returns cfg, usually the struct filled in by Load, encoded as YAML, JSON
+// or TOML, selected by format, for support bundles and debugging. The values of
+// fields tagged secret are replaced by RedactedValue unless they are empty, so
+// that a dump shows whether a credential was set without revealing it.
+// Values that look like credentials but are not tagged secret are logged as a
+// warning. Durations and types implementing encoding.TextMarshaler are written
+// as text.
+func (c Config) Dump(cfg interface{}, format string) ([]byte, error) {
+	b, err := c.encode("Dump", cfg, format, true)
+	if err != nil {
+		return nil, fmt.Errorf("Dump: %w", err)
+	}
+	return b, nil
+}
+
+// encode returns cfg encoded as format as Dump does. See dumpValues for op and
+// redact.
+func (c Config) encode(op string, cfg interface{}, format string, redact bool) ([]byte, error) {
+	flat, err := c.dumpValues(op, cfg, redact)
+	if err != nil {
+		return nil, err
+	}
+	m := maps.Unflatten(flat, c.delimiter)
+
+	switch strings.ToLower(format) {
+	case FormatYAML, "yml":
+		b, err := marshalYAML(m)
+		if err != nil {
+			return nil, fmt.Errorf("marshal: %w", err)
+		}
+		return b, nil
+	case FormatJSON:
+		b, err := json.MarshalIndent(m, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("marshal: %w", err)
+		}
+		return append(b, '\n'), nil
+	case FormatTOML:
+		b, err := tomlParser{}.Marshal(m)
+		if err != nil {
+			return nil, fmt.Errorf("marshal: %w", err)
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("format %q: %w", format, UnknownFormatError)
+	}
+}
+
+// dumpValues returns the leaf values of cfg, keyed by their delimited keys, in the
+// form they are written by Dump, replacing the values of secret fields only if
+// redact is true. Values that look like credentials but are not tagged secret,
+// and so are written as they are, are logged as a warning for op.
+func (c Config) dumpValues(op string, cfg interface{}, redact bool) (map[string]interface{}, error) {
+	c.warnSecrets(op, cfg)
+	flat, err := c.flatten(cfg)
+	if err != nil {
+		return nil, err
+	}
+	secrets := c.secretKeys(cfg)
+	for key, v := range flat {
+		switch {
+		case redact && secrets[key] && v != nil && !reflect.ValueOf(v).IsZero():
+			flat[key] = RedactedValue
+		default:
+			if flat[key], err = dumpValue(v); err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+		}
+	}
+	return flat, nil
+}
+
+// dumpValue returns v in the form it is written by Dump.
+func dumpValue(v interface{}) (interface{}, error) {
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return nil, nil
+	}
+	switch v := v.(type) {
+	case time.Duration:
+		return v.String(), nil
+	case encoding.TextMarshaler:
+		b, err := v.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	}
+	return v, nil
+}
@@ -0,0 +1,95 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type testDumpConfig struct {
+	Name    string        `koanf:"name"`
+	Timeout time.Duration `koanf:"timeout"`
+	IP      net.IP        `koanf:"ip"`
+	DB      struct {
+		Password string `koanf:"password" secret:"true"`
+		Token    string `koanf:"token,secret"`
+	} `koanf:"db"`
+}
+
+func TestDump(t *testing.T) {
+	cfg := testDumpConfig{Name: "app", Timeout: 5 * time.Second, IP: net.IPv4(10, 0, 0, 1)}
+	cfg.DB.Password = "hunter2"
+
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	cases := []struct {
+		format  string
+		want    string
+		wantErr error
+	}{
+		{
+			format: FormatYAML,
+			want: `db:
+  password: '[REDACTED]'
+  token: ""
+ip: 10.0.0.1
+name: app
+timeout: 5s
+`,
+		},
+		{
+			format: FormatJSON,
+			want: `{
+  "db": {
+    "password": "[REDACTED]",
+    "token": ""
+  },
+  "ip": "10.0.0.1",
+  "name": "app",
+  "timeout": "5s"
+}
+`,
+		},
+		{
+			format:  FormatHCL,
+			wantErr: UnknownFormatError,
+		},
+	}
+	for _, tc := range cases {
+		got, err := c.Dump(&cfg, tc.format)
+		if !errors.Is(err, tc.wantErr) {
+			t.Errorf("Dump(%s) err: got=%v want=%v", tc.format, err, tc.wantErr)
+			continue
+		}
+		if diff := cmp.Diff(tc.want, string(got)); diff != "" {
+			t.Errorf("Dump(%s) mismatch (-want +got):\n%s", tc.format, diff)
+		}
+	}
+}
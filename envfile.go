@@ -0,0 +1,182 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/knadh/koanf/maps"
+)
+
+var (
+	BadEnvFileError = errors.New("invalid env file")
+)
+
+// envFileParser is a koanf.Parser for env files. Variable names are mapped to
+// keys the same way environment variables are, so only names starting with
+// the prefix are used.
+type envFileParser struct {
+	c Config
+}
+
+// Unmarshal parses the env file in b.
+func (p envFileParser) Unmarshal(b []byte) (map[string]interface{}, error) {
+	vars, err := parseEnvFile(string(b))
+	if err != nil {
+		return nil, err
+	}
+	flat := make(map[string]interface{})
+	for name, value := range vars {
+		if !strings.HasPrefix(name, p.c.prefix) {
+			continue
+		}
+		if key := p.c.updateEnv(name); key != "" {
+			flat[key] = value
+		}
+	}
+	return maps.Unflatten(flat, p.c.delimiter), nil
+}
+
+// Marshal is not supported for env files.
+func (p envFileParser) Marshal(map[string]interface{}) ([]byte, error) {
+	return nil, errors.New("marshaling env files is not supported")
+}
+
+// parseEnvFile parses s using the rules Docker Compose applies to env_file:
+//   - blank lines and lines starting with # are ignored
+//   - each line is NAME=VALUE; a line containing only NAME takes its value
+//     from the environment and is ignored if it is not set
+//   - the export keyword is not supported
+//   - unquoted values end at a # preceded by whitespace and are trimmed
+//   - single quoted values are literal
+//   - double quoted values may span lines and support \n, \r, \t, \\, \"
+//     and \$ escapes
+func parseEnvFile(s string) (map[string]string, error) {
+	vars := make(map[string]string)
+	line := 1
+	for len(s) > 0 {
+		var l string
+		l, s = cutLine(s)
+		trimmed := strings.TrimSpace(l)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			line++
+			continue
+		}
+		if strings.HasPrefix(trimmed, "export ") {
+			return nil, fmt.Errorf("line %d: export is not supported: %w", line, BadEnvFileError)
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimLeft(l, " \t"), "=")
+		name = strings.TrimRight(name, " \t")
+		if name == "" || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("line %d: invalid variable name %q: %w", line, name, BadEnvFileError)
+		}
+		if !hasValue {
+			if v, ok := os.LookupEnv(name); ok {
+				vars[name] = v
+			}
+			line++
+			continue
+		}
+
+		value = strings.TrimLeft(value, " \t")
+		start := line
+		switch {
+		case strings.HasPrefix(value, "'"):
+			end := strings.Index(value[1:], "'")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated single quote: %w", start, BadEnvFileError)
+			}
+			vars[name] = value[1 : end+1]
+		case strings.HasPrefix(value, `"`):
+			// The value may continue onto following lines.
+			value = value[1:]
+			for {
+				v, ok := unquoteEnvValue(value)
+				if ok {
+					vars[name] = v
+					break
+				}
+				if len(s) == 0 {
+					return nil, fmt.Errorf("line %d: unterminated double quote: %w", start, BadEnvFileError)
+				}
+				l, s = cutLine(s)
+				value += "\n" + l
+				line++
+			}
+		default:
+			if i := strings.Index(value, " #"); i >= 0 {
+				value = value[:i]
+			}
+			if i := strings.Index(value, "\t#"); i >= 0 {
+				value = value[:i]
+			}
+			vars[name] = strings.TrimSpace(value)
+		}
+		line++
+	}
+	return vars, nil
+}
+
+// cutLine splits s after its first line, removing the line ending.
+func cutLine(s string) (line, rest string) {
+	line, rest, _ = strings.Cut(s, "\n")
+	return strings.TrimSuffix(line, "\r"), rest
+}
+
+// unquoteEnvValue returns the contents of the double quoted value s, which
+// starts after the opening quote, and whether the closing quote was found.
+func unquoteEnvValue(s string) (string, bool) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			return b.String(), true
+		case '\\':
+			if i+1 == len(s) {
+				b.WriteByte('\\')
+				continue
+			}
+			i++
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '\\', '"', '$':
+				b.WriteByte(s[i])
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(s[i])
+			}
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,125 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_parseEnvFile(t *testing.T) {
+	t.Setenv("FROM_ENV", "env value")
+
+	cases := []struct {
+		name    string
+		value   string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:  "empty",
+			value: "",
+			want:  map[string]string{},
+		},
+		{
+			name:  "comments and blank lines",
+			value: "# comment\n\n  # indented comment\nA=1\n",
+			want:  map[string]string{"A": "1"},
+		},
+		{
+			name:  "unquoted",
+			value: "A=value with spaces  \nB=value # comment\nC=value#not comment\nD=\n",
+			want: map[string]string{
+				"A": "value with spaces",
+				"B": "value",
+				"C": "value#not comment",
+				"D": "",
+			},
+		},
+		{
+			name:  "single quoted",
+			value: `A='$OTHER # not comment\n'` + "\n",
+			want:  map[string]string{"A": `$OTHER # not comment\n`},
+		},
+		{
+			name:  "double quoted",
+			value: `A="line1\nline2 \"quoted\" \$x # not comment"` + "\n",
+			want:  map[string]string{"A": "line1\nline2 \"quoted\" $x # not comment"},
+		},
+		{
+			name:  "double quoted multiple lines",
+			value: "A=\"first\nsecond\"\nB=2\n",
+			want:  map[string]string{"A": "first\nsecond", "B": "2"},
+		},
+		{
+			name:  "name only",
+			value: "FROM_ENV\nNOT_SET_ANYWHERE\n",
+			want:  map[string]string{"FROM_ENV": "env value"},
+		},
+		{
+			name:  "crlf",
+			value: "A=1\r\nB=2\r\n",
+			want:  map[string]string{"A": "1", "B": "2"},
+		},
+		{
+			name:    "export",
+			value:   "export A=1\n",
+			wantErr: true,
+		},
+		{
+			name:    "unterminated double quote",
+			value:   "A=\"value\n",
+			wantErr: true,
+		},
+		{
+			name:    "unterminated single quote",
+			value:   "A='value\n",
+			wantErr: true,
+		},
+		{
+			name:    "bad name",
+			value:   "A B=1\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseEnvFile(tc.value)
+			if tc.wantErr {
+				if !errors.Is(err, BadEnvFileError) {
+					t.Errorf("parseEnvFile err: got=%v want=%v", err, BadEnvFileError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseEnvFile err: got=%v want=nil", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("parseEnvFile mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
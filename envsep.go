@@ -0,0 +1,60 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+// DefaultEnvSeparator separates nested keys in environment variable names
+// unless WithEnvSeparator is used.
+const DefaultEnvSeparator = "_"
+
+// WithEnvSeparator sets the string that separates nested keys in environment
+// variable names, which is "_" by default. With the default, a key containing
+// an underscore, such as db.max_conns, cannot be set from the environment
+// because DB_MAX_CONNS means db.max.conns. With "__", DB__MAX_CONNS sets
+// db.max_conns and single underscores are kept.
+func WithEnvSeparator(sep string) Option {
+	return func(c *Config) {
+		c.envSep = sep
+	}
+}
+
+// envSeparator returns the separator of nested keys in environment variable
+// names.
+func (c Config) envSeparator() string {
+	if c.envSep == "" {
+		return DefaultEnvSeparator
+	}
+	return c.envSep
+}
+
+// WithEnvTransform replaces the mapping from environment variable names to
+// keys, which by default removes the prefix, lower cases the name and replaces
+// the separator with the delimiter. fn is called with the full name of every
+// variable starting with the prefix, and of every variable in env files, and
+// returns the key it sets using the delimiter, or an empty string to ignore
+// the variable. Use it to preserve case, map CamelCase or keep some
+// underscores. Env tags are not affected.
+func WithEnvTransform(fn func(name string) string) Option {
+	return func(c *Config) {
+		c.envTransform = fn
+	}
+}
@@ -0,0 +1,87 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+type testEnvSepConfig struct {
+	DB struct {
+		MaxConns int `koanf:"max_conns"`
+	} `koanf:"db"`
+}
+
+func TestWithEnvSeparator(t *testing.T) {
+	t.Setenv(testPrefix+"DB__MAX_CONNS", "7")
+
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	if err := f.Parse(nil); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	c, err := New(testPrefix, testDelimiter, WithEnvSeparator("__"))
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	var cfg testEnvSepConfig
+	if err := c.Load(f, &cfg); err != nil {
+		t.Fatalf("Load err: got=%v want=nil", err)
+	}
+	if got, want := cfg.DB.MaxConns, 7; got != want {
+		t.Errorf("MaxConns: got=%d want=%d", got, want)
+	}
+	if got, want := c.envName("db.max_conns"), testPrefix+"DB__MAX_CONNS"; got != want {
+		t.Errorf("envName: got=%q want=%q", got, want)
+	}
+}
+
+func TestWithEnvTransform(t *testing.T) {
+	t.Setenv(testPrefix+"DB_MAX_CONNS", "7")
+	t.Setenv(testPrefix+"IGNORED", "x")
+
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	if err := f.Parse(nil); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	transform := func(name string) string {
+		if name == testPrefix+"DB_MAX_CONNS" {
+			return "db.max_conns"
+		}
+		return ""
+	}
+	c, err := New(testPrefix, testDelimiter, WithEnvTransform(transform), WithStrict())
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	var cfg testEnvSepConfig
+	if err := c.Load(f, &cfg); err != nil {
+		t.Fatalf("Load err: got=%v want=nil", err)
+	}
+	if got, want := cfg.DB.MaxConns, 7; got != want {
+		t.Errorf("MaxConns: got=%d want=%d", got, want)
+	}
+}
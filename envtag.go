@@ -0,0 +1,102 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"os"
+	"reflect"
+
+	"github.com/knadh/koanf/maps"
+	"github.com/knadh/koanf/v2"
+)
+
+// envTagName is the struct tag that binds a field to an environment variable
+// with exactly the given name, as in `env:"DATABASE_URL"`. The prefix and
+// separator are not applied.
+const envTagName = "env"
+
+// envTags returns the environment variable names bound to fields of cfg by env
+// tags, keyed by their delimited keys.
+func (c Config) envTags(cfg interface{}) (map[string]string, error) {
+	v, err := structValue(cfg)
+	if err != nil {
+		return nil, err
+	}
+	tags := make(map[string]string)
+	err = walkFields(v, "", c.delimiter, func(key string, sf reflect.StructField, _ reflect.Value) error {
+		if name := sf.Tag.Get(envTagName); name != "" {
+			tags[key] = name
+		}
+		return nil
+	})
+	return tags, err
+}
+
+// fieldEnvName returns the environment variable that sets the field sf with
+// the delimited key.
+func (c Config) fieldEnvName(key string, sf reflect.StructField) string {
+	if name := sf.Tag.Get(envTagName); name != "" {
+		return name
+	}
+	return c.envName(key)
+}
+
+// envTagProvider is a koanf.Provider that adds the environment variables bound
+// by env tags, and the contents of secret files, to the values read by env.
+type envTagProvider struct {
+	env     koanf.Provider
+	tags    map[string]string
+	secrets map[string]secretFileVar
+	delim   string
+	l       *loaded
+}
+
+// ReadBytes is not supported because the environment is read as a map.
+func (envTagProvider) ReadBytes() ([]byte, error) {
+	return nil, errors.New("envTagProvider does not support ReadBytes")
+}
+
+// Read returns the environment, with tagged variables and secret files taking
+// precedence.
+func (p envTagProvider) Read() (map[string]interface{}, error) {
+	m, err := p.env.Read()
+	if err != nil {
+		return nil, err
+	}
+	tagged, err := readSecretFiles(p.secrets)
+	if err != nil {
+		return nil, err
+	}
+	for key := range tagged {
+		p.l.recordSpelling(SourceEnv, p.secrets[key].name, key)
+	}
+	for key, name := range p.tags {
+		if v, ok := os.LookupEnv(name); ok {
+			tagged[key] = v
+			p.l.recordSpelling(SourceEnv, name, key)
+		}
+	}
+	maps.Merge(maps.Unflatten(tagged, p.delim), m)
+	return m, nil
+}
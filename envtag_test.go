@@ -0,0 +1,70 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+type testEnvTagConfig struct {
+	DB struct {
+		URL  string `koanf:"url" env:"TEST_ENVTAG_DATABASE_URL"`
+		Name string `koanf:"name"`
+	} `koanf:"db"`
+}
+
+func TestEnvTag(t *testing.T) {
+	t.Setenv("TEST_ENVTAG_DATABASE_URL", "postgres://db")
+	t.Setenv(testPrefix+"DB_NAME", "app")
+
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	if err := f.Parse(nil); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	var cfg testEnvTagConfig
+	if err := c.Load(f, &cfg); err != nil {
+		t.Fatalf("Load err: got=%v want=nil", err)
+	}
+	if got, want := cfg.DB.URL, "postgres://db"; got != want {
+		t.Errorf("URL: got=%q want=%q", got, want)
+	}
+	if got, want := cfg.DB.Name, "app"; got != want {
+		t.Errorf("Name: got=%q want=%q", got, want)
+	}
+
+	b, err := c.Manifest(cfg, ManifestOptions{Kind: KindConfigMap, Name: "app"})
+	if err != nil {
+		t.Fatalf("Manifest err: got=%v want=nil", err)
+	}
+	if !strings.Contains(string(b), "TEST_ENVTAG_DATABASE_URL:") {
+		t.Errorf("Manifest: got=%s want TEST_ENVTAG_DATABASE_URL", b)
+	}
+}
@@ -0,0 +1,128 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// FileLoadError is returned by Load when a config file cannot be read or
+// parsed.
+type FileLoadError struct {
+	File string
+	Err  error
+}
+
+func (e *FileLoadError) Error() string {
+	return fmt.Sprintf("Load file %s: %v", e.File, e.Err)
+}
+
+func (e *FileLoadError) Unwrap() error { return e.Err }
+
+// EnvError is returned by Load when the environment cannot be loaded.
+type EnvError struct {
+	Err error
+}
+
+func (e *EnvError) Error() string {
+	return fmt.Sprintf("Load env: %v", e.Err)
+}
+
+func (e *EnvError) Unwrap() error { return e.Err }
+
+// FlagError is returned by Load when the flags cannot be loaded.
+type FlagError struct {
+	Err error
+}
+
+func (e *FlagError) Error() string {
+	return fmt.Sprintf("Load flags: %v", e.Err)
+}
+
+func (e *FlagError) Unwrap() error { return e.Err }
+
+// UnmarshalError is returned by Load when a value cannot be stored in the
+// configuration struct, usually because it has the wrong type. Key and Type
+// identify the field and its type, and are empty if the field could not be
+// determined.
+type UnmarshalError struct {
+	Key  string
+	Type reflect.Type
+	Err  error
+}
+
+func (e *UnmarshalError) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf("Load unmarshal: %v", e.Err)
+	}
+	return fmt.Sprintf("Load unmarshal %s into %v: %v", e.Key, e.Type, e.Err)
+}
+
+func (e *UnmarshalError) Unwrap() error { return e.Err }
+
+// layerError wraps err, the failure to load ly, in the error type for ly.
+func layerError(ly layer, err error) error {
+	switch {
+	case ly.file:
+		return &FileLoadError{File: ly.name, Err: err}
+	case ly.name == SourceEnv:
+		return &EnvError{Err: err}
+	case ly.name == SourceFlags, ly.name == SourceSet:
+		return &FlagError{Err: err}
+	}
+	return fmt.Errorf("Load %s: %w", ly.desc, err)
+}
+
+// unmarshalError returns an UnmarshalError for err, the failure to unmarshal
+// l into cfg, identifying the first field that cannot be unmarshaled by
+// itself.
+func (c Config) unmarshalError(l *loaded, cfg interface{}, err error) error {
+	return c.unmarshalErrors(l, cfg, err)[0]
+}
+
+// unmarshalErrors returns an UnmarshalError for every field that cannot be
+// unmarshaled by itself, or one UnmarshalError for err if no field fails.
+func (c Config) unmarshalErrors(l *loaded, cfg interface{}, err error) []error {
+	v, verr := structValue(cfg)
+	if verr != nil {
+		return []error{&UnmarshalError{Err: l.redactError(err)}}
+	}
+	var errs []error
+	_ = walkFields(v, "", c.delimiter, func(key string, sf reflect.StructField, _ reflect.Value) error {
+		if !l.k.Exists(key) {
+			return nil
+		}
+		if ferr := l.k.Unmarshal(key, reflect.New(sf.Type).Interface()); ferr != nil {
+			if l.isSecret(key) {
+				ferr = secretFieldError()
+			}
+			errs = append(errs, &UnmarshalError{Key: key, Type: sf.Type, Err: ferr})
+		}
+		return nil
+	})
+	if len(errs) == 0 {
+		return []error{&UnmarshalError{Err: l.redactError(err)}}
+	}
+	return errs
+}
@@ -0,0 +1,87 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestLoadErrorTypes(t *testing.T) {
+	t.Run("missing file", func(t *testing.T) {
+		const missing = "/this/file/does/not/exist.json"
+		f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+		f.StringSlice(FileArgName, nil, testNoHelpMessage)
+		if err := f.Parse([]string{fmt.Sprintf("--%s=%s", FileArgName, missing)}); err != nil {
+			t.Fatalf("f.Parse failed unexpectedly: %v", err)
+		}
+		c, err := New(testPrefix, testDelimiter)
+		if err != nil {
+			t.Fatalf("New failed unexpectedly: %v", err)
+		}
+
+		var cfg testConfig
+		err = c.Load(f, &cfg)
+		var fe *FileLoadError
+		if !errors.As(err, &fe) {
+			t.Fatalf("Load err: got=%v want *FileLoadError", err)
+		}
+		if got, want := fe.File, missing; got != want {
+			t.Errorf("File: got=%q want=%q", got, want)
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("Load err: got=%v want wrapping %v", err, fs.ErrNotExist)
+		}
+	})
+
+	t.Run("wrong type", func(t *testing.T) {
+		t.Setenv(strings.ToUpper(testPrefix+testKey2), "not a number")
+		f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+		if err := f.Parse(nil); err != nil {
+			t.Fatalf("f.Parse failed unexpectedly: %v", err)
+		}
+		c, err := New(testPrefix, testDelimiter)
+		if err != nil {
+			t.Fatalf("New failed unexpectedly: %v", err)
+		}
+
+		var cfg testConfig
+		err = c.Load(f, &cfg)
+		var ue *UnmarshalError
+		if !errors.As(err, &ue) {
+			t.Fatalf("Load err: got=%v want *UnmarshalError", err)
+		}
+		if got, want := ue.Key, testKey2; got != want {
+			t.Errorf("Key: got=%q want=%q", got, want)
+		}
+		if got, want := ue.Type, reflect.TypeOf(0); got != want {
+			t.Errorf("Type: got=%v want=%v", got, want)
+		}
+	})
+}
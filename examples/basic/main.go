@@ -0,0 +1,81 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Command basic is a command line program configured from files, the
+// environment and flags:
+//
+//	$ BASIC_LOG_LEVEL=debug basic --config=basic.yaml --port=9090
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/bretmckee/goconfig"
+	"github.com/spf13/pflag"
+)
+
+// Config is the configuration of the program.
+type Config struct {
+	Name    string        `koanf:"name" default:"basic" help:"name of the service"`
+	Port    int           `koanf:"port" default:"8080" validate:"min=1,max=65535" help:"port to listen on"`
+	Timeout time.Duration `koanf:"timeout" default:"5s" help:"request timeout"`
+	Log     struct {
+		Level string `koanf:"level" default:"info" oneof:"debug,info,warn,error"`
+	} `koanf:"log"`
+	DB struct {
+		URL string `koanf:"url,required" secret:"true"`
+	} `koanf:"db"`
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// run loads the configuration from args and the environment and prints it to
+// out.
+func run(args []string, out io.Writer) error {
+	c, err := goconfig.New("BASIC_", ".", goconfig.WithStrict(), goconfig.WithValidation())
+	if err != nil {
+		return err
+	}
+	f := pflag.NewFlagSet("basic", pflag.ContinueOnError)
+	goconfig.AddConfigFlag(f)
+	if err := c.RegisterFlags(f, &Config{}); err != nil {
+		return err
+	}
+	if err := f.Parse(args); err != nil {
+		return err
+	}
+
+	var cfg Config
+	if err := c.Load(f, &cfg); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "name=%s port=%d timeout=%s log.level=%s\n", cfg.Name, cfg.Port, cfg.Timeout, cfg.Log.Level)
+	return nil
+}
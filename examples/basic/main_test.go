@@ -0,0 +1,89 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/bretmckee/goconfig"
+)
+
+func TestRun(t *testing.T) {
+	cases := []struct {
+		name    string
+		args    []string
+		env     map[string]string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "file",
+			args: []string{"--config=basic.yaml"},
+			want: "name=example port=8081 timeout=5s log.level=info\n",
+		},
+		{
+			name: "env and flags",
+			args: []string{"--config=basic.yaml", "--port=9090", "--timeout=1m"},
+			env:  map[string]string{"BASIC_LOG_LEVEL": "debug", "BASIC_NAME": "env"},
+			want: "name=env port=9090 timeout=1m0s log.level=debug\n",
+		},
+		{
+			name: "defaults",
+			env:  map[string]string{"BASIC_DB_URL": "postgres://db/app"},
+			want: "name=basic port=8080 timeout=5s log.level=info\n",
+		},
+		{
+			name:    "required",
+			wantErr: goconfig.MissingRequiredError,
+		},
+		{
+			name:    "one of",
+			args:    []string{"--config=basic.yaml"},
+			env:     map[string]string{"BASIC_LOG_LEVEL": "trace"},
+			wantErr: goconfig.NotOneOfError,
+		},
+		{
+			name:    "strict",
+			args:    []string{"--config=" + filepath.Join("testdata", "typo.yaml")},
+			wantErr: goconfig.UnknownKeyError,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			var out bytes.Buffer
+			err := run(tc.args, &out)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("run err: got=%v want=%v", err, tc.wantErr)
+			}
+			if got := out.String(); got != tc.want {
+				t.Errorf("run output: got=%q want=%q", got, tc.want)
+			}
+		})
+	}
+}
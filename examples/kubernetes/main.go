@@ -0,0 +1,90 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Command kubernetes runs in a Kubernetes pod and is configured by a ConfigMap
+// mounted as a volume, the Deployment environment and its arguments. See
+// deployment.yaml. Kubernetes updates a mounted ConfigMap by atomically
+// replacing a symbolic link, which the watch follows, so edits to the
+// ConfigMap apply without restarting the pod.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/bretmckee/goconfig"
+	"github.com/spf13/pflag"
+)
+
+// Config is the configuration of the program.
+type Config struct {
+	Log struct {
+		Level string `koanf:"level" default:"info" oneof:"debug,info,warn,error"`
+	} `koanf:"log"`
+	Pod struct {
+		Name string `koanf:"name"`
+	} `koanf:"pod"`
+	Workers int `koanf:"workers" default:"1"`
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := run(ctx, os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// run loads the configuration and reports every change to it on out until
+// ctx is done.
+func run(ctx context.Context, args []string, out io.Writer) error {
+	f := pflag.NewFlagSet("kubernetes", pflag.ContinueOnError)
+	goconfig.AddConfigFlag(f)
+	f.Int("workers", 0, "number of workers")
+	if err := f.Parse(args); err != nil {
+		return err
+	}
+
+	c, err := goconfig.New("APP_", ".", goconfig.WithOnKeyChange(func(changes []goconfig.KeyChange) {
+		for _, ch := range changes {
+			fmt.Fprintf(out, "changed %s: %v -> %v\n", ch.Path, ch.Old, ch.New)
+		}
+	}))
+	if err != nil {
+		return err
+	}
+	var cfg Config
+	current := goconfig.NewHolder(cfg)
+	if err := goconfig.Watch(ctx, c, f, &cfg, current.OnChange); err != nil {
+		return err
+	}
+	current.Store(cfg)
+	fmt.Fprintf(out, "started %s: log.level=%s workers=%d\n", cfg.Pod.Name, cfg.Log.Level, cfg.Workers)
+
+	<-ctx.Done()
+	return nil
+}
@@ -0,0 +1,118 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// lineWriter sends every write, a line of output, to a channel.
+type lineWriter chan string
+
+func (w lineWriter) Write(p []byte) (int, error) {
+	w <- strings.TrimSuffix(string(p), "\n")
+	return len(p), nil
+}
+
+// configMap lays out a directory the way the kubelet mounts a ConfigMap:
+// each key is a link through the ..data link to a timestamped directory.
+type configMap struct {
+	t   *testing.T
+	dir string
+	gen int
+}
+
+func (cm *configMap) must(err error) {
+	cm.t.Helper()
+	if err != nil {
+		cm.t.Fatal(err)
+	}
+}
+
+// update atomically replaces the contents of the ConfigMap.
+func (cm *configMap) update(data map[string]string) {
+	cm.t.Helper()
+	cm.gen++
+	old := ""
+	if target, err := os.Readlink(filepath.Join(cm.dir, "..data")); err == nil {
+		old = target
+	}
+	ts := filepath.Join(cm.dir, fmt.Sprintf("..gen%d", cm.gen))
+	cm.must(os.Mkdir(ts, 0o755))
+	for k, v := range data {
+		cm.must(os.WriteFile(filepath.Join(ts, k), []byte(v), 0o644))
+		link := filepath.Join(cm.dir, k)
+		if _, err := os.Lstat(link); err != nil {
+			cm.must(os.Symlink(filepath.Join("..data", k), link))
+		}
+	}
+	tmp := filepath.Join(cm.dir, "..data_tmp")
+	cm.must(os.Symlink(filepath.Base(ts), tmp))
+	cm.must(os.Rename(tmp, filepath.Join(cm.dir, "..data")))
+	if old != "" {
+		cm.must(os.RemoveAll(filepath.Join(cm.dir, old)))
+	}
+}
+
+func next(t *testing.T, lines <-chan string) string {
+	t.Helper()
+	select {
+	case line := <-lines:
+		return line
+	case <-time.After(10 * time.Second):
+		t.Fatalf("timed out waiting for output")
+		return ""
+	}
+}
+
+func TestConfigMapUpdate(t *testing.T) {
+	cm := &configMap{t: t, dir: t.TempDir()}
+	cm.update(map[string]string{"config.yaml": "log:\n  level: info\n"})
+	t.Setenv("APP_POD_NAME", "app-7d9f")
+
+	lines := make(lineWriter, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	args := []string{"--config=" + filepath.Join(cm.dir, "config.yaml"), "--workers=4"}
+	go func() { done <- run(ctx, args, lines) }()
+
+	if got, want := next(t, lines), "started app-7d9f: log.level=info workers=4"; got != want {
+		t.Errorf("output: got=%q want=%q", got, want)
+	}
+
+	cm.update(map[string]string{"config.yaml": "log:\n  level: debug\n"})
+	if got, want := next(t, lines), "changed log.level: info -> debug"; got != want {
+		t.Errorf("output: got=%q want=%q", got, want)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("run err: got=%v want=nil", err)
+	}
+}
@@ -0,0 +1,135 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Command service is a cobra HTTP service that reloads its configuration when
+// its config files change, without restarting:
+//
+//	$ service serve --config=service.yaml
+//
+// The greeting is reloaded; the listen address is only read at startup.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/bretmckee/goconfig"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Config is the configuration of the service.
+type Config struct {
+	Listen   string `koanf:"listen" default:"localhost:8080"`
+	Greeting string `koanf:"greeting" default:"hello"`
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := newCommand(os.Stdout).ExecuteContext(ctx); err != nil {
+		os.Exit(1)
+	}
+}
+
+// newCommand returns the root command, which writes its progress to out.
+func newCommand(out io.Writer) *cobra.Command {
+	root := &cobra.Command{
+		Use:          "service",
+		Short:        "An example service configured with goconfig",
+		SilenceUsage: true,
+	}
+	serveCmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the greeting, reloading it when the config files change",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return serve(cmd.Context(), cmd.Flags(), out)
+		},
+	}
+	goconfig.AddConfigFlag(serveCmd.Flags())
+	serveCmd.Flags().String("greeting", "", "greeting returned to clients")
+	root.AddCommand(serveCmd)
+	return root
+}
+
+// serve runs the service until ctx is done.
+func serve(ctx context.Context, f *pflag.FlagSet, out io.Writer) error {
+	c, err := goconfig.New("SERVICE_", ".")
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var cfg Config
+	w, err := goconfig.NewWatcher(ctx, c, f, &cfg)
+	if err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("tcp", cfg.Listen)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "listening on %s\n", ln.Addr())
+
+	current := goconfig.NewHolder(cfg)
+	srv := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprintln(w, current.Load().Greeting)
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(ln) }()
+
+	updates := w.Subscribe()
+	for {
+		select {
+		case err := <-errc:
+			return err
+		case snap, ok := <-updates:
+			if !ok {
+				return shutdown(srv)
+			}
+			current.Store(snap.Config)
+			fmt.Fprintf(out, "config v%d: greeting=%q\n", snap.Version, snap.Config.Greeting)
+			if snap.Config.Listen != cfg.Listen {
+				fmt.Fprintf(out, "listen changed to %s, restart to apply\n", snap.Config.Listen)
+			}
+		}
+	}
+}
+
+// shutdown stops srv, waiting for active requests for a short while.
+func shutdown(srv *http.Server) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return srv.Shutdown(ctx)
+}
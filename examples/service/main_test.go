@@ -0,0 +1,108 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// lineWriter sends every write, a line of output, to a channel.
+type lineWriter chan string
+
+func (w lineWriter) Write(p []byte) (int, error) {
+	w <- strings.TrimSuffix(string(p), "\n")
+	return len(p), nil
+}
+
+func next(t *testing.T, lines <-chan string) string {
+	t.Helper()
+	select {
+	case line := <-lines:
+		return line
+	case <-time.After(10 * time.Second):
+		t.Fatalf("timed out waiting for output")
+		return ""
+	}
+}
+
+func get(t *testing.T, url string) string {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read response: %v", err)
+	}
+	return strings.TrimSpace(string(b))
+}
+
+func writeConfig(t *testing.T, name, contents string) {
+	t.Helper()
+	if err := os.WriteFile(name, []byte(contents), 0o600); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+}
+
+func TestServeReloads(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "service.yaml")
+	writeConfig(t, file, "listen: 127.0.0.1:0\ngreeting: hello\n")
+
+	lines := make(lineWriter, 10)
+	cmd := newCommand(lines)
+	cmd.SetArgs([]string{"serve", "--config=" + file})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- cmd.ExecuteContext(ctx) }()
+
+	addr := strings.TrimPrefix(next(t, lines), "listening on ")
+	if got, want := next(t, lines), `config v1: greeting="hello"`; got != want {
+		t.Errorf("output: got=%q want=%q", got, want)
+	}
+	url := "http://" + addr + "/"
+	if got, want := get(t, url), "hello"; got != want {
+		t.Errorf("greeting: got=%q want=%q", got, want)
+	}
+
+	writeConfig(t, file, "listen: 127.0.0.1:0\ngreeting: hi\n")
+	if got, want := next(t, lines), `config v2: greeting="hi"`; got != want {
+		t.Errorf("output: got=%q want=%q", got, want)
+	}
+	if got, want := get(t, url), "hi"; got != want {
+		t.Errorf("greeting: got=%q want=%q", got, want)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Execute err: got=%v want=nil", err)
+	}
+}
@@ -0,0 +1,146 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+var (
+	CommandFailedError = errors.New("command failed")
+)
+
+// DefaultExecTimeout is the timeout used when ExecOptions.Timeout is not set.
+const DefaultExecTimeout = 30 * time.Second
+
+// ExecOptions describes a command whose standard output is configuration.
+type ExecOptions struct {
+	// Command is the program to run and Args its arguments.
+	Command string
+	Args    []string
+	// Format is the format of the output, FormatJSON or FormatYAML. If empty,
+	// FormatJSON is used.
+	Format string
+	// Timeout limits how long the command may run. If zero,
+	// DefaultExecTimeout is used.
+	Timeout time.Duration
+	// Dir is the working directory of the command. If empty, the current
+	// directory is used.
+	Dir string
+	// Env, if not nil, is the environment of the command. Otherwise the
+	// command inherits the environment of the process.
+	Env []string
+}
+
+// ExecProvider is a koanf.Provider that runs a command and parses its
+// standard output. It can be added to a Config using WithSource.
+type ExecProvider struct {
+	opts ExecOptions
+}
+
+// NewExecProvider returns an ExecProvider running the command described by o.
+func NewExecProvider(o ExecOptions) *ExecProvider {
+	if o.Format == "" {
+		o.Format = FormatJSON
+	}
+	if o.Timeout == 0 {
+		o.Timeout = DefaultExecTimeout
+	}
+	return &ExecProvider{opts: o}
+}
+
+// ReadBytes runs the command and returns its standard output. A command that
+// exits with a non-zero status or runs longer than its timeout returns an
+// error wrapping CommandFailedError that includes its standard error.
+func (p *ExecProvider) ReadBytes() ([]byte, error) {
+	return p.ReadBytesContext(context.Background())
+}
+
+// ReadBytesContext is like ReadBytes, but kills the command when ctx is done.
+func (p *ExecProvider) ReadBytesContext(parent context.Context) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(parent, p.opts.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, p.opts.Command, p.opts.Args...)
+	cmd.Dir = p.opts.Dir
+	cmd.Env = p.opts.Env
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		text := err.Error()
+		switch {
+		case parent.Err() != nil:
+			err = parent.Err()
+			text = err.Error()
+		case ctx.Err() != nil:
+			err = ctx.Err()
+			text = fmt.Sprintf("timed out after %v", p.opts.Timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			text += ": " + msg
+		}
+		return nil, commandError{msg: fmt.Sprintf("exec %s: %s: %v", p.opts.Command, text, CommandFailedError), err: err}
+	}
+	return stdout.Bytes(), nil
+}
+
+// commandError is a CommandFailedError that also unwraps to its cause, such as
+// the context error of a cancelled load.
+type commandError struct {
+	msg string
+	err error
+}
+
+func (e commandError) Error() string        { return e.msg }
+func (e commandError) Unwrap() error        { return e.err }
+func (e commandError) Is(target error) bool { return target == CommandFailedError }
+
+// Read runs the command and parses its standard output.
+func (p *ExecProvider) Read() (map[string]interface{}, error) {
+	return p.ReadContext(context.Background())
+}
+
+// ReadContext is like Read, but kills the command when ctx is done.
+func (p *ExecProvider) ReadContext(ctx context.Context) (map[string]interface{}, error) {
+	parser, err := parserForFormat(p.opts.Format)
+	if err != nil {
+		return nil, err
+	}
+	b, err := p.ReadBytesContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	m, err := parser.Unmarshal(b)
+	if err != nil {
+		return nil, fmt.Errorf("exec %s: parse output: %w", p.opts.Command, err)
+	}
+	return m, nil
+}
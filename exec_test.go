@@ -0,0 +1,156 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/pflag"
+)
+
+func TestLoadViaExec(t *testing.T) {
+	cases := []struct {
+		name    string
+		opts    ExecOptions
+		want    testConfig
+		wantErr error
+	}{
+		{
+			name: "json",
+			opts: ExecOptions{
+				Command: "cat",
+				Args:    []string{testFileName(testGoodJSONConfig)},
+			},
+			want: testConfig{
+				Value1: testValue1,
+				Nested: testConfig1{
+					NestedVal: testValue2,
+				},
+			},
+		},
+		{
+			name: "yaml",
+			opts: ExecOptions{
+				Command: "cat",
+				Args:    []string{testFileName(testGoodYAMLConfig)},
+				Format:  FormatYAML,
+			},
+			want: testConfig{
+				Value1: testValue1,
+				Nested: testConfig1{
+					NestedVal: testValue2,
+				},
+			},
+		},
+		{
+			name: "non-zero exit",
+			opts: ExecOptions{
+				Command: "sh",
+				Args:    []string{"-c", "echo failed >&2; exit 3"},
+			},
+			wantErr: CommandFailedError,
+		},
+		{
+			name: "timeout",
+			opts: ExecOptions{
+				Command: "sleep",
+				Args:    []string{"5"},
+				Timeout: 10 * time.Millisecond,
+			},
+			wantErr: CommandFailedError,
+		},
+		{
+			name: "unknown format",
+			opts: ExecOptions{
+				Command: "cat",
+				Args:    []string{testFileName(testGoodJSONConfig)},
+				Format:  "ini",
+			},
+			wantErr: UnknownFormatError,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+
+			src := Source{Name: "exec", Provider: NewExecProvider(tc.opts)}
+			c, err := New(testPrefix, testDelimiter, WithSource(src))
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+
+			var cfg testConfig
+			err = c.Load(f, &cfg)
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Errorf("Load err: got=%v want=%v", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load err: got=%v want=nil", err)
+			}
+			if diff := cmp.Diff(tc.want, cfg); diff != "" {
+				t.Errorf("Load cfg mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExecProviderContextErrors(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	cases := []struct {
+		name    string
+		ctx     context.Context
+		timeout time.Duration
+		wantErr error
+	}{
+		{
+			name:    "cancelled",
+			ctx:     cancelled,
+			wantErr: context.Canceled,
+		},
+		{
+			name:    "timeout",
+			ctx:     context.Background(),
+			timeout: 10 * time.Millisecond,
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewExecProvider(ExecOptions{Command: "sleep", Args: []string{"5"}, Timeout: tc.timeout})
+			_, err := p.ReadBytesContext(tc.ctx)
+			for _, want := range []error{CommandFailedError, tc.wantErr} {
+				if !errors.Is(err, want) {
+					t.Errorf("ReadBytesContext err: got=%v want=%v", err, want)
+				}
+			}
+		})
+	}
+}
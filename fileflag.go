@@ -0,0 +1,64 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// WithFileFlag makes Load read config files from the flag name, such as
+// "config-file", instead of FileArgName. The environment variable naming
+// config files follows it, so the prefix APP_ and the flag config-file read
+// APP_CONFIG_FILE.
+func WithFileFlag(name string) Option {
+	return func(c *Config) {
+		c.fileFlag = name
+	}
+}
+
+// FileFlag returns the name of the flag that names config files.
+func (c Config) FileFlag() string {
+	if c.fileFlag == "" {
+		return FileArgName
+	}
+	return c.fileFlag
+}
+
+// AddFileFlag defines the flag named by FileFlag in f, like AddConfigFlag,
+// with the one letter shorthand, such as "c", unless it is empty.
+func (c Config) AddFileFlag(f *pflag.FlagSet, shorthand string) {
+	f.VarP(NewFileList(), c.FileFlag(), shorthand, "configuration file, may be repeated")
+}
+
+// fileEnvName returns the environment variable that names config files.
+func (c Config) fileEnvName() string {
+	return c.envName(strings.ReplaceAll(c.FileFlag(), "-", c.delimiter))
+}
+
+// controlKey reports whether key is set by a flag that controls how Load
+// reads configuration, such as the file flag, rather than by a value.
+func (c Config) controlKey(key string) bool {
+	return key == c.FileFlag() || c.profileKey(key) || c.setKey(key) || c.printConfigKey(key)
+}
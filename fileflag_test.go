@@ -0,0 +1,101 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestWithFileFlag(t *testing.T) {
+	file := testFileName(testGoodJSONConfig)
+	tests := []struct {
+		name string
+		args []string
+		env  string
+	}{
+		{name: "long", args: []string{"--config-file=" + file}},
+		{name: "shorthand", args: []string{"-c", file}},
+		{name: "env", env: file},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.env != "" {
+				t.Setenv(testPrefix+"CONFIG_FILE", tc.env)
+			}
+			c, err := New(testPrefix, testDelimiter, WithFileFlag("config-file"))
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			c.AddFileFlag(f, "c")
+			if err := f.Parse(tc.args); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+
+			var cfg testConfig
+			if err := c.Load(f, &cfg); err != nil {
+				t.Fatalf("Load err: got=%v want=nil", err)
+			}
+			if got, want := cfg.Value1, testValue1; got != want {
+				t.Errorf("Value1: got=%d want=%d", got, want)
+			}
+		})
+	}
+}
+
+func TestFileFlagDefault(t *testing.T) {
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	if got, want := c.FileFlag(), FileArgName; got != want {
+		t.Errorf("FileFlag: got=%q want=%q", got, want)
+	}
+	if got, want := c.fileEnvName(), fmt.Sprintf("%sCONFIG", testPrefix); got != want {
+		t.Errorf("fileEnvName: got=%q want=%q", got, want)
+	}
+}
+
+func TestWithFileFlagStrict(t *testing.T) {
+	t.Setenv(testPrefix+"CONFIG_FILE", testFileName(testGoodJSONConfig))
+	c, err := New(testPrefix, testDelimiter, WithFileFlag("config-file"), WithStrict())
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	c.AddFileFlag(f, "c")
+	if err := f.Parse(nil); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+
+	var cfg testConfig
+	if err := c.Load(f, &cfg); err != nil {
+		t.Fatalf("Load err: got=%v want=nil", err)
+	}
+	if got, want := cfg.Value1, testValue1; got != want {
+		t.Errorf("Value1: got=%d want=%d", got, want)
+	}
+}
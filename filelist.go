@@ -0,0 +1,80 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import "strings"
+
+// FileList is the value of the flag naming config files, defined by
+// AddConfigFlag and Config.AddFileFlag. Each Set adds one file, so names may
+// contain commas, and setting an empty name clears the list, so that
+// --config= loads none of the default files. It implements flag.Getter, whose
+// Get returns the names as a []string.
+type FileList struct {
+	// Append makes Set add to the default files instead of replacing them on
+	// the first Set, so that the files given on the command line override
+	// them.
+	Append bool
+
+	files   []string
+	changed bool
+}
+
+// NewFileList returns a FileList holding the default files.
+func NewFileList(files ...string) *FileList {
+	return &FileList{files: files}
+}
+
+// Set adds the file s, or clears the list if s is empty.
+func (l *FileList) Set(s string) error {
+	if !l.changed && !l.Append || s == "" {
+		l.files = []string{}
+	}
+	l.changed = true
+	if s != "" {
+		l.files = append(l.files, s)
+	}
+	return nil
+}
+
+// Get returns the files as a []string.
+func (l *FileList) Get() interface{} {
+	return l.Files()
+}
+
+// Files returns a copy of the files.
+func (l *FileList) Files() []string {
+	if l.files == nil {
+		return nil
+	}
+	return append([]string{}, l.files...)
+}
+
+// String returns the files separated by commas.
+func (l *FileList) String() string {
+	return strings.Join(l.files, ",")
+}
+
+// Type returns the name of the type shown in usage messages.
+func (l *FileList) Type() string {
+	return "fileList"
+}
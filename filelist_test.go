@@ -0,0 +1,106 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"flag"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/spf13/pflag"
+)
+
+func TestFileList(t *testing.T) {
+	cases := []struct {
+		name     string
+		defaults []string
+		append   bool
+		args     []string
+		want     []string
+	}{
+		{
+			name:     "defaults",
+			defaults: []string{"base.yaml"},
+			want:     []string{"base.yaml"},
+		},
+		{
+			name:     "replace defaults",
+			defaults: []string{"base.yaml"},
+			args:     []string{"a,b.yaml", "c.yaml"},
+			want:     []string{"a,b.yaml", "c.yaml"},
+		},
+		{
+			name:     "append to defaults",
+			defaults: []string{"base.yaml"},
+			append:   true,
+			args:     []string{"a.yaml", "b.yaml"},
+			want:     []string{"base.yaml", "a.yaml", "b.yaml"},
+		},
+		{
+			name:     "empty clears",
+			defaults: []string{"base.yaml"},
+			append:   true,
+			args:     []string{""},
+			want:     []string{},
+		},
+		{
+			name: "empty then file",
+			args: []string{"a.yaml", "", "b.yaml"},
+			want: []string{"b.yaml"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			l := NewFileList(tc.defaults...)
+			l.Append = tc.append
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			f.Var(l, FileArgName, testNoHelpMessage)
+			var args []string
+			for _, a := range tc.args {
+				args = append(args, fmt.Sprintf("--%s=%s", FileArgName, a))
+			}
+			if err := f.Parse(args); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+
+			var g flag.Getter = l
+			if diff := cmp.Diff(tc.want, g.Get()); diff != "" {
+				t.Errorf("Get mismatch (-want +got):\n%s", diff)
+			}
+
+			got, err := Config{}.configFiles(f)
+			if err != nil {
+				t.Fatalf("configFiles err: got=%v want=nil", err)
+			}
+			var names []string
+			for _, fa := range got {
+				names = append(names, fa.name)
+			}
+			if diff := cmp.Diff(tc.want, names, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("configFiles mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"reflect"
+
+	"github.com/knadh/koanf/providers/posflag"
+	"github.com/knadh/koanf/v2"
+	"github.com/spf13/pflag"
+)
+
+// flagTagName is the struct tag that names the flag setting a field when it
+// differs from the key, as in `flag:"db-url"`. Config files and the
+// environment still use the key.
+const flagTagName = "flag"
+
+// flagTags returns the flag names set by flag tags on fields of cfg, keyed by
+// their delimited keys.
+func (c Config) flagTags(cfg interface{}) (map[string]string, error) {
+	v, err := structValue(cfg)
+	if err != nil {
+		return nil, err
+	}
+	tags := make(map[string]string)
+	err = walkFields(v, "", c.delimiter, func(key string, sf reflect.StructField, _ reflect.Value) error {
+		if name := sf.Tag.Get(flagTagName); name != "" {
+			tags[key] = name
+		}
+		return nil
+	})
+	return tags, err
+}
+
+// fieldFlagName returns the name of the flag that sets the field sf with the
+// delimited key.
+func fieldFlagName(key string, sf reflect.StructField) string {
+	if name := sf.Tag.Get(flagTagName); name != "" {
+		return name
+	}
+	return key
+}
+
+// flagProvider returns the provider for the flags in f, mapping the flags
+// named by flag tags of cfg and the old keys of aliases to their keys.
+func (c Config) flagProvider(l *loaded, f *pflag.FlagSet, cfg interface{}) koanf.Provider {
+	// A cfg that is not a struct is reported by unmarshal.
+	tags, err := c.flagTags(cfg)
+	if err != nil {
+		tags = nil
+	}
+	if len(tags) == 0 && len(c.aliases) == 0 {
+		return posflag.Provider(f, ".", l.k)
+	}
+	l.flagNames = tags
+	keys := make(map[string]string, len(tags))
+	for key, name := range tags {
+		keys[name] = key
+	}
+	return posflag.ProviderWithFlag(f, ".", l.k, func(fl *pflag.Flag) (string, interface{}) {
+		key, ok := keys[fl.Name]
+		if !ok {
+			key = fl.Name
+		}
+		// Renaming here, rather than when the layer is loaded, keeps the
+		// defaults of unchanged flags from overriding the new key.
+		if a, ok := c.aliasKey(key); ok {
+			if fl.Changed {
+				if a.warn {
+					c.logf("%s: flag --%s is deprecated, use %q", SourceFlags, fl.Name, a.key)
+				}
+				if l.flagNames == nil {
+					l.flagNames = make(map[string]string)
+				}
+				l.flagNames[a.key] = fl.Name
+			}
+			key = a.key
+		}
+		return key, posflag.FlagVal(f, fl)
+	})
+}
+
+// flagName returns the name of the flag that sets key.
+func (l *loaded) flagName(key string) string {
+	if name, ok := l.flagNames[key]; ok {
+		return name
+	}
+	return key
+}
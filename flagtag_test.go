@@ -0,0 +1,74 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+type testFlagTagConfig struct {
+	DB struct {
+		URL string `koanf:"url,required" flag:"db-url"`
+	} `koanf:"db"`
+}
+
+func TestFlagTag(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "config.yaml")
+	writeTestFile(t, name, "db:\n  url: from-file\n")
+
+	cases := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "file", args: []string{fmt.Sprintf("--%s=%s", FileArgName, name)}, want: "from-file"},
+		{name: "flag", args: []string{fmt.Sprintf("--%s=%s", FileArgName, name), "--db-url=from-flag"}, want: "from-flag"},
+		{name: "flag only", args: []string{"--db-url=from-flag"}, want: "from-flag"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			AddConfigFlag(f)
+			f.String("db-url", "default", testNoHelpMessage)
+			if err := f.Parse(tc.args); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+			c, err := New(testPrefix, testDelimiter)
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+
+			var cfg testFlagTagConfig
+			if err := c.Load(f, &cfg); err != nil {
+				t.Fatalf("Load err: got=%v want=nil", err)
+			}
+			if got := cfg.DB.URL; got != tc.want {
+				t.Errorf("URL: got=%q want=%q", got, tc.want)
+			}
+		})
+	}
+}
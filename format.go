@@ -0,0 +1,125 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/knadh/koanf/parsers/json"
+	kyaml "github.com/knadh/koanf/parsers/yaml"
+	"github.com/knadh/koanf/v2"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	UnknownFormatError = errors.New("unknown configuration format")
+)
+
+// Formats understood by the package.
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+	FormatHCL  = "hcl"
+	FormatTOML = "toml"
+	FormatEnv  = "env"
+)
+
+// detectFormat returns the format of the configuration named by name based on
+// its extension. For URLs only the path is considered. Names without a
+// recognized extension are assumed to be JSON. AgeExtension is ignored.
+func detectFormat(name string) string {
+	if strings.Contains(name, "://") {
+		if u, err := url.Parse(name); err == nil {
+			name = u.Path
+		}
+	}
+	name = trimAgeExtension(name)
+	if path.Base(name) == ".env" {
+		return FormatEnv
+	}
+	switch strings.ToLower(path.Ext(name)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".hcl", ".tfvars":
+		return FormatHCL
+	case ".toml":
+		return FormatTOML
+	case ".env":
+		return FormatEnv
+	default:
+		return FormatJSON
+	}
+}
+
+// parserForFormat returns the koanf parser used for format. Env files are
+// handled by Config.parserFor because parsing them depends on the Config.
+func parserForFormat(format string) (koanf.Parser, error) {
+	switch strings.ToLower(format) {
+	case FormatJSON:
+		return json.Parser(), nil
+	case FormatYAML, "yml":
+		return kyaml.Parser(), nil
+	case FormatHCL, "tfvars":
+		return hclParser{}, nil
+	case FormatTOML:
+		return tomlParser{}, nil
+	default:
+		return nil, fmt.Errorf("format %q: %w", format, UnknownFormatError)
+	}
+}
+
+// parserFor returns the koanf parser for the configuration named by name.
+func (c Config) parserFor(name string) (koanf.Parser, error) {
+	format := detectFormat(name)
+	if isStdin(name) {
+		format = c.stdinFormatOrDefault()
+	}
+	return c.formatParser(format)
+}
+
+// formatParser returns the koanf parser for format, including env files.
+func (c Config) formatParser(format string) (koanf.Parser, error) {
+	if strings.ToLower(format) == FormatEnv {
+		return envFileParser{c: c}, nil
+	}
+	return parserForFormat(format)
+}
+
+// marshalYAML encodes v as YAML using two space indentation.
+func marshalYAML(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	e := yaml.NewEncoder(&buf)
+	e.SetIndent(2)
+	if err := e.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := e.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,86 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_detectFormat(t *testing.T) {
+	cases := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{
+			name:  "json",
+			value: "config.json",
+			want:  FormatJSON,
+		},
+		{
+			name:  "yaml",
+			value: "config.yaml",
+			want:  FormatYAML,
+		},
+		{
+			name:  "yml uppercase",
+			value: "CONFIG.YML",
+			want:  FormatYAML,
+		},
+		{
+			name:  "tfvars",
+			value: "prod.tfvars",
+			want:  FormatHCL,
+		},
+		{
+			name:  "toml",
+			value: "config.toml",
+			want:  FormatTOML,
+		},
+		{
+			name:  "no extension",
+			value: "config",
+			want:  FormatJSON,
+		},
+		{
+			name:  "url",
+			value: "s3://bucket/dir/config.yaml",
+			want:  FormatYAML,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got, want := detectFormat(tc.value), tc.want; got != want {
+				t.Errorf("detectFormat(%q): got=%q want=%q", tc.value, got, want)
+			}
+		})
+	}
+}
+
+func Test_parserForFormatUnknown(t *testing.T) {
+	if _, err := parserForFormat("ini"); !errors.Is(err, UnknownFormatError) {
+		t.Errorf("parserForFormat err: got=%v want=%v", err, UnknownFormatError)
+	}
+}
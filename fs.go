@@ -0,0 +1,94 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// DefaultFSPollInterval is how often Watch polls config files read from a
+// file system set with WithFS, unless WithPollInterval sets another interval.
+const DefaultFSPollInterval = time.Second
+
+// WithFS makes Load read local config files from fsys, such as an embed.FS or
+// an fstest.MapFS, instead of the operating system. Paths are resolved inside
+// fsys: they are slash separated, and leading slashes and ./ are removed, so
+// --config=/conf/app.yaml and --config=./conf/app.yaml both read conf/app.yaml.
+// Combined with go:embed, this ships default config files inside the binary:
+//
+//	//go:embed conf
+//	var conf embed.FS
+//
+//	c, err := goconfig.New("APP_", ".", goconfig.WithFS(conf))
+//
+// Files are read from fsys only, so files on disk cannot be mixed with them.
+// Because fsys cannot report changes, Watch polls the files instead of
+// subscribing to file system events, every DefaultFSPollInterval unless
+// WithPollInterval sets another interval. Streaming and the parse cache do not
+// apply to files in fsys.
+func WithFS(fsys fs.FS) Option {
+	return func(c *Config) {
+		c.fsys = fsys
+	}
+}
+
+// fsPath returns the local config file name as a path in the file system set
+// by WithFS. Other names are returned unchanged.
+func (c Config) fsPath(name string) string {
+	if c.fsys == nil || !localFile(name) || isStdin(name) {
+		return name
+	}
+	p := strings.TrimLeft(path.Clean(filepath.ToSlash(name)), "/")
+	if p == "" {
+		return "."
+	}
+	return p
+}
+
+// localFile reports whether name is a local config file, as opposed to a URL.
+func localFile(name string) bool {
+	return !strings.Contains(name, "://")
+}
+
+// fsProvider returns a provider reading name from the file system set by
+// WithFS.
+func (c Config) fsProvider(name string) koanf.Provider {
+	return bytesProvider(func() ([]byte, error) {
+		return fs.ReadFile(c.fsys, name)
+	})
+}
+
+// fsPollInterval returns how often Watch polls files in the file system set
+// by WithFS.
+func (c Config) fsPollInterval() time.Duration {
+	if c.pollInterval > 0 {
+		return c.pollInterval
+	}
+	return DefaultFSPollInterval
+}
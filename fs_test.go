@@ -0,0 +1,96 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/spf13/pflag"
+)
+
+func TestWithFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"conf/app.json": {Data: []byte(fmt.Sprintf(`{"%s": %d}`, testKey1, testValue1))},
+	}
+	tests := []struct {
+		name    string
+		file    string
+		want    int
+		wantErr error
+	}{
+		{name: "present", file: "conf/app.json", want: testValue1},
+		{name: "rooted", file: "/conf/app.json", want: testValue1},
+		{name: "dot", file: "./conf/../conf/app.json", want: testValue1},
+		{name: "missing", file: "conf/missing.json", wantErr: fs.ErrNotExist},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			AddConfigFlag(f)
+			if err := f.Parse([]string{"--" + FileArgName + "=" + tc.file}); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+			c, err := New(testPrefix, testDelimiter, WithFS(fsys))
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+
+			var cfg testConfig
+			err = c.Load(f, &cfg)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Load err: got=%v want=%v", err, tc.wantErr)
+			}
+			if got := cfg.Value1; got != tc.want {
+				t.Errorf("Value1: got=%d want=%d", got, tc.want)
+			}
+		})
+	}
+}
+
+//go:embed testdata
+var testEmbedded embed.FS
+
+func TestWithEmbedFS(t *testing.T) {
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	AddConfigFlag(f)
+	if err := f.Parse([]string{"--" + FileArgName + "=" + testGoodJSONConfig}); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	c, err := New(testPrefix, testDelimiter, WithFS(testEmbedded), WithBaseDir(testDataDir))
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	var got testConfig
+	if err := c.Load(f, &got); err != nil {
+		t.Fatalf("Load err: got=%v want=nil", err)
+	}
+	if got, want := got.Value1, testValue1; got != want {
+		t.Errorf("Value1: got=%d want=%d", got, want)
+	}
+}
@@ -0,0 +1,315 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// helpTagName is the struct tag describing a field, as in
+// `help:"port to listen on"`. Generate writes it as a comment.
+const helpTagName = "help"
+
+// sampleField is a field written by Generate.
+type sampleField struct {
+	comment []string
+	value   interface{}
+	// raw is set if value is the text of a default tag, written unquoted.
+	raw bool
+}
+
+// Generate returns a sample config file for cfg in format, FormatYAML,
+// FormatTOML or FormatEnv, for example for a "config init" command. Every field is written
+// in struct order with its help tag, allowed values and whether it is
+// required as a comment. Values are taken from cfg if they are not zero, then
+// from default tags and WithDefaults. Fields tagged secret are left empty.
+func (c Config) Generate(cfg interface{}, format string) ([]byte, error) {
+	v, err := structValue(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("Generate: %w", err)
+	}
+	var defaults map[string]interface{}
+	if c.defaults != nil {
+		if defaults, err = c.flatten(c.defaults); err != nil {
+			return nil, fmt.Errorf("Generate defaults: %w", err)
+		}
+	}
+
+	switch strings.ToLower(format) {
+	case FormatYAML, "yml":
+		n, err := c.sampleYAML(v, "", defaults, make(map[reflect.Type]bool))
+		if err != nil {
+			return nil, fmt.Errorf("Generate: %w", err)
+		}
+		var buf bytes.Buffer
+		e := yaml.NewEncoder(&buf)
+		e.SetIndent(2)
+		if err := e.Encode(n); err != nil {
+			return nil, fmt.Errorf("Generate marshal: %w", err)
+		}
+		if err := e.Close(); err != nil {
+			return nil, fmt.Errorf("Generate marshal: %w", err)
+		}
+		return buf.Bytes(), nil
+	case FormatEnv:
+		var buf bytes.Buffer
+		err := walkFields(v, "", c.delimiter, func(key string, sf reflect.StructField, fv reflect.Value) error {
+			f, err := c.sampleField(key, sf, fv, defaults)
+			if err != nil {
+				return err
+			}
+			for _, line := range f.comment {
+				fmt.Fprintf(&buf, "# %s\n", line)
+			}
+			fmt.Fprintf(&buf, "%s=%s\n", c.envName(key), envFileValue(f))
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Generate: %w", err)
+		}
+		return buf.Bytes(), nil
+	case FormatTOML:
+		var buf bytes.Buffer
+		if err := c.sampleTOML(&buf, v, nil, defaults, make(map[reflect.Type]bool)); err != nil {
+			return nil, fmt.Errorf("Generate: %w", err)
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("Generate format %q: %w", format, UnknownFormatError)
+	}
+}
+
+// sampleYAML returns the mapping node holding the fields of the struct v,
+// whose keys start with prefix. expanding holds the struct types being
+// written, and a nil pointer to one of them is skipped, as walkFields does, so
+// that recursive types end.
+func (c Config) sampleYAML(v reflect.Value, prefix string, defaults map[string]interface{}, expanding map[reflect.Type]bool) (*yaml.Node, error) {
+	m := &yaml.Node{Kind: yaml.MappingNode}
+	t := v.Type()
+	expanding[t] = true
+	defer delete(expanding, t)
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, ok := fieldName(sf)
+		if !ok {
+			continue
+		}
+		key := prefix + name
+		fv := v.Field(i)
+		keyNode := &yaml.Node{Kind: yaml.ScalarNode, Value: name}
+		var valueNode *yaml.Node
+		if isNested(sf.Type) {
+			if fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					if expanding[fv.Type().Elem()] {
+						continue
+					}
+					fv = reflect.New(fv.Type().Elem())
+				}
+				fv = fv.Elem()
+			}
+			var err error
+			if valueNode, err = c.sampleYAML(fv, key+c.delimiter, defaults, expanding); err != nil {
+				return nil, err
+			}
+			if help := sf.Tag.Get(helpTagName); help != "" {
+				keyNode.HeadComment = help
+			}
+		} else {
+			f, err := c.sampleField(key, sf, fv, defaults)
+			if err != nil {
+				return nil, err
+			}
+			keyNode.HeadComment = strings.Join(f.comment, "\n")
+			valueNode = &yaml.Node{}
+			if f.raw {
+				valueNode.Kind, valueNode.Value = yaml.ScalarNode, f.value.(string)
+			} else if err := valueNode.Encode(f.value); err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+		}
+		m.Content = append(m.Content, keyNode, valueNode)
+	}
+	return m, nil
+}
+
+// sampleTOML writes the fields of the struct v to buf as the TOML table named
+// by path, which is empty for the root. Leaf fields are written before the
+// tables of nested structs, since TOML assigns every key following a table
+// header to that table. Recursive types end as in sampleYAML.
+func (c Config) sampleTOML(buf *bytes.Buffer, v reflect.Value, path []string, defaults map[string]interface{}, expanding map[reflect.Type]bool) error {
+	t := v.Type()
+	expanding[t] = true
+	defer delete(expanding, t)
+
+	type table struct {
+		name string
+		sf   reflect.StructField
+		v    reflect.Value
+	}
+	var tables []table
+	prefix := ""
+	if len(path) > 0 {
+		prefix = strings.Join(path, c.delimiter) + c.delimiter
+	}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, ok := fieldName(sf)
+		if !ok {
+			continue
+		}
+		key := prefix + name
+		fv := v.Field(i)
+		if isNested(sf.Type) {
+			if fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					if expanding[fv.Type().Elem()] {
+						continue
+					}
+					fv = reflect.New(fv.Type().Elem())
+				}
+				fv = fv.Elem()
+			}
+			tables = append(tables, table{name: name, sf: sf, v: fv})
+			continue
+		}
+		f, err := c.sampleField(key, sf, fv, defaults)
+		if err != nil {
+			return err
+		}
+		for _, line := range f.comment {
+			fmt.Fprintf(buf, "# %s\n", line)
+		}
+		line, err := tomlKeyValue(name, f)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		buf.WriteString(line)
+	}
+
+	for _, tb := range tables {
+		if buf.Len() > 0 {
+			buf.WriteByte('\n')
+		}
+		if help := tb.sf.Tag.Get(helpTagName); help != "" {
+			fmt.Fprintf(buf, "# %s\n", help)
+		}
+		tablePath := append(append([]string(nil), path...), tb.name)
+		var names []string
+		for _, n := range tablePath {
+			names = append(names, tomlKey(n))
+		}
+		fmt.Fprintf(buf, "[%s]\n", strings.Join(names, "."))
+		if err := c.sampleTOML(buf, tb.v, tablePath, defaults, expanding); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tomlKey returns name as a TOML key, quoted unless it is a bare key.
+func tomlKey(name string) string {
+	if name == "" || strings.Trim(name, "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789_-") != "" {
+		return strconv.Quote(name)
+	}
+	return name
+}
+
+// tomlKeyValue returns the TOML line setting name to the value of f. The text
+// of a default tag is written as the scalar it holds, and a field without a
+// value, which TOML cannot express, is commented out.
+func tomlKeyValue(name string, f sampleField) (string, error) {
+	value := f.value
+	if f.raw {
+		if err := yaml.Unmarshal([]byte(f.value.(string)), &value); err != nil {
+			value = f.value
+		}
+	}
+	if value == nil {
+		return fmt.Sprintf("# %s =\n", tomlKey(name)), nil
+	}
+	var buf bytes.Buffer
+	e := toml.NewEncoder(&buf)
+	e.SetTablesInline(true)
+	if err := e.Encode(map[string]interface{}{name: value}); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// sampleField returns the comment and value written by Generate for the leaf
+// field sf with the value fv.
+func (c Config) sampleField(key string, sf reflect.StructField, fv reflect.Value, defaults map[string]interface{}) (sampleField, error) {
+	var f sampleField
+	if help := sf.Tag.Get(helpTagName); help != "" {
+		f.comment = append(f.comment, help)
+	}
+	if allowed, ok := sf.Tag.Lookup(oneofTagName); ok {
+		f.comment = append(f.comment, "One of: "+strings.Join(strings.Split(allowed, ","), ", ")+".")
+	}
+	if hasTagOption(sf, requiredOption) {
+		f.comment = append(f.comment, "Required.")
+	}
+
+	d, hasDefault := sf.Tag.Lookup(defaultTagName)
+	switch {
+	case isSecret(sf):
+		f.comment = append(f.comment, "Secret.")
+		f.value = reflect.Zero(sf.Type).Interface()
+	case !fv.IsZero():
+		f.value = fv.Interface()
+	case hasDefault:
+		f.value, f.raw = d, sf.Type.Kind() != reflect.String
+	case defaults[key] != nil:
+		f.value = defaults[key]
+	default:
+		f.value = fv.Interface()
+	}
+	if !f.raw {
+		v, err := dumpValue(f.value)
+		if err != nil {
+			return sampleField{}, fmt.Errorf("%s: %w", key, err)
+		}
+		f.value = v
+	}
+	return f, nil
+}
+
+// envFileValue returns the value of f as written in an env file.
+func envFileValue(f sampleField) string {
+	if f.value == nil {
+		return ""
+	}
+	s := manifestValue(f.value)
+	if f.raw || !strings.ContainsAny(s, " \t#\"'\\") {
+		return s
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`).Replace(s) + `"`
+}
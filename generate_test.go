@@ -0,0 +1,182 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/pflag"
+)
+
+type testGenerateConfig struct {
+	Name    string        `koanf:"name,required" help:"name of the service"`
+	Level   string        `koanf:"level" default:"info" oneof:"debug,info"`
+	Timeout time.Duration `koanf:"timeout" default:"5s"`
+	Port    int           `koanf:"port" default:"8080"`
+	Tags    []string      `koanf:"tags"`
+	DB      struct {
+		Host     string `koanf:"host" help:"database host"`
+		Password string `koanf:"password" secret:"true"`
+	} `koanf:"db" help:"database connection"`
+}
+
+func TestGenerate(t *testing.T) {
+	cfg := testGenerateConfig{Name: "app", Tags: []string{"a", "b"}}
+	cfg.DB.Password = "hunter2"
+
+	var defaults testGenerateConfig
+	defaults.DB.Host = "localhost"
+	c, err := New(testPrefix, testDelimiter, WithDefaults(defaults))
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	cases := []struct {
+		format  string
+		want    string
+		wantErr error
+	}{
+		{
+			format: FormatYAML,
+			want: `# name of the service
+# Required.
+name: app
+# One of: debug, info.
+level: info
+timeout: 5s
+port: 8080
+tags:
+  - a
+  - b
+# database connection
+db:
+  # database host
+  host: localhost
+  # Secret.
+  password: ""
+`,
+		},
+		{
+			format: FormatEnv,
+			want: `# name of the service
+# Required.
+` + testPrefix + `NAME=app
+# One of: debug, info.
+` + testPrefix + `LEVEL=info
+` + testPrefix + `TIMEOUT=5s
+` + testPrefix + `PORT=8080
+` + testPrefix + `TAGS=a,b
+# database host
+` + testPrefix + `DB_HOST=localhost
+# Secret.
+` + testPrefix + `DB_PASSWORD=
+`,
+		},
+		{
+			format: FormatTOML,
+			want: `# name of the service
+# Required.
+name = 'app'
+# One of: debug, info.
+level = 'info'
+timeout = '5s'
+port = 8080
+tags = ['a', 'b']
+
+# database connection
+[db]
+# database host
+host = 'localhost'
+# Secret.
+password = ''
+`,
+		},
+		{
+			format:  "xml",
+			wantErr: UnknownFormatError,
+		},
+	}
+	for _, tc := range cases {
+		got, err := c.Generate(&cfg, tc.format)
+		if !errors.Is(err, tc.wantErr) {
+			t.Errorf("Generate(%q) err: got=%v want=%v", tc.format, err, tc.wantErr)
+			continue
+		}
+		if diff := cmp.Diff(tc.want, string(got)); diff != "" {
+			t.Errorf("Generate(%q) mismatch (-want +got):\n%s", tc.format, diff)
+		}
+	}
+}
+
+func TestGenerateRecursive(t *testing.T) {
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	cfg := testRecursiveConfig{Name: "a", Next: &testRecursiveConfig{Name: "b"}}
+
+	got, err := c.Generate(&cfg, FormatYAML)
+	if err != nil {
+		t.Fatalf("Generate err: got=%v want=nil", err)
+	}
+	want := `# Required.
+name: a
+next:
+  # Required.
+  name: b
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("Generate mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateTOMLLoads(t *testing.T) {
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	want := testGenerateConfig{Name: "app", Level: "debug", Timeout: time.Second, Port: 80, Tags: []string{"a"}}
+	want.DB.Host = "db"
+
+	b, err := c.Generate(&want, FormatTOML)
+	if err != nil {
+		t.Fatalf("Generate err: got=%v want=nil", err)
+	}
+	path := filepath.Join(t.TempDir(), "config.toml")
+	writeTestFile(t, path, string(b))
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	AddConfigFlag(f)
+	if err := f.Parse([]string{"--" + FileArgName + "=" + path}); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	var got testGenerateConfig
+	if err := c.Load(f, &got); err != nil {
+		t.Fatalf("Load err: got=%v want=nil", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Load mismatch (-want +got):\n%s", diff)
+	}
+}
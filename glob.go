@@ -0,0 +1,58 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// globMeta are the characters that make a config file name a glob pattern.
+const globMeta = `*?[`
+
+// expandGlob returns the config files matched by fa if its name is a glob
+// pattern, such as conf.d/*.yaml, in lexical order. A pattern that matches
+// nothing expands to no files. Other names, including URLs, are returned
+// unchanged.
+func (c Config) expandGlob(fa fileArg) ([]fileArg, error) {
+	if !localFile(fa.name) || !strings.ContainsAny(fa.name, globMeta) {
+		return []fileArg{fa}, nil
+	}
+	var matches []string
+	var err error
+	if c.fsys != nil {
+		matches, err = fs.Glob(c.fsys, fa.name)
+	} else {
+		matches, err = filepath.Glob(fa.name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("glob %q: %w", fa.name, err)
+	}
+	args := make([]fileArg, len(matches))
+	for i, m := range matches {
+		args[i] = fileArg{name: m, mount: fa.mount, optional: fa.optional}
+	}
+	return args, nil
+}
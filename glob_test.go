@@ -0,0 +1,108 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/pflag"
+)
+
+func TestLoadGlob(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "20-override.yaml"), "name: override\n")
+	writeTestFile(t, filepath.Join(dir, "10-base.yaml"), "name: base\ndb:\n  host: localhost\n")
+	writeTestFile(t, filepath.Join(dir, "notes.txt"), "ignored")
+
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	AddConfigFlag(f)
+	if err := f.Parse([]string{"--" + FileArgName + "=" + filepath.Join(dir, "*.yaml")}); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	var got testMountConfig
+	if err := c.Load(f, &got); err != nil {
+		t.Fatalf("Load err: got=%v want=nil", err)
+	}
+	var want testMountConfig
+	want.Name = "override"
+	want.DB.Host = "localhost"
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Load mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestExpandGlob(t *testing.T) {
+	fsys := fstest.MapFS{
+		"conf.d/b.yaml": {},
+		"conf.d/a.yaml": {},
+		"conf.d/c.json": {},
+	}
+	c, err := New(testPrefix, testDelimiter, WithFS(fsys))
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	cases := []struct {
+		arg     fileArg
+		want    []fileArg
+		wantErr bool
+	}{
+		{
+			arg:  fileArg{name: "conf.d/*.yaml", mount: "m"},
+			want: []fileArg{{name: "conf.d/a.yaml", mount: "m"}, {name: "conf.d/b.yaml", mount: "m"}},
+		},
+		{
+			arg:  fileArg{name: "empty.d/*.yaml"},
+			want: []fileArg{},
+		},
+		{
+			arg:  fileArg{name: "conf.d/c.json"},
+			want: []fileArg{{name: "conf.d/c.json"}},
+		},
+		{
+			arg:  fileArg{name: "https://example.com/*.yaml"},
+			want: []fileArg{{name: "https://example.com/*.yaml"}},
+		},
+		{
+			arg:     fileArg{name: "conf.d/[.yaml"},
+			wantErr: true,
+		},
+	}
+	for _, tc := range cases {
+		got, err := c.expandGlob(tc.arg)
+		if gotErr := err != nil; gotErr != tc.wantErr {
+			t.Errorf("expandGlob(%q) err: got=%v wantErr=%t", tc.arg.name, err, tc.wantErr)
+			continue
+		}
+		if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(fileArg{})); err == nil && diff != "" {
+			t.Errorf("expandGlob(%q) mismatch (-want +got):\n%s", tc.arg.name, diff)
+		}
+	}
+}
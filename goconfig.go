@@ -23,15 +23,20 @@
 package goconfig
 
 import (
+	"context"
 	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"io"
+	"io/fs"
+	"os"
+	"reflect"
 	"strings"
+	"time"
 
-	"github.com/knadh/koanf/parsers/json"
+	"github.com/bretmckee/goconfig/core"
+	"github.com/go-playground/validator/v10"
 	"github.com/knadh/koanf/providers/env"
-	"github.com/knadh/koanf/providers/file"
-	"github.com/knadh/koanf/providers/posflag"
 	"github.com/knadh/koanf/v2"
 	"github.com/spf13/pflag"
 )
@@ -47,55 +52,643 @@ const FileArgName = "config"
 type Config struct {
 	prefix    string
 	delimiter string
+	stores    map[string]ObjectStore
+	http      HTTPOptions
+	policy    SourcePolicy
+	sources   []Source
+	compiled  *compiledOverlay
+	metadata  bool
+	defaults  interface{}
+	timing    bool
+
+	streaming       bool
+	streamThreshold int64
+	cachePath       string
+	strict          bool
+	strictPrefix    string
+	conflicts       ConflictMode
+	allErrors       bool
+	logger          Logger
+	precedence      []string
+	baseDir         string
+	envSep          string
+	envTransform    func(string) string
+	clk             Clock
+	fileFlag        string
+	translator      Translator
+	stdin           *stdinReader
+	stdinFormat     string
+	raw             []rawConfig
+	noFiles         bool
+	search          *search
+	profiles        bool
+	setFlag         bool
+	interpolate     bool
+	references      bool
+	secretFiles     bool
+	ageIdentities   []string
+	decrypters      map[string]DecryptFunc
+	aliases         map[string]alias
+	migrations      map[int]Migration
+	mustLoadHandler func(err error)
+	printConfig     io.Writer
+	writeOptions    WriteOptions
+	fsys            fs.FS
+
+	pollInterval     time.Duration
+	requireAnySource bool
+	validator        *validator.Validate
+
+	onKeyChange func([]KeyChange)
 }
 
 // New returns a Config initialized with prefix and delimiter. For information
-// about how these values are used see the description of load.
-func New(envPrefix, flagDelimiter string) (Config, error) {
-	if len(flagDelimiter) != 1 {
-		return Config{}, fmt.Errorf("invalid delimiter %q: %w", flagDelimiter, BadDelimiterError)
-	}
-	return Config{
+// about how these values are used see the description of load. Optional
+// behavior can be enabled by passing opts, which may also replace prefix and
+// delimiter using WithEnvPrefix and WithDelimiter. New code may prefer the v2
+// package, where they are only set by options.
+func New(envPrefix, flagDelimiter string, opts ...Option) (Config, error) {
+	c := Config{
 		prefix:    envPrefix,
 		delimiter: flagDelimiter,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if len(c.delimiter) != 1 {
+		return Config{}, fmt.Errorf("invalid delimiter %q: %w", c.delimiter, BadDelimiterError)
+	}
+	if err := checkPrecedence(c.precedence); err != nil {
+		return Config{}, err
+	}
+	return c, nil
 }
 
+// updateEnv returns the key set by the environment variable s, or an empty
+// string if s should be ignored.
 func (c Config) updateEnv(s string) string {
-	return strings.Replace(strings.ToLower(strings.TrimPrefix(s, c.prefix)), "_", c.delimiter, -1)
+	if c.ageEnv(s) || s == c.fileEnvName() {
+		return ""
+	}
+	if c.envTransform != nil {
+		return c.envTransform(s)
+	}
+	return strings.Replace(strings.ToLower(strings.TrimPrefix(s, c.prefix)), c.envSeparator(), c.delimiter, -1)
 }
 
-// Load loads values into cfg from environment variables, flags and json files.
-func (c Config) Load(f *pflag.FlagSet, cfg interface{}) error {
-	const unmarshalEverything = ""
+// AddConfigFlag defines the flag named FileArgName in f, used to name config
+// files. Its value is a FileList, so each file is given with a separate flag
+// and names may contain commas:
+//
+//	$ ./prog --config=base.yaml --config=prod.yaml
+func AddConfigFlag(f *pflag.FlagSet) {
+	f.Var(NewFileList(), FileArgName, "configuration file, may be repeated")
+}
 
-	k := koanf.New(c.delimiter)
+// configFiles returns the config files named by the environment variable for
+// the file flag, such as APP_CONFIG for the prefix APP_, followed by those
+// provided on the commandline if there is a file flag. The flag may be a
+// FileList, or any flag.Getter whose Get returns a []string, a string slice,
+// which splits its values on commas, or a string array, which does not. Glob
+// patterns are expanded, directories replaced by the config files they
+// contain and the files of the active profiles added. The search paths are
+// only used if no file is named and the flag was not explicitly cleared.
+func (c Config) configFiles(f *pflag.FlagSet) ([]fileArg, error) {
+	if c.noFiles {
+		return nil, nil
+	}
+	name := c.FileFlag()
+	var ss []string
+	if v := os.Getenv(c.fileEnvName()); v != "" {
+		ss = splitFileList(v)
+	}
+	// cleared is set if the flag holds an empty, rather than nil, list, as
+	// a FileList does after --config=.
+	cleared := false
+	if p := f.Lookup(name); p != nil {
+		var flagFiles []string
+		var err error
+		g, isGetter := p.Value.(flag.Getter)
+		switch {
+		case isGetter:
+			var ok bool
+			if flagFiles, ok = g.Get().([]string); !ok {
+				err = fmt.Errorf("value of type %T is not a []string", g.Get())
+			}
+			cleared = flagFiles != nil && len(flagFiles) == 0
+		case p.Value.Type() == "stringArray":
+			flagFiles, err = f.GetStringArray(name)
+		default:
+			flagFiles, err = f.GetStringSlice(name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("get %s flag: %v", name, err)
+		}
+		ss = append(ss, flagFiles...)
+	}
+	if len(ss) == 0 && !cleared {
+		ss = c.searchFiles()
+	}
+	args, err := c.fileArgs(ss)
+	if err != nil {
+		return nil, err
+	}
+	profiles, err := c.Profiles(f)
+	if err != nil {
+		return nil, err
+	}
+	return withProfiles(args, profiles), nil
+}
 
-	// Load the config files provided on the commandline if there is an argument
-	// named FileArgName.
-	if p := f.Lookup(FileArgName); p != nil {
-		ss, err := f.GetStringSlice(FileArgName)
+// fileArgs returns the config files named by ss, expanding glob patterns and
+// replacing directories by the config files they contain.
+func (c Config) fileArgs(ss []string) ([]fileArg, error) {
+	var args []fileArg
+	for _, s := range ss {
+		fa := parseFileArg(s)
+		fa.name = c.resolveFile(fa.name)
+		matches, err := c.expandGlob(fa)
 		if err != nil {
-			return fmt.Errorf("Load GetStringSlice: %v", err)
+			return nil, err
 		}
-		for _, c := range ss {
-			if err := k.Load(file.Provider(c), json.Parser()); err != nil {
-				return fmt.Errorf("Load file %s: %v", c, err)
+		for _, m := range matches {
+			files, err := c.expandDir(m)
+			if err != nil {
+				return nil, err
 			}
+			args = append(args, files...)
+		}
+	}
+	return args, nil
+}
+
+// splitFileList splits a list of config files from the environment. If the list
+// contains a comma it is split on commas. Otherwise it is split on colons, as
+// in PATH, except for the colon after a URL scheme. Use commas to name files
+// that are mounted or URLs that include a port.
+func splitFileList(v string) []string {
+	sep := ":"
+	if strings.Contains(v, ",") {
+		sep = ","
+	}
+	var ss []string
+	for _, part := range strings.Split(v, sep) {
+		switch {
+		case part == "":
+		case sep == ":" && strings.HasPrefix(part, "//") && len(ss) > 0:
+			ss[len(ss)-1] += ":" + part
+		default:
+			ss = append(ss, part)
+		}
+	}
+	return ss
+}
+
+// Names of the built in layers, used to report where values came from.
+const (
+	SourceEnv   = "env"
+	SourceFlags = "flags"
+)
+
+// loaded holds the result of merging all configuration layers.
+type loaded struct {
+	k *koanf.Koanf
+	// sources maps each key to the name of the layer that supplied its
+	// value.
+	sources map[string]string
+	// status describes every layer in the order it was loaded.
+	status []SourceStatus
+	// timings, if not nil, receives the time taken by each stage.
+	timings *[]StageTiming
+	// cache, if not nil, holds the parsed contents of config files.
+	cache *parseCache
+	// spellings maps lower cased keys to the ways sources wrote them.
+	spellings map[string][]Spelling
+	// conflicts is what to do when config files conflict, and files holds
+	// the names of the config files loaded so far.
+	conflicts ConflictMode
+	files     map[string]bool
+	// overrides holds, for each config file, the config files whose values
+	// it is meant to override, which are not conflicts.
+	overrides map[string]map[string]bool
+	// flagNames maps keys to the names of the flags set by flag tags.
+	flagNames map[string]string
+	// logf writes messages to the configured Logger.
+	logf func(format string, v ...interface{})
+	// including holds the config files being loaded, to detect include
+	// cycles.
+	including map[string]bool
+	// secrets holds the keys of the fields tagged secret.
+	secrets map[string]bool
+	// origins holds, for every key, the values supplied by each layer in
+	// the order they were loaded.
+	origins map[string][]keyOrigin
+	// rename renames the keys set by WithAliases in the values of a layer.
+	rename func(ly layer, k *koanf.Koanf) (*koanf.Koanf, error)
+	// ctx is the context of the load, which remote sources honor.
+	ctx context.Context
+}
+
+// layer is a single source of configuration merged by Load.
+type layer struct {
+	// name identifies the layer when reporting where values came from.
+	name string
+	// desc describes the layer in errors.
+	desc string
+	// open returns the provider and parser used to read the layer.
+	open func(l *loaded) (koanf.Provider, koanf.Parser, error)
+	// file is set for config files.
+	file bool
+	// mount, if not empty, is the key the layer is loaded under.
+	mount string
+	// optional is set for config files that are skipped if they do not
+	// exist.
+	optional bool
+	// base, if not empty, is the config file the layer overrides, whose
+	// values it may change without a conflict.
+	base string
+	// migrate, if set, migrates the values of the layer to the current
+	// version before they are used.
+	migrate func(k *koanf.Koanf) (*koanf.Koanf, error)
+	// include, if set, loads the files included by the layer, whose values
+	// are k, before the layer is merged.
+	include func(l *loaded, k *koanf.Koanf) error
+	// expand, if set, rewrites the values of the layer after it is read.
+	expand func(k *koanf.Koanf) error
+}
+
+// loadLayer reads ly and merges it into l, recording its status.
+func (l *loaded) loadLayer(ly layer) error {
+	start := time.Now()
+	st := SourceStatus{Name: ly.name, State: SourceFailed}
+	defer func() {
+		st.Duration = time.Since(start)
+		l.status = append(l.status, st)
+	}()
+
+	if err := l.ctx.Err(); err != nil {
+		st.Err = err
+		return err
+	}
+	p, parser, err := ly.open(l)
+	if err != nil {
+		st.Err = err
+		return err
+	}
+	if l.timings != nil {
+		p = timedProvider{p: p, l: l, source: ly.name}
+		if parser != nil {
+			parser = timedParser{p: parser, l: l, source: ly.name}
+		}
+	}
+	k := koanf.New(l.k.Delim())
+	if err := k.Load(p, parser); err != nil {
+		if ly.optional && errors.Is(err, fs.ErrNotExist) {
+			st.State = SourceMissing
+			return nil
+		}
+		st.Err = err
+		return err
+	}
+	if ly.migrate != nil {
+		if k, err = ly.migrate(k); err != nil {
+			st.Err = err
+			return err
+		}
+	}
+	if ly.include != nil {
+		if err := ly.include(l, k); err != nil {
+			st.Err = err
+			return err
+		}
+	}
+	if ly.expand != nil {
+		if err := ly.expand(k); err != nil {
+			st.Err = err
+			return err
 		}
 	}
+	if ly.mount != "" {
+		if k, err = mount(k, ly.mount); err != nil {
+			st.Err = err
+			return err
+		}
+	}
+	if k, err = l.rename(ly, k); err != nil {
+		st.Err = err
+		return err
+	}
+	if ly.base != "" {
+		l.overrideFile(ly.name, ly.base)
+	}
+	if err := l.checkConflicts(ly, k); err != nil {
+		st.Err = err
+		return err
+	}
+	if ly.file {
+		l.files[ly.name] = true
+	}
+	keys := k.Keys()
+	for _, key := range keys {
+		l.sources[key] = ly.name
+		l.recordOrigin(ly.name, key, k.Get(key))
+		// Environment variable names are recorded as they are read.
+		if ly.name != SourceEnv {
+			l.recordSpelling(ly.name, key, key)
+		}
+	}
+	mergeStart := time.Now()
+	if err := l.k.Merge(k); err != nil {
+		st.Err = err
+		return err
+	}
+	l.since(StageMerge, ly.name, mergeStart)
+	st.State, st.Keys = SourceLoaded, len(keys)
+	return nil
+}
 
-	if err := k.Load(env.Provider(c.prefix, c.delimiter, c.updateEnv), nil); err != nil {
-		return fmt.Errorf("Load env: %v", err)
+// layers returns every configuration layer in order of increasing precedence.
+// Defaults are taken from WithDefaults and, unless cfg is nil, the struct tags
+// of cfg. Files, sources, the environment and flags are ordered as set by
+// WithPrecedence, followed by the overrides of WithSetFlag.
+func (c Config) layers(f *pflag.FlagSet, files []fileArg, cfg interface{}) []layer {
+	var layers []layer
+
+	if ly, ok := c.defaultsLayer(cfg); ok {
+		layers = append(layers, ly)
 	}
 
-	if err := k.Load(posflag.Provider(f, ".", k), nil); err != nil {
-		log.Fatalf("Load flags: %v", err)
+	groups := make(map[string][]layer)
+	groups[PrecedenceFiles] = c.rawLayers()
+	for _, fa := range files {
+		groups[PrecedenceFiles] = append(groups[PrecedenceFiles], c.fileLayer(fa))
 	}
 
-	if err := k.Unmarshal(unmarshalEverything, cfg); err != nil {
-		return fmt.Errorf("Load unmarshal: %v", err)
+	for _, s := range c.sources {
+		s := s
+		groups[PrecedenceSources] = append(groups[PrecedenceSources], layer{
+			name: s.Name,
+			desc: "source " + s.Name,
+			open: func(l *loaded) (koanf.Provider, koanf.Parser, error) {
+				return policyProvider{s: s, ctx: l.ctx, policy: c.policy}, s.Parser, nil
+			},
+		})
 	}
 
-	return nil
+	groups[SourceEnv] = []layer{{
+		name: SourceEnv,
+		desc: SourceEnv,
+		open: func(l *loaded) (koanf.Provider, koanf.Parser, error) {
+			p := env.Provider(c.prefix, c.delimiter, func(s string) string {
+				key := c.updateEnv(s)
+				if key != "" {
+					l.recordSpelling(SourceEnv, s, key)
+				}
+				return key
+			})
+			// A cfg that is not a struct is reported by unmarshal.
+			tags, err := c.envTags(cfg)
+			if err != nil {
+				return p, nil, nil
+			}
+			secrets, err := c.secretFileVars(cfg)
+			if err != nil || len(tags)+len(secrets) == 0 {
+				return p, nil, nil
+			}
+			return envTagProvider{env: p, tags: tags, secrets: secrets, delim: c.delimiter, l: l}, nil, nil
+		},
+	}}
+	groups[SourceFlags] = []layer{{
+		name: SourceFlags,
+		desc: SourceFlags,
+		open: func(l *loaded) (koanf.Provider, koanf.Parser, error) {
+			return c.flagProvider(l, f, cfg), nil, nil
+		},
+	}}
+
+	for _, g := range c.precedenceOrder() {
+		layers = append(layers, groups[g]...)
+	}
+
+	if ly, ok := c.setLayer(f); ok {
+		layers = append(layers, ly)
+	}
+
+	layers = append(layers, c.compiledLayers()...)
+
+	if c.metadata {
+		layers = append(layers, layer{
+			name: SourceMetadata,
+			desc: SourceMetadata,
+			open: func(*loaded) (koanf.Provider, koanf.Parser, error) {
+				return metadataProvider{}, nil, nil
+			},
+		})
+	}
+
+	return layers
+}
+
+// newLoaded returns an empty loaded for cfg.
+func (c Config) newLoaded(cfg interface{}) *loaded {
+	return &loaded{
+		k:         koanf.New(c.delimiter),
+		sources:   make(map[string]string),
+		conflicts: c.conflicts,
+		logf:      c.logf,
+		files:     make(map[string]bool),
+		secrets:   c.secretKeys(cfg),
+		rename:    c.renameAliases,
+		ctx:       context.Background(),
+	}
+}
+
+// merge loads and merges every configuration layer, including the defaults of
+// cfg, in order of increasing precedence. If a layer fails, the returned loaded
+// reports the remaining layers as skipped, unless WithAllErrors is used, in
+// which case they are loaded.
+func (c Config) merge(ctx context.Context, f *pflag.FlagSet, cfg interface{}) (*loaded, error) {
+	l := c.newLoaded(cfg)
+	l.ctx = ctx
+	if c.timing {
+		l.timings = new([]StageTiming)
+	}
+	if c.cachePath != "" {
+		l.cache = c.readCache(c.cachePath)
+		defer func() {
+			if err := l.cache.write(c.cachePath); err != nil {
+				c.logf("write cache %s: %v", c.cachePath, err)
+			}
+		}()
+	}
+
+	// Load the config files provided on the commandline.
+	files, err := c.configFiles(f)
+	if err != nil {
+		return l, &FlagError{Err: err}
+	}
+
+	ec := collector{all: c.allErrors}
+	layers := c.layers(f, files, cfg)
+	for i, ly := range layers {
+		err := l.loadLayer(ly)
+		if err == nil {
+			continue
+		}
+		err = layerError(ly, err)
+		if !ec.add(err) {
+			for _, skipped := range layers[i+1:] {
+				l.status = append(l.status, SourceStatus{Name: skipped.name, State: SourceSkipped})
+			}
+			return l, err
+		}
+	}
+
+	if c.requireAnySource && !c.anySource(l, f) {
+		ec.add(fmt.Errorf("Load: %w", NoSourceError))
+	}
+
+	return l, ec.err()
+}
+
+// load merges every configuration layer and unmarshals the result into cfg.
+// The returned loaded is not nil even if an error is returned.
+func (c Config) load(ctx context.Context, f *pflag.FlagSet, cfg interface{}) (*loaded, error) {
+	l, err := c.loadLayers(ctx, f, cfg)
+	return l, c.translate(err)
+}
+
+// loadLayers implements load, returning untranslated errors.
+func (c Config) loadLayers(ctx context.Context, f *pflag.FlagSet, cfg interface{}) (*loaded, error) {
+	ec := collector{all: c.allErrors}
+	l, err := c.merge(ctx, f, cfg)
+	if !ec.add(err) {
+		return l, err
+	}
+	return l, c.finish(l, f, cfg, &ec)
+}
+
+// finish resolves the values merged into l, unmarshals them into cfg and
+// checks the result, recording errors in ec.
+func (c Config) finish(l *loaded, f *pflag.FlagSet, cfg interface{}, ec *collector) error {
+	const unmarshalEverything = ""
+
+	if !ec.add(c.decryptValues(l.k)) {
+		return ec.err()
+	}
+	if c.references && !ec.add(resolveReferences(l.k)) {
+		return ec.err()
+	}
+
+	if !ec.add(c.checkUnknown(l, f, cfg)) {
+		return ec.err()
+	}
+
+	start := time.Now()
+	uc := koanf.UnmarshalConf{DecoderConfig: core.DecoderConfig(cfg)}
+	if err := l.k.UnmarshalWithConf(unmarshalEverything, cfg, uc); err != nil {
+		if !c.allErrors {
+			return c.unmarshalError(l, cfg, err)
+		}
+		for _, err := range c.unmarshalErrors(l, cfg, err) {
+			ec.add(err)
+		}
+	}
+	l.since(StageUnmarshal, "", start)
+
+	start = time.Now()
+	for _, check := range []func() error{
+		func() error { return c.checkRequired(l, f, cfg) },
+		func() error { return c.checkOneOf(cfg) },
+		func() error { return c.validateTags(cfg) },
+		func() error { return c.validate(reflect.ValueOf(cfg), nil) },
+	} {
+		if !ec.add(check()) {
+			return ec.err()
+		}
+	}
+	l.since(StageValidate, "", start)
+
+	return ec.err()
+}
+
+// Load loads values into cfg from environment variables, flags and config
+// files. Config files may be local paths, http or https URLs, or object storage
+// URLs such as s3://bucket/key, and are parsed based on their extension. Once
+// loaded, structs implementing Validator are validated. Load fails with
+// MissingRequiredError if no source supplied a field tagged as required, as in
+// `koanf:"port,required"`. Fields may have a default value, as in
+// `default:"8080"`, which has lower precedence than every other source, and be
+// limited to a set of values, as in `oneof:"debug,info"`, failing with
+// NotOneOfError.
+func (c Config) Load(f *pflag.FlagSet, cfg interface{}) error {
+	return c.LoadContext(context.Background(), f, cfg)
+}
+
+// LoadContext is like Load, but stops with the error of ctx once it is done.
+// Config files fetched over HTTP or from an ObjectStore and sources that are
+// ContextProviders are read with ctx, so that they honor its cancellation and
+// deadline.
+func (c Config) LoadContext(ctx context.Context, f *pflag.FlagSet, cfg interface{}) error {
+	if _, err := c.load(ctx, f, cfg); err != nil {
+		return err
+	}
+	return c.printConfigIfRequested(f, cfg)
+}
+
+// Load returns a new T loaded as Config.Load loads cfg, so that callers cannot
+// pass a nil or non-pointer configuration:
+//
+//	cfg, err := goconfig.Load[Config](c, f)
+//
+// T is a struct or a pointer to a struct, which is allocated. The zero T is
+// returned if loading fails.
+func Load[T any](c Config, f *pflag.FlagSet) (T, error) {
+	cfg, target := newTarget[T]()
+	if err := c.Load(f, target); err != nil {
+		var zero T
+		return zero, err
+	}
+	return *cfg, nil
+}
+
+// LoadWithResultAs is like Load, but also returns the Result, as
+// Config.LoadWithResult does. The Result is returned even if loading fails.
+func LoadWithResultAs[T any](c Config, f *pflag.FlagSet) (T, *Result, error) {
+	cfg, target := newTarget[T]()
+	r, err := c.LoadWithResult(f, target)
+	if err != nil {
+		var zero T
+		return zero, r, err
+	}
+	return *cfg, r, nil
+}
+
+// newTarget returns a new T and the pointer to load it through, which is the
+// value of the T if T is a pointer.
+func newTarget[T any]() (*T, interface{}) {
+	cfg := new(T)
+	if t := reflect.TypeOf(*cfg); t != nil && t.Kind() == reflect.Pointer {
+		*cfg = reflect.New(t.Elem()).Interface().(T)
+		return cfg, *cfg
+	}
+	return cfg, cfg
+}
+
+// MustLoad is like Load, but calls the handler set by WithMustLoadHandler if
+// loading fails, for main functions and examples:
+//
+//	cfg := goconfig.MustLoad[Config](c, f)
+//
+// MustLoad panics with the error if there is no handler or the handler
+// returns.
+func MustLoad[T any](c Config, f *pflag.FlagSet) T {
+	cfg, err := Load[T](c, f)
+	if err != nil {
+		if c.mustLoadHandler != nil {
+			c.mustLoadHandler(err)
+		}
+		panic(err)
+	}
+	return cfg
 }
@@ -0,0 +1,102 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package goconfig2 is version 2 of the goconfig API. It uses the same loader
+// as version 1, with an API that is easier to extend without breaking callers:
+//
+//   - New takes only options. The environment prefix and key delimiter are set
+//     with WithEnvPrefix and WithDelimiter, and default to none and ".".
+//   - Load returns a Result describing the load in addition to the error.
+//
+// Option is the version 1 type, so every version 1 option, such as
+// goconfig.WithStrict, can be passed to New, but only WithEnvPrefix and
+// WithDelimiter are declared in this package. Programs can move to version 2
+// one call at a time: NewV1 and LoadV1 have the version 1 signatures and are
+// deprecated.
+//
+// The package is not named v2 so that its import path does not claim the
+// module path of a future major version.
+package goconfig2
+
+import (
+	v1 "github.com/bretmckee/goconfig"
+	"github.com/spf13/pflag"
+)
+
+// DefaultDelimiter is the delimiter of nested keys unless WithDelimiter is
+// used.
+const DefaultDelimiter = "."
+
+type (
+	// Config holds the data necessary to process configuration data.
+	Config = v1.Config
+	// Option configures a Config.
+	Option = v1.Option
+	// Result describes a completed Load.
+	Result = v1.Result
+)
+
+var (
+	// WithEnvPrefix sets the prefix of environment variables.
+	WithEnvPrefix = v1.WithEnvPrefix
+	// WithDelimiter sets the delimiter of nested keys.
+	WithDelimiter = v1.WithDelimiter
+)
+
+// New returns a Config with no environment prefix, DefaultDelimiter and opts
+// applied.
+func New(opts ...Option) (Config, error) {
+	return v1.New("", DefaultDelimiter, opts...)
+}
+
+// Load loads values into cfg from config files, sources, environment variables
+// and flags, as described in version 1. The Result is returned even if Load
+// fails, so that it can be logged.
+func Load(c Config, f *pflag.FlagSet, cfg interface{}) (*Result, error) {
+	return c.LoadWithResult(f, cfg)
+}
+
+// NewV1 has the signature of version 1 New.
+//
+// Deprecated: Use New with WithEnvPrefix and WithDelimiter.
+func NewV1(envPrefix, flagDelimiter string, opts ...Option) (Config, error) {
+	return v1.New(envPrefix, flagDelimiter, opts...)
+}
+
+// LoadV1 has the signature of version 1 Config.Load.
+//
+// Deprecated: Use Load, which also returns a Result.
+func LoadV1(c Config, f *pflag.FlagSet, cfg interface{}) error {
+	_, err := Load(c, f, cfg)
+	return err
+}
+
+// LoadAs returns a new T loaded as Load loads cfg, so that callers cannot pass
+// a nil or non-pointer configuration:
+//
+//	cfg, r, err := goconfig2.LoadAs[Config](c, f)
+//
+// T is a struct or a pointer to a struct, which is allocated. The zero T is
+// returned if loading fails, with the Result.
+func LoadAs[T any](c Config, f *pflag.FlagSet) (T, *Result, error) {
+	return v1.LoadWithResultAs[T](c, f)
+}
@@ -0,0 +1,90 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig2
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+type testConfig struct {
+	Nested struct {
+		Val int `koanf:"val"`
+	} `koanf:"nested"`
+}
+
+func TestNewAndLoad(t *testing.T) {
+	t.Setenv("APP_NESTED_VAL", "7")
+
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	if err := f.Parse(nil); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	c, err := New(WithEnvPrefix("APP_"))
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	var cfg testConfig
+	r, err := Load(c, f, &cfg)
+	if err != nil {
+		t.Fatalf("Load err: got=%v want=nil", err)
+	}
+	if got, want := cfg.Nested.Val, 7; got != want {
+		t.Errorf("Val: got=%d want=%d", got, want)
+	}
+	if len(r.SourceStatus()) == 0 {
+		t.Errorf("SourceStatus: got none want some")
+	}
+}
+
+func TestNewBadDelimiter(t *testing.T) {
+	if _, err := New(WithDelimiter("::")); err == nil {
+		t.Errorf("New err: got=nil want error")
+	}
+}
+
+func TestLoadAs(t *testing.T) {
+	t.Setenv("APP_NESTED_VAL", "7")
+
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	if err := f.Parse(nil); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	c, err := New(WithEnvPrefix("APP_"))
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	cfg, r, err := LoadAs[*testConfig](c, f)
+	if err != nil {
+		t.Fatalf("LoadAs err: got=%v want=nil", err)
+	}
+	if got, want := cfg.Nested.Val, 7; got != want {
+		t.Errorf("Val: got=%d want=%d", got, want)
+	}
+	if len(r.SourceStatus()) == 0 {
+		t.Errorf("SourceStatus: got none want some")
+	}
+}
@@ -23,12 +23,17 @@
 package goconfig
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/spf13/pflag"
@@ -56,7 +61,10 @@ const (
 	testValue1         = 101
 	testValue2         = 102
 	testValue3         = 103
-	testGoodJSONConfig = "good.json" // Sets value=101 val=102
+	testGoodJSONConfig = "good.json"   // Sets value=101 val=102
+	testGoodYAMLConfig = "good.yaml"   // Sets value=101 val=102
+	testGoodHCLConfig  = "good.tfvars" // Sets value=101 val=102
+	testGoodEnvConfig  = "good.env"    // Sets value=101 val=102
 )
 
 type nameValue struct {
@@ -401,6 +409,36 @@ func TestLoadViaConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "yaml values overwrite defaults",
+			file: testFileName(testGoodYAMLConfig),
+			want: testConfig{
+				Value1: testValue1,
+				Nested: testConfig1{
+					NestedVal: testValue2,
+				},
+			},
+		},
+		{
+			name: "tfvars values overwrite defaults",
+			file: testFileName(testGoodHCLConfig),
+			want: testConfig{
+				Value1: testValue1,
+				Nested: testConfig1{
+					NestedVal: testValue2,
+				},
+			},
+		},
+		{
+			name: "env file values overwrite defaults",
+			file: testFileName(testGoodEnvConfig),
+			want: testConfig{
+				Value1: testValue1,
+				Nested: testConfig1{
+					NestedVal: testValue2,
+				},
+			},
+		},
 		{
 			name:        "bad values",
 			file:        testFileName("bad.json"),
@@ -546,3 +584,277 @@ func TestFlagIsAfterFile(t *testing.T) {
 		t.Errorf("Value: got=%d want=%d", got, want)
 	}
 }
+
+func TestConfigFilesFlagTypes(t *testing.T) {
+	const withComma = "dir,with,commas/config.yaml"
+	cases := []struct {
+		name   string
+		define func(f *pflag.FlagSet)
+		want   []fileArg
+	}{
+		{
+			name:   "file list",
+			define: AddConfigFlag,
+			want:   []fileArg{{name: withComma}, {name: "db.yaml", mount: "db"}},
+		},
+		{
+			name: "string array",
+			define: func(f *pflag.FlagSet) {
+				f.StringArray(FileArgName, nil, testNoHelpMessage)
+			},
+			want: []fileArg{{name: withComma}, {name: "db.yaml", mount: "db"}},
+		},
+		{
+			name: "string slice",
+			define: func(f *pflag.FlagSet) {
+				f.StringSlice(FileArgName, nil, testNoHelpMessage)
+			},
+			want: []fileArg{{name: "dir"}, {name: "with"}, {name: "commas/config.yaml"}, {name: "db.yaml", mount: "db"}},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			tc.define(f)
+			args := []string{
+				fmt.Sprintf("--%s=%s", FileArgName, withComma),
+				fmt.Sprintf("--%s=db.yaml:db", FileArgName),
+			}
+			if err := f.Parse(args); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+			got, err := Config{}.configFiles(f)
+			if err != nil {
+				t.Fatalf("configFiles err: got=%v want=nil", err)
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(fileArg{})); diff != "" {
+				t.Errorf("configFiles mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSplitFileList(t *testing.T) {
+	cases := []struct {
+		v    string
+		want []string
+	}{
+		{"a.yaml", []string{"a.yaml"}},
+		{"a.yaml:b.yaml", []string{"a.yaml", "b.yaml"}},
+		{"a.yaml,b.yaml:db", []string{"a.yaml", "b.yaml:db"}},
+		{"a.yaml:https://example.com/b.yaml", []string{"a.yaml", "https://example.com/b.yaml"}},
+		{"a.yaml::b.yaml:", []string{"a.yaml", "b.yaml"}},
+	}
+	for _, tc := range cases {
+		if diff := cmp.Diff(tc.want, splitFileList(tc.v)); diff != "" {
+			t.Errorf("splitFileList(%q) mismatch (-want +got):\n%s", tc.v, diff)
+		}
+	}
+}
+
+func TestConfigFilesFromEnv(t *testing.T) {
+	t.Setenv(strings.ToUpper(testPrefix)+"CONFIG", "env1.yaml:env2.yaml")
+
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	AddConfigFlag(f)
+	if err := f.Parse([]string{fmt.Sprintf("--%s=flag.yaml", FileArgName)}); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	got, err := c.configFiles(f)
+	if err != nil {
+		t.Fatalf("configFiles err: got=%v want=nil", err)
+	}
+	want := []fileArg{{name: "env1.yaml"}, {name: "env2.yaml"}, {name: "flag.yaml"}}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(fileArg{})); diff != "" {
+		t.Errorf("configFiles mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLoadGeneric(t *testing.T) {
+	t.Setenv(testPrefix+"VALUE1", "7")
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	if err := f.Parse(nil); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	got, err := Load[testConfig](c, f)
+	if err != nil {
+		t.Fatalf("Load[testConfig] err: got=%v want=nil", err)
+	}
+	if got.Value1 != 7 {
+		t.Errorf("Load[testConfig] Value1: got=%d want=%d", got.Value1, 7)
+	}
+
+	gotPtr, err := Load[*testConfig](c, f)
+	if err != nil {
+		t.Fatalf("Load[*testConfig] err: got=%v want=nil", err)
+	}
+	if gotPtr == nil || gotPtr.Value1 != 7 {
+		t.Errorf("Load[*testConfig]: got=%+v want Value1=%d", gotPtr, 7)
+	}
+
+	t.Setenv(testPrefix+"VALUE1", "x")
+	got, err = Load[testConfig](c, f)
+	if err == nil {
+		t.Errorf("Load[testConfig] err: got=nil want=error")
+	}
+	if diff := cmp.Diff(testConfig{}, got); diff != "" {
+		t.Errorf("Load[testConfig] on error mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := Load[int](c, f); err == nil {
+		t.Errorf("Load[int] err: got=nil want=error")
+	}
+}
+
+func TestLoadWithResultAs(t *testing.T) {
+	t.Setenv(testPrefix+"VALUE1", "7")
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	if err := f.Parse(nil); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	got, r, err := LoadWithResultAs[*testConfig](c, f)
+	if err != nil {
+		t.Fatalf("LoadWithResultAs err: got=%v want=nil", err)
+	}
+	if got == nil || got.Value1 != 7 {
+		t.Errorf("LoadWithResultAs: got=%+v want Value1=%d", got, 7)
+	}
+	if r == nil {
+		t.Errorf("LoadWithResultAs Result: got=nil want non-nil")
+	}
+}
+
+func TestMustLoad(t *testing.T) {
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	if err := f.Parse(nil); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	t.Setenv(testPrefix+"VALUE1", "7")
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	if got := MustLoad[testConfig](c, f); got.Value1 != 7 {
+		t.Errorf("MustLoad Value1: got=%d want=%d", got.Value1, 7)
+	}
+
+	t.Setenv(testPrefix+"VALUE1", "x")
+	var handled error
+	c, err = New(testPrefix, testDelimiter, WithMustLoadHandler(func(err error) { handled = err }))
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("MustLoad panic: got=nil want=error")
+			}
+		}()
+		MustLoad[testConfig](c, f)
+	}()
+	if handled == nil {
+		t.Errorf("MustLoad handler err: got=nil want=error")
+	}
+}
+
+// testContextProvider is a ContextProvider that records the context it is read
+// with.
+type testContextProvider struct {
+	ctx context.Context
+}
+
+func (p *testContextProvider) ReadBytes() ([]byte, error) {
+	return nil, errors.New("testContextProvider does not support ReadBytes")
+}
+
+func (p *testContextProvider) Read() (map[string]interface{}, error) {
+	return p.ReadContext(context.Background())
+}
+
+func (p *testContextProvider) ReadBytesContext(ctx context.Context) ([]byte, error) {
+	return p.ReadBytes()
+}
+
+func (p *testContextProvider) ReadContext(ctx context.Context) (map[string]interface{}, error) {
+	p.ctx = ctx
+	return map[string]interface{}{"value1": 7}, nil
+}
+
+type testContextKey struct{}
+
+func TestLoadContext(t *testing.T) {
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	if err := f.Parse(nil); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+
+	p := &testContextProvider{}
+	c, err := New(testPrefix, testDelimiter, WithSource(Source{Name: "test", Provider: p}))
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	ctx := context.WithValue(context.Background(), testContextKey{}, "v")
+	var cfg testConfig
+	if err := c.LoadContext(ctx, f, &cfg); err != nil {
+		t.Fatalf("LoadContext err: got=%v want=nil", err)
+	}
+	if cfg.Value1 != 7 {
+		t.Errorf("LoadContext Value1: got=%d want=%d", cfg.Value1, 7)
+	}
+	if p.ctx == nil || p.ctx.Value(testContextKey{}) != "v" {
+		t.Errorf("ReadContext ctx: got=%v want the LoadContext context", p.ctx)
+	}
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := c.LoadContext(canceled, f, &cfg); !errors.Is(err, context.Canceled) {
+		t.Errorf("LoadContext canceled err: got=%v want=%v", err, context.Canceled)
+	}
+}
+
+func TestLoadContextHTTPDeadline(t *testing.T) {
+	done := make(chan struct{})
+	defer close(done)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	defer srv.Close()
+
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	AddConfigFlag(f)
+	if err := f.Parse([]string{"--" + FileArgName + "=" + srv.URL + "/config.yaml"}); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	var cfg testConfig
+	if err := c.LoadContext(ctx, f, &cfg); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("LoadContext err: got=%v want=%v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > DefaultHTTPTimeout/2 {
+		t.Errorf("LoadContext took %v, want it to stop at the deadline", elapsed)
+	}
+}
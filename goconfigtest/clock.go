@@ -0,0 +1,137 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfigtest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bretmckee/goconfig"
+)
+
+// Clock is a goconfig.Clock whose time only moves when Advance is called, for
+// use with goconfig.WithClock. It is safe for concurrent use.
+type Clock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []*waiter
+}
+
+// waiter is a pending After channel or a running ticker.
+type waiter struct {
+	at     time.Time
+	period time.Duration // zero for After
+	ch     chan time.Time
+}
+
+// NewClock returns a Clock set to now.
+func NewClock(now time.Time) *Clock {
+	c := &Clock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now returns the current time of c.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After returns a channel that receives the time once c has been advanced by
+// d.
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+	return c.add(&waiter{at: c.Now().Add(d), ch: make(chan time.Time, 1)}).ch
+}
+
+// NewTicker returns a ticker that ticks every time c has been advanced by d.
+// Like time.Ticker, ticks are dropped if the previous one was not received.
+func (c *Clock) NewTicker(d time.Duration) goconfig.Ticker {
+	return ticker{c: c, w: c.add(&waiter{at: c.Now().Add(d), period: d, ch: make(chan time.Time, 1)})}
+}
+
+// Advance moves c forward by d, firing every After channel and ticker that
+// became due.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if !w.at.After(c.now) {
+			select {
+			case w.ch <- c.now:
+			default:
+			}
+			if w.period == 0 {
+				continue
+			}
+			for !w.at.After(c.now) {
+				w.at = w.at.Add(w.period)
+			}
+		}
+		pending = append(pending, w)
+	}
+	c.waiters = pending
+	c.cond.Broadcast()
+}
+
+// BlockUntil blocks until n After channels and tickers are pending, so that a
+// test can be sure the code under test is waiting before calling Advance.
+func (c *Clock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.waiters) != n {
+		c.cond.Wait()
+	}
+}
+
+func (c *Clock) add(w *waiter) *waiter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.waiters = append(c.waiters, w)
+	c.cond.Broadcast()
+	return w
+}
+
+func (c *Clock) remove(w *waiter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, x := range c.waiters {
+		if x == w {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			break
+		}
+	}
+	c.cond.Broadcast()
+}
+
+// ticker is a goconfig.Ticker driven by a Clock.
+type ticker struct {
+	c *Clock
+	w *waiter
+}
+
+func (t ticker) C() <-chan time.Time { return t.w.ch }
+func (t ticker) Stop()               { t.c.remove(t.w) }
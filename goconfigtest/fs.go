@@ -0,0 +1,72 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfigtest
+
+import (
+	"io/fs"
+	"sync"
+	"testing/fstest"
+	"time"
+)
+
+// FS is an in-memory file system for use with goconfig.WithFS whose files can
+// be changed while a watch is running. It is safe for concurrent use.
+type FS struct {
+	mu    sync.Mutex
+	files fstest.MapFS
+}
+
+// NewFS returns an FS holding files.
+func NewFS(files ...File) *FS {
+	fsys := &FS{files: make(fstest.MapFS)}
+	for _, f := range files {
+		fsys.WriteFile(f.Name, f.Contents)
+	}
+	return fsys
+}
+
+// WriteFile creates or replaces the file name.
+func (fsys *FS) WriteFile(name, contents string) {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+	fsys.files[name] = &fstest.MapFile{Data: []byte(contents), Mode: 0o644, ModTime: time.Now()}
+}
+
+// Remove removes the file name.
+func (fsys *FS) Remove(name string) {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+	delete(fsys.files, name)
+}
+
+// Open opens a snapshot of the file name.
+func (fsys *FS) Open(name string) (fs.File, error) {
+	fsys.mu.Lock()
+	snapshot := make(fstest.MapFS, len(fsys.files))
+	for k, v := range fsys.files {
+		f := *v
+		snapshot[k] = &f
+	}
+	fsys.mu.Unlock()
+	return snapshot.Open(name)
+}
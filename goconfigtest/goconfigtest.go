@@ -0,0 +1,115 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package goconfigtest runs tables of configuration scenarios through the full
+// goconfig pipeline, so that applications can lock in how their files,
+// environment and flags combine and notice if an upgrade changes it. It also
+// provides a Clock and an FS for testing Watch without sleeping or writing
+// files.
+package goconfigtest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bretmckee/goconfig"
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/pflag"
+)
+
+// File is a config file written for a Case.
+type File struct {
+	// Name is the file name. Its extension selects the format.
+	Name     string
+	Contents string
+}
+
+// Case is a single scenario.
+type Case[T any] struct {
+	Name string
+	// Files are written to a temporary directory and passed, in order,
+	// with the config file flag.
+	Files []File
+	// Env is set for the duration of the case.
+	Env map[string]string
+	// Args are the command line arguments, parsed after the files.
+	Args []string
+	// Want is the expected configuration, unless WantErr is set.
+	Want    T
+	WantErr bool
+}
+
+// Run runs each case as a subtest of t. For every case it defines the flags of
+// a new flag set using flags, adds the config file flag if flags did not,
+// parses the arguments and loads a T using c, then compares the result to the
+// case using opts.
+//
+// Run uses t.Setenv, so it cannot be used in parallel tests.
+func Run[T any](t *testing.T, c goconfig.Config, flags func(f *pflag.FlagSet), cases []Case[T], opts ...cmp.Option) {
+	t.Helper()
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			for k, v := range tc.Env {
+				t.Setenv(k, v)
+			}
+
+			f := pflag.NewFlagSet(tc.Name, pflag.ContinueOnError)
+			if flags != nil {
+				flags(f)
+			}
+			if f.Lookup(c.FileFlag()) == nil {
+				c.AddFileFlag(f, "")
+			}
+
+			dir := t.TempDir()
+			var args []string
+			for _, file := range tc.Files {
+				name := filepath.Join(dir, file.Name)
+				if err := os.WriteFile(name, []byte(file.Contents), 0o600); err != nil {
+					t.Fatalf("write %s: %v", file.Name, err)
+				}
+				args = append(args, fmt.Sprintf("--%s=%s", c.FileFlag(), name))
+			}
+			if err := f.Parse(append(args, tc.Args...)); err != nil {
+				t.Fatalf("parse flags: %v", err)
+			}
+
+			var got T
+			err := c.Load(f, &got)
+			if tc.WantErr {
+				if err == nil {
+					t.Errorf("Load err: got=nil want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load err: got=%v want=nil", err)
+			}
+			if diff := cmp.Diff(tc.Want, got, opts...); diff != "" {
+				t.Errorf("Load mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfigtest
+
+import (
+	"testing"
+
+	"github.com/bretmckee/goconfig"
+	"github.com/spf13/pflag"
+)
+
+type testConfig struct {
+	Name string `koanf:"name"`
+	Port int    `koanf:"port"`
+}
+
+func TestRun(t *testing.T) {
+	c, err := goconfig.New("TEST_", ".")
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	flags := func(f *pflag.FlagSet) {
+		f.String("name", "default", "")
+		f.Int("port", 0, "")
+	}
+
+	Run(t, c, flags, []Case[testConfig]{
+		{
+			Name: "flag defaults",
+			Want: testConfig{Name: "default"},
+		},
+		{
+			Name:  "later files win",
+			Files: []File{{Name: "a.yaml", Contents: "port: 1\n"}, {Name: "b.json", Contents: `{"port": 2}`}},
+			Want:  testConfig{Name: "default", Port: 2},
+		},
+		{
+			Name:  "env over files",
+			Files: []File{{Name: "a.yaml", Contents: "port: 1\n"}},
+			Env:   map[string]string{"TEST_PORT": "3"},
+			Want:  testConfig{Name: "default", Port: 3},
+		},
+		{
+			Name: "flags over env",
+			Env:  map[string]string{"TEST_PORT": "3"},
+			Args: []string{"--port=4"},
+			Want: testConfig{Name: "default", Port: 4},
+		},
+		{
+			Name:    "bad file",
+			Files:   []File{{Name: "a.json", Contents: "{"}},
+			WantErr: true,
+		},
+	})
+}
@@ -0,0 +1,103 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfigtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bretmckee/goconfig"
+	"github.com/spf13/pflag"
+)
+
+func TestClockAfter(t *testing.T) {
+	clk := NewClock(time.Unix(0, 0))
+	ch := clk.After(time.Second)
+
+	clk.Advance(time.Second - 1)
+	select {
+	case <-ch:
+		t.Fatalf("After fired early")
+	default:
+	}
+
+	clk.Advance(1)
+	select {
+	case got := <-ch:
+		if want := time.Unix(1, 0); !got.Equal(want) {
+			t.Errorf("After: got=%v want=%v", got, want)
+		}
+	default:
+		t.Fatalf("After did not fire")
+	}
+}
+
+func TestWatchFS(t *testing.T) {
+	const interval = time.Minute
+
+	fsys := NewFS(File{Name: "app.json", Contents: `{"port": 1}`})
+	clk := NewClock(time.Unix(0, 0))
+	c, err := goconfig.New("TEST_", ".", goconfig.WithFS(fsys), goconfig.WithClock(clk), goconfig.WithPollInterval(interval))
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	goconfig.AddConfigFlag(f)
+	if err := f.Parse([]string{"--config=app.json"}); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := make(chan testConfig, 1)
+	var cfg testConfig
+	err = goconfig.Watch(ctx, c, f, &cfg, func(_, new testConfig) error {
+		changes <- new
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Watch err: got=%v want=nil", err)
+	}
+	if got, want := cfg.Port, 1; got != want {
+		t.Errorf("initial Port: got=%d want=%d", got, want)
+	}
+
+	// Wait for the poll ticker, then for the debounce timer started by the
+	// change it detects.
+	clk.BlockUntil(1)
+	fsys.WriteFile("app.json", `{"port": 2}`)
+	clk.Advance(interval)
+	clk.BlockUntil(2)
+	clk.Advance(interval)
+
+	select {
+	case got := <-changes:
+		if got, want := got.Port, 2; got != want {
+			t.Errorf("reloaded Port: got=%d want=%d", got, want)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("timed out waiting for reload")
+	}
+}
@@ -0,0 +1,66 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+
+	"github.com/hashicorp/hcl"
+)
+
+// hclParser is a koanf.Parser for HCL, including Terraform variable files.
+type hclParser struct{}
+
+// Unmarshal parses the HCL in b. HCL decodes blocks and object values as
+// lists of maps, so single element lists of maps are replaced by the map
+// itself.
+func (hclParser) Unmarshal(b []byte) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if err := hcl.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+	flattenHCL(out)
+	return out, nil
+}
+
+// Marshal is not supported for HCL.
+func (hclParser) Marshal(map[string]interface{}) ([]byte, error) {
+	return nil, errors.New("marshaling HCL is not supported")
+}
+
+// flattenHCL replaces single element lists of maps in m with the map.
+func flattenHCL(m map[string]interface{}) {
+	for k, v := range m {
+		switch v := v.(type) {
+		case []map[string]interface{}:
+			for _, mm := range v {
+				flattenHCL(mm)
+			}
+			if len(v) == 1 {
+				m[k] = v[0]
+			}
+		case map[string]interface{}:
+			flattenHCL(v)
+		}
+	}
+}
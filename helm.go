@@ -0,0 +1,80 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// HelmSchema returns a values.schema.json document describing cfg, so that
+// Helm chart values are validated against the same structure Load accepts.
+func (c Config) HelmSchema(cfg interface{}) ([]byte, error) {
+	s, err := rootSchema(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("HelmSchema: %w", err)
+	}
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("HelmSchema marshal: %w", err)
+	}
+	return append(b, '\n'), nil
+}
+
+// HelmMapping returns a markdown table listing, for every value in cfg, the
+// environment variable and flag that set the same configuration key.
+func (c Config) HelmMapping(cfg interface{}) ([]byte, error) {
+	v, err := structValue(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("HelmMapping: %w", err)
+	}
+
+	types := make(map[string]string)
+	envNames := make(map[string]string)
+	flagNames := make(map[string]string)
+	err = walkFields(v, "", c.delimiter, func(key string, sf reflect.StructField, _ reflect.Value) error {
+		types[key] = typeSchema(sf.Type).Type
+		envNames[key] = c.fieldEnvName(key, sf)
+		flagNames[key] = fieldFlagName(key, sf)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("HelmMapping: %w", err)
+	}
+	keys := make([]string, 0, len(types))
+	for k := range types {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "| Value | Type | Environment variable | Flag |")
+	fmt.Fprintln(&buf, "|-------|------|----------------------|------|")
+	for _, k := range keys {
+		fmt.Fprintf(&buf, "| `%s` | %s | `%s` | `--%s` |\n", k, types[k], envNames[k], flagNames[k])
+	}
+	return buf.Bytes(), nil
+}
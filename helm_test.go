@@ -0,0 +1,139 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type testHelmConfig struct {
+	Name   string            `koanf:"name"`
+	Debug  bool              `koanf:"debug"`
+	Ratio  float64           `koanf:"ratio"`
+	Hosts  []string          `koanf:"hosts"`
+	Labels map[string]string `koanf:"labels"`
+	Nested testConfig1       `koanf:"nested"`
+}
+
+func TestHelmSchema(t *testing.T) {
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	got, err := c.HelmSchema(testHelmConfig{})
+	if err != nil {
+		t.Fatalf("HelmSchema err: got=%v want=nil", err)
+	}
+	want := `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "properties": {
+    "debug": {
+      "type": "boolean"
+    },
+    "hosts": {
+      "type": "array",
+      "items": {
+        "type": "string"
+      }
+    },
+    "labels": {
+      "type": "object",
+      "additionalProperties": {
+        "type": "string"
+      }
+    },
+    "name": {
+      "type": "string"
+    },
+    "nested": {
+      "type": "object",
+      "properties": {
+        "nestedvalue": {
+          "type": "integer"
+        }
+      }
+    },
+    "ratio": {
+      "type": "number"
+    }
+  }
+}
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("HelmSchema mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestHelmMapping(t *testing.T) {
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	got, err := c.HelmMapping(&testConfig{})
+	if err != nil {
+		t.Fatalf("HelmMapping err: got=%v want=nil", err)
+	}
+	want := "| Value | Type | Environment variable | Flag |\n" +
+		"|-------|------|----------------------|------|\n" +
+		"| `nested.nestedvalue` | integer | `TEST_NESTED_NESTEDVALUE` | `--nested.nestedvalue` |\n" +
+		"| `value1` | integer | `TEST_VALUE1` | `--value1` |\n" +
+		"| `value2` | integer | `TEST_VALUE2` | `--value2` |\n" +
+		"| `value3` | integer | `TEST_VALUE3` | `--value3` |\n"
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("HelmMapping mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestHelmSchemaRecursive(t *testing.T) {
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	got, err := c.HelmSchema(testRecursiveConfig{})
+	if err != nil {
+		t.Fatalf("HelmSchema err: got=%v want=nil", err)
+	}
+	want := `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "properties": {
+    "name": {
+      "type": "string"
+    },
+    "next": {}
+  },
+  "required": [
+    "name"
+  ]
+}
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("HelmSchema mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,78 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"sync/atomic"
+)
+
+// Holder stores the current configuration so that it can be read from many
+// goroutines while reloads replace it. The zero value holds the zero value of
+// T. A Holder must not be copied after first use.
+type Holder[T any] struct {
+	p atomic.Pointer[T]
+}
+
+// NewHolder returns a Holder containing v.
+func NewHolder[T any](v T) *Holder[T] {
+	h := &Holder[T]{}
+	h.Store(v)
+	return h
+}
+
+// Load returns the current configuration. Reference types such as maps and
+// slices inside it are shared with other readers and must not be modified.
+func (h *Holder[T]) Load() T {
+	if p := h.p.Load(); p != nil {
+		return *p
+	}
+	var zero T
+	return zero
+}
+
+// Store atomically replaces the current configuration with v.
+func (h *Holder[T]) Store(v T) {
+	h.p.Store(&v)
+}
+
+// Swap atomically replaces the current configuration with v and returns the
+// previous one.
+func (h *Holder[T]) Swap(v T) T {
+	if p := h.p.Swap(&v); p != nil {
+		return *p
+	}
+	var zero T
+	return zero
+}
+
+// OnChange stores new. It can be passed as the onChange function of Watch or
+// ReloadOnSignal to keep the Holder up to date:
+//
+//	var cfg Config
+//	h := goconfig.NewHolder(cfg)
+//	err := goconfig.Watch(ctx, c, f, &cfg, h.OnChange)
+//	h.Store(cfg)
+func (h *Holder[T]) OnChange(_, new T) error {
+	h.Store(new)
+	return nil
+}
@@ -0,0 +1,69 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestHolder(t *testing.T) {
+	var zero Holder[testConfig]
+	if got, want := zero.Load(), (testConfig{}); got != want {
+		t.Errorf("zero Load: got=%+v want=%+v", got, want)
+	}
+
+	h := NewHolder(testConfig{Value1: testValue1})
+	if got, want := h.Load().Value1, testValue1; got != want {
+		t.Errorf("Load: got=%d want=%d", got, want)
+	}
+
+	if got, want := h.Swap(testConfig{Value1: testValue2}).Value1, testValue1; got != want {
+		t.Errorf("Swap: got=%d want=%d", got, want)
+	}
+
+	if err := h.OnChange(testConfig{}, testConfig{Value1: testValue3}); err != nil {
+		t.Fatalf("OnChange err: got=%v want=nil", err)
+	}
+	if got, want := h.Load().Value1, testValue3; got != want {
+		t.Errorf("Load after OnChange: got=%d want=%d", got, want)
+	}
+}
+
+func TestHolderConcurrent(t *testing.T) {
+	h := NewHolder(testConfig{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(v int) {
+			defer wg.Done()
+			h.Store(testConfig{Value1: v})
+		}(i)
+		go func() {
+			defer wg.Done()
+			_ = h.Load()
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,147 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+var (
+	BadStatusError = errors.New("unexpected HTTP status")
+)
+
+// DefaultHTTPTimeout is the per attempt timeout used when HTTPOptions.Timeout
+// is not set.
+const DefaultHTTPTimeout = 30 * time.Second
+
+// HTTPOptions controls how config files named by http and https URLs are
+// fetched.
+type HTTPOptions struct {
+	// Client is used to make requests. If nil, http.DefaultClient is used.
+	Client *http.Client
+	// Timeout limits each attempt. If zero, DefaultHTTPTimeout is used.
+	Timeout time.Duration
+	// Retries is the number of additional attempts made after a failure.
+	// Only network errors and 429 or 5xx responses are retried.
+	Retries int
+	// Backoff is the delay before the first retry. It doubles after each
+	// retry.
+	Backoff time.Duration
+	// BearerToken, if set, is sent in an Authorization header.
+	BearerToken string
+	// Username and Password, if Username is set, are sent using basic
+	// authentication.
+	Username string
+	Password string
+
+	// maxSize limits the size of responses. It is set from SourcePolicy.
+	maxSize int64
+}
+
+// WithHTTP sets the options used to fetch config files from http and https
+// URLs.
+func WithHTTP(o HTTPOptions) Option {
+	return func(c *Config) {
+		c.http = o
+	}
+}
+
+// fetch retrieves url, retrying as configured by o.
+func (o HTTPOptions) fetch(ctx context.Context, url string) ([]byte, error) {
+	b, err := withRetries(ctx, o.Retries, o.Backoff, func(ctx context.Context) ([]byte, error) {
+		b, _, err := o.fetchOnce(ctx, url, "")
+		return b, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s: %w", url, err)
+	}
+	return b, nil
+}
+
+// errNotModified is returned by fetchOnce when the server reports that the
+// resource has not changed since etag was returned.
+var errNotModified = errors.New("not modified")
+
+// fetchOnce makes a single attempt to retrieve url, returning its contents and
+// ETag. If etag is not empty the request is conditional, and errNotModified
+// is returned if the contents are unchanged.
+func (o HTTPOptions) fetchOnce(ctx context.Context, url, etag string) ([]byte, string, error) {
+	timeout := o.Timeout
+	if timeout == 0 {
+		timeout = DefaultHTTPTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	switch {
+	case o.BearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+o.BearerToken)
+	case o.Username != "":
+		req.SetBasicAuth(o.Username, o.Password)
+	}
+
+	client := o.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", retryableError{err}
+	}
+	defer resp.Body.Close()
+
+	if etag != "" && resp.StatusCode == http.StatusNotModified {
+		return nil, etag, errNotModified
+	}
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("%s: %w", resp.Status, BadStatusError)
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			return nil, "", retryableError{err}
+		}
+		return nil, "", err
+	}
+	body := io.Reader(resp.Body)
+	if o.maxSize > 0 {
+		body = io.LimitReader(resp.Body, o.maxSize+1)
+	}
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return nil, "", retryableError{err}
+	}
+	if err := checkSize(b, o.maxSize); err != nil {
+		return nil, "", err
+	}
+	return b, resp.Header.Get("ETag"), nil
+}
@@ -0,0 +1,178 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/pflag"
+)
+
+func TestLoadViaHTTP(t *testing.T) {
+	const (
+		testToken    = "token"
+		testUser     = "user"
+		testPassword = "password"
+	)
+
+	good, err := os.ReadFile(testFileName(testGoodYAMLConfig))
+	if err != nil {
+		t.Fatalf("os.ReadFile failed unexpectedly: %v", err)
+	}
+
+	cases := []struct {
+		name       string
+		opts       HTTPOptions
+		failures   int
+		status     int
+		want       testConfig
+		wantErr    error
+		wantTries  int
+		wantBearer string
+		wantUser   string
+	}{
+		{
+			name:      "success",
+			wantTries: 1,
+			want: testConfig{
+				Value1: testValue1,
+				Nested: testConfig1{
+					NestedVal: testValue2,
+				},
+			},
+		},
+		{
+			name:       "bearer token",
+			opts:       HTTPOptions{BearerToken: testToken},
+			wantTries:  1,
+			wantBearer: "Bearer " + testToken,
+			want: testConfig{
+				Value1: testValue1,
+				Nested: testConfig1{
+					NestedVal: testValue2,
+				},
+			},
+		},
+		{
+			name:      "basic auth",
+			opts:      HTTPOptions{Username: testUser, Password: testPassword},
+			wantTries: 1,
+			wantUser:  testUser,
+			want: testConfig{
+				Value1: testValue1,
+				Nested: testConfig1{
+					NestedVal: testValue2,
+				},
+			},
+		},
+		{
+			name:      "retries server errors",
+			opts:      HTTPOptions{Retries: 2},
+			failures:  2,
+			status:    http.StatusServiceUnavailable,
+			wantTries: 3,
+			want: testConfig{
+				Value1: testValue1,
+				Nested: testConfig1{
+					NestedVal: testValue2,
+				},
+			},
+		},
+		{
+			name:      "gives up after retries",
+			opts:      HTTPOptions{Retries: 1},
+			failures:  2,
+			status:    http.StatusInternalServerError,
+			wantTries: 2,
+			wantErr:   BadStatusError,
+		},
+		{
+			name:      "does not retry client errors",
+			opts:      HTTPOptions{Retries: 3},
+			failures:  1,
+			status:    http.StatusNotFound,
+			wantTries: 1,
+			wantErr:   BadStatusError,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var tries int
+			var gotBearer, gotUser string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tries++
+				gotBearer = r.Header.Get("Authorization")
+				gotUser, _, _ = r.BasicAuth()
+				if tries <= tc.failures {
+					w.WriteHeader(tc.status)
+					return
+				}
+				_, _ = w.Write(good)
+			}))
+			defer srv.Close()
+
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			f.StringSlice(FileArgName, nil, testNoHelpMessage)
+			if err := f.Parse([]string{fmt.Sprintf("--%s=%s/%s", FileArgName, srv.URL, testGoodYAMLConfig)}); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+
+			c, err := New(testPrefix, testDelimiter, WithHTTP(tc.opts))
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+
+			var cfg testConfig
+			err = c.Load(f, &cfg)
+			if got, want := tries, tc.wantTries; got != want {
+				t.Errorf("tries: got=%d want=%d", got, want)
+			}
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Errorf("Load err: got=%v want=%v", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load err: got=%v want=nil", err)
+			}
+			if tc.wantBearer != "" {
+				if got, want := gotBearer, tc.wantBearer; got != want {
+					t.Errorf("Authorization: got=%q want=%q", got, want)
+				}
+			}
+			if got, want := gotUser, tc.wantUser; got != want {
+				t.Errorf("user: got=%q want=%q", got, want)
+			}
+			if diff := cmp.Diff(tc.want, cfg); diff != "" {
+				t.Errorf("Load cfg mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
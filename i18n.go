@@ -0,0 +1,188 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MessageID identifies the message of an error returned by Load, so that it
+// can be rendered in another language by a Translator. The arguments passed
+// with each message are listed next to it.
+type MessageID string
+
+const (
+	// MsgFileLoad: file, err.
+	MsgFileLoad MessageID = "file_load"
+	// MsgUnmarshal: err. Used when the failing field is unknown.
+	MsgUnmarshal MessageID = "unmarshal"
+	// MsgUnmarshalKey: key, type, err.
+	MsgUnmarshalKey MessageID = "unmarshal_key"
+	// MsgValidation: fields, the translated field checks joined by "; ".
+	MsgValidation MessageID = "validation"
+	// MsgFieldCheck: key, tag.
+	MsgFieldCheck MessageID = "field_check"
+	// MsgFieldCheckParam: key, tag, param.
+	MsgFieldCheckParam MessageID = "field_check_param"
+	// MsgMissingRequired: keys.
+	MsgMissingRequired MessageID = "missing_required"
+	// MsgNotOneOf: key, value, allowed.
+	MsgNotOneOf MessageID = "not_one_of"
+)
+
+// Translator renders messages in the operator's language. Translate returns
+// false if it has no translation for id, in which case the English message is
+// used.
+type Translator interface {
+	Translate(id MessageID, args map[string]string) (string, bool)
+}
+
+// Catalog is a Translator holding a template for each message. Templates refer
+// to arguments by name in braces:
+//
+//	goconfig.Catalog{
+//	  goconfig.MsgMissingRequired: "Pflichtschlüssel fehlen: {keys}",
+//	}
+type Catalog map[MessageID]string
+
+// Translate renders the template for id with args.
+func (c Catalog) Translate(id MessageID, args map[string]string) (string, bool) {
+	tmpl, ok := c[id]
+	if !ok {
+		return "", false
+	}
+	pairs := make([]string, 0, 2*len(args))
+	for name, value := range args {
+		pairs = append(pairs, "{"+name+"}", value)
+	}
+	return strings.NewReplacer(pairs...).Replace(tmpl), true
+}
+
+// WithTranslator makes the errors returned by Load render their messages with
+// tr. errors.Is and errors.As see the same errors as without it.
+func WithTranslator(tr Translator) Option {
+	return func(c *Config) {
+		c.translator = tr
+	}
+}
+
+// Localize returns the message of err rendered by tr, falling back to English
+// for messages tr does not translate and for errors without a MessageID.
+func Localize(err error, tr Translator) string {
+	if err == nil {
+		return ""
+	}
+	var le *LoadErrors
+	if errors.As(err, &le) {
+		ss := make([]string, len(le.Errors))
+		for i, err := range le.Errors {
+			ss[i] = Localize(err, tr)
+		}
+		return strings.Join(ss, "\n")
+	}
+	var m messager
+	if !errors.As(err, &m) {
+		return err.Error()
+	}
+	id, args := m.message(tr)
+	if s, ok := tr.Translate(id, args); ok {
+		return s
+	}
+	return err.Error()
+}
+
+// messager is implemented by errors that have a MessageID. Arguments that are
+// themselves errors are rendered with tr.
+type messager interface {
+	message(tr Translator) (MessageID, map[string]string)
+}
+
+// messageError gives err, usually wrapping a sentinel error, a MessageID.
+type messageError struct {
+	id   MessageID
+	args map[string]string
+	err  error
+}
+
+// newMessageError returns an error with the message id and args whose English
+// text and wrapped errors are those of err.
+func newMessageError(id MessageID, args map[string]string, err error) error {
+	return &messageError{id: id, args: args, err: err}
+}
+
+func (e *messageError) Error() string { return e.err.Error() }
+func (e *messageError) Unwrap() error { return e.err }
+
+func (e *messageError) message(Translator) (MessageID, map[string]string) {
+	return e.id, e.args
+}
+
+func (e *FileLoadError) message(tr Translator) (MessageID, map[string]string) {
+	return MsgFileLoad, map[string]string{"file": e.File, "err": Localize(e.Err, tr)}
+}
+
+func (e *UnmarshalError) message(tr Translator) (MessageID, map[string]string) {
+	if e.Key == "" {
+		return MsgUnmarshal, map[string]string{"err": Localize(e.Err, tr)}
+	}
+	return MsgUnmarshalKey, map[string]string{"key": e.Key, "type": fmt.Sprint(e.Type), "err": Localize(e.Err, tr)}
+}
+
+func (e *ValidationError) message(tr Translator) (MessageID, map[string]string) {
+	ss := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		id, args := f.message()
+		s, ok := tr.Translate(id, args)
+		if !ok {
+			s = f.String()
+		}
+		ss[i] = s
+	}
+	return MsgValidation, map[string]string{"fields": strings.Join(ss, "; ")}
+}
+
+func (e FieldError) message() (MessageID, map[string]string) {
+	if e.Param == "" {
+		return MsgFieldCheck, map[string]string{"key": e.Key, "tag": e.Tag}
+	}
+	return MsgFieldCheckParam, map[string]string{"key": e.Key, "tag": e.Tag, "param": e.Param}
+}
+
+// translatedError renders err with a Translator.
+type translatedError struct {
+	err error
+	tr  Translator
+}
+
+func (e *translatedError) Error() string { return Localize(e.err, e.tr) }
+func (e *translatedError) Unwrap() error { return e.err }
+
+// translate returns err rendered with the Translator set by WithTranslator.
+func (c Config) translate(err error) error {
+	if err == nil || c.translator == nil {
+		return err
+	}
+	return &translatedError{err: err, tr: c.translator}
+}
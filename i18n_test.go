@@ -0,0 +1,105 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+var testCatalog = Catalog{
+	MsgMissingRequired: "Pflichtschlüssel fehlen: {keys}",
+	MsgNotOneOf:        "{key}: {value} ist nicht in [{allowed}]",
+	MsgValidation:      "Prüfung fehlgeschlagen: {fields}",
+	MsgFieldCheck:      "{key} verletzt {tag}",
+}
+
+func TestWithTranslator(t *testing.T) {
+	cases := []struct {
+		name    string
+		args    []string
+		cfg     interface{}
+		opts    []Option
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "required",
+			cfg:     &testRequiredConfig{},
+			want:    "Pflichtschlüssel fehlen: value1, value2",
+			wantErr: MissingRequiredError,
+		},
+		{
+			name:    "one of",
+			args:    []string{"--level=trace"},
+			cfg:     &testOneOfConfig{},
+			want:    "level: trace ist nicht in [debug, info, warn, error]",
+			wantErr: NotOneOfError,
+		},
+		{
+			name: "validation falls back per field",
+			args: []string{"--server.port=70000"},
+			cfg:  &testTaggedConfig{},
+			opts: []Option{WithValidation()},
+			want: `Prüfung fehlgeschlagen: name verletzt required; server.port: failed "max" check (65535)`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			f.String("level", "", testNoHelpMessage)
+			f.Int("server.port", 0, testNoHelpMessage)
+			if err := f.Parse(tc.args); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+			c, err := New(testPrefix, testDelimiter, append(tc.opts, WithTranslator(testCatalog))...)
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+
+			err = c.Load(f, tc.cfg)
+			if err == nil {
+				t.Fatalf("Load err: got=nil want=%q", tc.want)
+			}
+			if got := err.Error(); got != tc.want {
+				t.Errorf("Load err: got=%q want=%q", got, tc.want)
+			}
+			if tc.wantErr != nil && !errors.Is(err, tc.wantErr) {
+				t.Errorf("errors.Is(%v, %v): got=false want=true", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestLocalizeFallsBack(t *testing.T) {
+	err := &FileLoadError{File: "a.json", Err: errors.New("boom")}
+	if got, want := Localize(err, Catalog{}), err.Error(); got != want {
+		t.Errorf("Localize: got=%q want=%q", got, want)
+	}
+	tr := Catalog{MsgFileLoad: "Datei {file}: {err}"}
+	if got, want := Localize(err, tr), "Datei a.json: boom"; got != want {
+		t.Errorf("Localize: got=%q want=%q", got, want)
+	}
+}
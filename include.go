@@ -0,0 +1,167 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+
+	"github.com/knadh/koanf/v2"
+)
+
+var (
+	IncludeCycleError = errors.New("config files include each other")
+)
+
+// includeKey is the top level key listing the files included by a config
+// file.
+const includeKey = "include"
+
+// fileLayer returns the layer loading the config file fa, and the files it
+// includes.
+func (c Config) fileLayer(fa fileArg) layer {
+	name := fa.name
+	ly := layer{
+		name:     name,
+		desc:     "file " + name,
+		file:     true,
+		mount:    fa.mount,
+		optional: fa.optional,
+		base:     fa.base,
+		open: func(l *loaded) (koanf.Provider, koanf.Parser, error) {
+			if isAgeFile(name) {
+				return c.ageProvider(l.ctx, name)
+			}
+			if p, ok := c.streamProvider(name); ok {
+				return p, nil, nil
+			}
+			if p, ok, err := c.cachedProvider(l, name); ok || err != nil {
+				return p, nil, err
+			}
+			p, err := c.provider(l.ctx, name)
+			if err != nil {
+				return nil, nil, err
+			}
+			parser, err := c.parserFor(name)
+			if err != nil {
+				return nil, nil, err
+			}
+			return p, parser, nil
+		},
+	}
+	ly.include = func(l *loaded, k *koanf.Koanf) error {
+		return c.loadIncludes(l, fa, k)
+	}
+	if len(c.migrations) > 0 {
+		ly.migrate = c.migrate
+	}
+	if c.interpolate {
+		ly.expand = c.interpolateValues
+	}
+	return ly
+}
+
+// loadIncludes loads the files listed under includeKey in k, the values of
+// the config file fa, in order, so that the including file overrides them.
+// Included paths are relative to the including file and may be globs or
+// directories. includeKey is removed from k.
+func (c Config) loadIncludes(l *loaded, fa fileArg, k *koanf.Koanf) error {
+	var names []string
+	switch v := k.Get(includeKey).(type) {
+	case nil:
+		return nil
+	case string:
+		names = []string{v}
+	default:
+		names = k.Strings(includeKey)
+	}
+	k.Delete(includeKey)
+
+	id := c.fileID(fa.name)
+	if l.including == nil {
+		l.including = make(map[string]bool)
+	}
+	l.including[id] = true
+	defer delete(l.including, id)
+
+	for _, name := range names {
+		matches, err := c.expandGlob(fileArg{name: c.fsPath(resolveInclude(fa.name, name)), mount: fa.mount})
+		if err != nil {
+			return err
+		}
+		for _, m := range matches {
+			files, err := c.expandDir(m)
+			if err != nil {
+				return err
+			}
+			for _, inc := range files {
+				if l.including[c.fileID(inc.name)] {
+					return fmt.Errorf("include %s: %w", inc.name, IncludeCycleError)
+				}
+				if err := l.loadLayer(c.fileLayer(inc)); err != nil {
+					return fmt.Errorf("include %s: %w", inc.name, err)
+				}
+				l.overrideFile(fa.name, inc.name)
+			}
+		}
+	}
+	return nil
+}
+
+// resolveInclude returns the config file name included by the file
+// including. Relative names are resolved against the directory of including,
+// or its URL.
+func resolveInclude(including, name string) string {
+	if !localFile(including) {
+		base, err := url.Parse(including)
+		if err != nil {
+			return name
+		}
+		ref, err := url.Parse(name)
+		if err != nil {
+			return name
+		}
+		return base.ResolveReference(ref).String()
+	}
+	if !localFile(name) || filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(filepath.Dir(including), name)
+}
+
+// fileID identifies the config file name when detecting include cycles.
+func (c Config) fileID(name string) string {
+	if !localFile(name) {
+		return name
+	}
+	if c.fsys != nil {
+		return path.Clean(name)
+	}
+	if abs, err := filepath.Abs(name); err == nil {
+		return abs
+	}
+	return filepath.Clean(name)
+}
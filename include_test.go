@@ -0,0 +1,182 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/pflag"
+)
+
+func TestLoadInclude(t *testing.T) {
+	cases := []struct {
+		name    string
+		files   map[string]string
+		want    testMountConfig
+		wantErr error
+	}{
+		{
+			name: "list",
+			files: map[string]string{
+				"main.yaml":       "include: [parts/db.yaml, parts/name.yaml]\nname: main\n",
+				"parts/db.yaml":   "db:\n  host: db\n  pool:\n    size: 2\n",
+				"parts/name.yaml": "name: part\ndb:\n  pool:\n    size: 3\n",
+			},
+			want: func() (c testMountConfig) {
+				c.Name = "main"
+				c.DB.Host = "db"
+				c.DB.Pool.Size = 3
+				return c
+			}(),
+		},
+		{
+			name: "nested string relative to includer",
+			files: map[string]string{
+				"main.yaml":       "include: parts/db.yaml\n",
+				"parts/db.yaml":   "include: pool.yaml\ndb:\n  host: db\n",
+				"parts/pool.yaml": "db:\n  pool:\n    size: 4\n",
+			},
+			want: func() (c testMountConfig) {
+				c.DB.Host = "db"
+				c.DB.Pool.Size = 4
+				return c
+			}(),
+		},
+		{
+			name: "cycle",
+			files: map[string]string{
+				"main.yaml":  "include: [other.yaml]\n",
+				"other.yaml": "include: [main.yaml]\n",
+			},
+			wantErr: IncludeCycleError,
+		},
+		{
+			name: "missing",
+			files: map[string]string{
+				"main.yaml": "include: [missing.yaml]\n",
+			},
+			wantErr: os.ErrNotExist,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, contents := range tc.files {
+				name = filepath.Join(dir, name)
+				if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+					t.Fatalf("os.MkdirAll failed unexpectedly: %v", err)
+				}
+				writeTestFile(t, name, contents)
+			}
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			AddConfigFlag(f)
+			if err := f.Parse([]string{"--" + FileArgName + "=" + filepath.Join(dir, "main.yaml")}); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+			c, err := New(testPrefix, testDelimiter, WithStrict())
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+
+			var got testMountConfig
+			err = c.Load(f, &got)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Load err: got=%v want=%v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Load mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLoadIncludeConflicts(t *testing.T) {
+	cases := []struct {
+		name    string
+		files   map[string]string
+		wantErr error
+	}{
+		{
+			name: "includer overrides",
+			files: map[string]string{
+				"main.yaml": "include: [a.yaml]\nname: main\n",
+				"a.yaml":    "include: [b.yaml]\nname: a\n",
+				"b.yaml":    "name: b\ndb:\n  host: b\n",
+			},
+		},
+		{
+			name: "included files conflict",
+			files: map[string]string{
+				"main.yaml": "include: [a.yaml, b.yaml]\n",
+				"a.yaml":    "name: a\n",
+				"b.yaml":    "name: b\n",
+			},
+			wantErr: ConflictError,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, contents := range tc.files {
+				writeTestFile(t, filepath.Join(dir, name), contents)
+			}
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			AddConfigFlag(f)
+			if err := f.Parse([]string{"--" + FileArgName + "=" + filepath.Join(dir, "main.yaml")}); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+			c, err := New(testPrefix, testDelimiter, WithConflicts(ConflictFail))
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+
+			var got testMountConfig
+			if err := c.Load(f, &got); !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Load err: got=%v want=%v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestResolveInclude(t *testing.T) {
+	cases := []struct {
+		including, name, want string
+	}{
+		{"conf/app.yaml", "db.yaml", filepath.Join("conf", "db.yaml")},
+		{"conf/app.yaml", "/etc/db.yaml", "/etc/db.yaml"},
+		{"https://example.com/conf/app.yaml", "db.yaml", "https://example.com/conf/db.yaml"},
+		{"conf/app.yaml", "https://example.com/db.yaml", "https://example.com/db.yaml"},
+	}
+	for _, tc := range cases {
+		if got := resolveInclude(tc.including, tc.name); got != tc.want {
+			t.Errorf("resolveInclude(%q, %q): got=%q want=%q", tc.including, tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"os"
+	"strings"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// WithInterpolation makes Load expand references to environment variables in
+// the string values of config files, so that one file can be used where only
+// hosts or credentials differ:
+//
+//	url: postgres://${DB_USER}:${DB_PASSWORD}@${DB_HOST:-localhost}/app
+//
+// ${VAR} is replaced by the value of VAR, or nothing if it is not set, and
+// ${VAR:-default} by default if VAR is not set or empty. $${ is a literal ${.
+// Variables are not expanded in defaults. Other uses of $ are left alone. With
+// WithReferences, a variable that is not set refers to a key instead.
+func WithInterpolation() Option {
+	return func(c *Config) {
+		c.interpolate = true
+	}
+}
+
+// interpolateValues expands the environment variables in the string values of
+// k, including those in lists. With WithReferences, references that are not
+// set in the environment and $${ are kept for resolveReferences.
+func (c Config) interpolateValues(k *koanf.Koanf) error {
+	expand := func(s string) (interface{}, error) {
+		return expandBraces(s, !c.references, func(ref string) (string, bool, error) {
+			name, def, hasDefault := strings.Cut(ref, ":-")
+			v := os.Getenv(name)
+			switch {
+			case v == "" && hasDefault:
+				return def, true, nil
+			case v == "" && c.references:
+				return "", false, nil
+			}
+			return v, true, nil
+		})
+	}
+	for key, v := range k.All() {
+		nv, changed, err := rewriteStrings(v, expand)
+		if err != nil {
+			return err
+		}
+		if changed {
+			if err := k.Set(key, nv); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// rewriteStrings applies rewrite to v if it is a string, or to the strings in
+// v if it is a list, and reports whether the result differs from v.
+func rewriteStrings(v interface{}, rewrite func(string) (interface{}, error)) (interface{}, bool, error) {
+	switch v := v.(type) {
+	case string:
+		nv, err := rewrite(v)
+		if err != nil {
+			return nil, false, err
+		}
+		s, ok := nv.(string)
+		return nv, !ok || s != v, nil
+	case []interface{}:
+		changed := false
+		out := make([]interface{}, len(v))
+		for i, e := range v {
+			ne, c, err := rewriteStrings(e, rewrite)
+			if err != nil {
+				return nil, false, err
+			}
+			out[i], changed = ne, changed || c
+		}
+		return out, changed, nil
+	}
+	return v, false, nil
+}
+
+// expandBraces returns s with each ${ref} replaced by the string returned by
+// lookup, unless lookup reports that ref should be kept. If unescape is set,
+// $${ is replaced by a literal ${, otherwise it is kept along with the
+// reference that follows. A ${ without a closing brace is left alone.
+func expandBraces(s string, unescape bool, lookup func(ref string) (string, bool, error)) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+	var b strings.Builder
+	for {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			break
+		}
+		end := strings.Index(s[i:], "}")
+		if i > 0 && s[i-1] == '$' {
+			if unescape {
+				b.WriteString(s[:i] + "{")
+				s = s[i+2:]
+				continue
+			}
+			if end < 0 {
+				break
+			}
+			b.WriteString(s[:i+end+1])
+			s = s[i+end+1:]
+			continue
+		}
+		if end < 0 {
+			break
+		}
+		ref := s[i+2 : i+end]
+		v, ok, err := lookup(ref)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			v = s[i : i+end+1]
+		}
+		b.WriteString(s[:i] + v)
+		s = s[i+end+1:]
+	}
+	b.WriteString(s)
+	return b.String(), nil
+}
@@ -0,0 +1,90 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knadh/koanf/v2"
+	"github.com/spf13/pflag"
+)
+
+func TestInterpolate(t *testing.T) {
+	t.Setenv("GOCONFIG_TEST_HOST", "db.example.com")
+	t.Setenv("GOCONFIG_TEST_EMPTY", "")
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{in: "plain", want: "plain"},
+		{in: "${GOCONFIG_TEST_HOST}:5432", want: "db.example.com:5432"},
+		{in: "${GOCONFIG_TEST_UNSET}", want: ""},
+		{in: "${GOCONFIG_TEST_UNSET:-localhost}", want: "localhost"},
+		{in: "${GOCONFIG_TEST_EMPTY:-localhost}", want: "localhost"},
+		{in: "${GOCONFIG_TEST_HOST:-localhost}", want: "db.example.com"},
+		{in: "$${GOCONFIG_TEST_HOST}", want: "${GOCONFIG_TEST_HOST}"},
+		{in: "pa$$word $GOCONFIG_TEST_HOST", want: "pa$$word $GOCONFIG_TEST_HOST"},
+		{in: "${GOCONFIG_TEST_HOST", want: "${GOCONFIG_TEST_HOST"},
+	}
+	for _, tc := range cases {
+		k := koanf.New(testDelimiter)
+		if err := k.Set(testKey1, tc.in); err != nil {
+			t.Fatalf("k.Set failed unexpectedly: %v", err)
+		}
+		if err := (Config{}).interpolateValues(k); err != nil {
+			t.Errorf("interpolateValues(%q) err: got=%v want=nil", tc.in, err)
+			continue
+		}
+		if got := k.String(testKey1); got != tc.want {
+			t.Errorf("interpolateValues(%q): got=%q want=%q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestWithInterpolation(t *testing.T) {
+	t.Setenv("GOCONFIG_TEST_HOST", "db.example.com")
+	name := filepath.Join(t.TempDir(), "config.yaml")
+	writeTestFile(t, name, "name: ${GOCONFIG_TEST_NAME:-app}\ndb:\n  host: ${GOCONFIG_TEST_HOST}\n")
+
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	AddConfigFlag(f)
+	if err := f.Parse([]string{"--" + FileArgName + "=" + name}); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	c, err := New(testPrefix, testDelimiter, WithInterpolation())
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	var got testMountConfig
+	if err := c.Load(f, &got); err != nil {
+		t.Fatalf("Load err: got=%v want=nil", err)
+	}
+	var want testMountConfig
+	want.Name = "app"
+	want.DB.Host = "db.example.com"
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Load mismatch (-want +got):\n%s", diff)
+	}
+}
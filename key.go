@@ -0,0 +1,99 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import "strings"
+
+// Key is a configuration key path, such as db.pool.size, held as its
+// segments so that it does not depend on a delimiter. ParseKey and Format
+// convert keys to and from the delimited strings used by sources.
+type Key []string
+
+// ParseKey splits s into the segments delimited by delim. The empty string is
+// the empty key.
+func ParseKey(s, delim string) Key {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, delim)
+}
+
+// Key parses s using the delimiter of c.
+func (c Config) Key(s string) Key {
+	return ParseKey(s, c.delimiter)
+}
+
+// Join returns k followed by segments. k is not modified.
+func (k Key) Join(segments ...string) Key {
+	joined := make(Key, 0, len(k)+len(segments))
+	return append(append(joined, k...), segments...)
+}
+
+// Parent returns k without its last segment, or the empty key if k is empty
+// or has one segment.
+func (k Key) Parent() Key {
+	if len(k) <= 1 {
+		return nil
+	}
+	return k[: len(k)-1 : len(k)-1]
+}
+
+// Base returns the last segment of k, or "" if k is empty.
+func (k Key) Base() string {
+	if len(k) == 0 {
+		return ""
+	}
+	return k[len(k)-1]
+}
+
+// HasPrefix reports whether prefix is k or one of its ancestors. Every key
+// has the empty key as a prefix.
+func (k Key) HasPrefix(prefix Key) bool {
+	if len(prefix) > len(k) {
+		return false
+	}
+	return k[:len(prefix)].Equal(prefix)
+}
+
+// Equal reports whether k and other have the same segments.
+func (k Key) Equal(other Key) bool {
+	if len(k) != len(other) {
+		return false
+	}
+	for i := range k {
+		if k[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Format returns k with its segments separated by delim.
+func (k Key) Format(delim string) string {
+	return strings.Join(k, delim)
+}
+
+// String returns k delimited by periods, for use in messages.
+func (k Key) String() string {
+	return k.Format(".")
+}
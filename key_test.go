@@ -0,0 +1,90 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseKey(t *testing.T) {
+	cases := []struct {
+		s     string
+		delim string
+		want  Key
+	}{
+		{"", ".", nil},
+		{"port", ".", Key{"port"}},
+		{"db.pool.size", ".", Key{"db", "pool", "size"}},
+		{"db/pool.size", "/", Key{"db", "pool.size"}},
+	}
+	for _, tc := range cases {
+		got := ParseKey(tc.s, tc.delim)
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("ParseKey(%q, %q) mismatch (-want +got):\n%s", tc.s, tc.delim, diff)
+		}
+		if got := got.Format(tc.delim); got != tc.s {
+			t.Errorf("Format(%q): got=%q want=%q", tc.delim, got, tc.s)
+		}
+	}
+}
+
+func TestKeyJoinParent(t *testing.T) {
+	db := Key{"db"}
+	size := db.Join("pool", "size")
+	host := db.Join("host")
+	if got, want := size.Format("_"), "db_pool_size"; got != want {
+		t.Errorf("Join: got=%q want=%q", got, want)
+	}
+	if got, want := host.String(), "db.host"; got != want {
+		t.Errorf("Join shares storage: got=%q want=%q", got, want)
+	}
+	if got, want := size.Parent(), (Key{"db", "pool"}); !got.Equal(want) {
+		t.Errorf("Parent: got=%v want=%v", got, want)
+	}
+	if got := db.Parent(); got != nil {
+		t.Errorf("Parent of one segment: got=%v want=nil", got)
+	}
+	if got, want := size.Base(), "size"; got != want {
+		t.Errorf("Base: got=%q want=%q", got, want)
+	}
+}
+
+func TestKeyHasPrefix(t *testing.T) {
+	cases := []struct {
+		k, prefix Key
+		want      bool
+	}{
+		{Key{"db", "host"}, Key{"db"}, true},
+		{Key{"db", "host"}, Key{"db", "host"}, true},
+		{Key{"db", "host"}, nil, true},
+		{Key{"dbx", "host"}, Key{"db"}, false},
+		{Key{"db"}, Key{"db", "host"}, false},
+	}
+	for _, tc := range cases {
+		if got := tc.k.HasPrefix(tc.prefix); got != tc.want {
+			t.Errorf("%v.HasPrefix(%v): got=%t want=%t", tc.k, tc.prefix, got, tc.want)
+		}
+	}
+}
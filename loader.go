@@ -0,0 +1,69 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"context"
+	"sync"
+
+	"github.com/spf13/pflag"
+)
+
+// Loader remembers the flag set and Config of a load, and with them the config
+// files, sources and options, so that the full load can be run again, for
+// example from a SIGHUP handler or an admin endpoint. It is safe for
+// concurrent use.
+type Loader[T any] struct {
+	mu sync.Mutex
+	r  *reloader[T]
+}
+
+// NewLoader loads cfg as LoadContext does and returns a Loader that can load
+// it again. cfg is not modified after NewLoader returns.
+func NewLoader[T any](ctx context.Context, c Config, f *pflag.FlagSet, cfg *T) (*Loader[T], error) {
+	r, err := newReloader(ctx, c, f, cfg, func(_, _ T) error { return nil })
+	if err != nil {
+		return nil, err
+	}
+	return &Loader[T]{r: r}, nil
+}
+
+// Current returns the value of the last successful load.
+func (l *Loader[T]) Current() T {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.current
+}
+
+// Reload runs the full load again with the original inputs and returns the new
+// value. Changed keys are reported to the function passed to WithOnKeyChange.
+// If loading fails, the error is returned and Current is not changed.
+func (l *Loader[T]) Reload(ctx context.Context) (T, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.r.next(ctx); err != nil {
+		var zero T
+		return zero, err
+	}
+	return l.r.current, nil
+}
@@ -0,0 +1,89 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestLoaderReload(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "config.json")
+	writeTestFile(t, name, fmt.Sprintf(`{"%s": %d}`, testKey1, testValue1))
+
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	f.Int(testKey2, testDefaultValue2, testNoHelpMessage)
+	f.StringSlice(FileArgName, nil, testNoHelpMessage)
+	args := []string{
+		fmt.Sprintf("--%s=%s", FileArgName, name),
+		fmt.Sprintf("--%s=%d", testKey2, testValue3),
+	}
+	if err := f.Parse(args); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+
+	var changed []KeyChange
+	c, err := New(testPrefix, testDelimiter, WithOnKeyChange(func(ch []KeyChange) { changed = ch }))
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	var cfg testConfig
+	l, err := NewLoader(context.Background(), c, f, &cfg)
+	if err != nil {
+		t.Fatalf("NewLoader err: got=%v want=nil", err)
+	}
+	if got, want := cfg.Value1, testValue1; got != want {
+		t.Errorf("initial Value1: got=%d want=%d", got, want)
+	}
+
+	// The flag must still take precedence over the file after reloading.
+	writeTestFile(t, name, fmt.Sprintf(`{"%s": %d, "%s": %d}`, testKey1, testValue2, testKey2, testValue1))
+	got, err := l.Reload(context.Background())
+	if err != nil {
+		t.Fatalf("Reload err: got=%v want=nil", err)
+	}
+	want := testConfig{Value1: testValue2, Value2: testValue3}
+	if got != want {
+		t.Errorf("Reload: got=%+v want=%+v", got, want)
+	}
+	if got := l.Current(); got != want {
+		t.Errorf("Current: got=%+v want=%+v", got, want)
+	}
+	if got, want := len(changed), 1; got != want {
+		t.Errorf("key changes: got=%d want=%d", got, want)
+	}
+
+	// A failed reload returns the error and keeps the current value.
+	writeTestFile(t, name, "{")
+	if _, err := l.Reload(context.Background()); err == nil {
+		t.Errorf("Reload of invalid file err: got=nil want=non-nil")
+	}
+	if got := l.Current(); got != want {
+		t.Errorf("Current after failed reload: got=%+v want=%+v", got, want)
+	}
+}
@@ -0,0 +1,75 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// LoadKey is like Load, but loads only the keys below prefix, such as
+// "database", into cfg, so that a library can own its part of the
+// configuration without seeing the application struct:
+//
+//	var db DatabaseConfig
+//	err := c.LoadKey("database", f, &db)
+//
+// The fields of cfg are set by the same keys, environment variables and flags
+// as if cfg were the field at prefix of a larger struct, so database.host is
+// set by APP_DATABASE_HOST and --database.host. Keys outside prefix are
+// ignored, even with WithStrict, which only reports the unknown keys below
+// prefix. cfg must be a pointer to a struct.
+func (c Config) LoadKey(prefix string, f *pflag.FlagSet, cfg interface{}) error {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("LoadKey %T: %w", cfg, NotStructError)
+	}
+	if prefix == "" {
+		return c.Load(f, cfg)
+	}
+
+	names := strings.Split(prefix, c.delimiter)
+	t := v.Elem().Type()
+	for i := len(names) - 1; i >= 0; i-- {
+		t = reflect.StructOf([]reflect.StructField{{
+			Name: "Key",
+			Type: t,
+			Tag:  reflect.StructTag(fmt.Sprintf(`koanf:%q`, names[i])),
+		}})
+	}
+	wrapper := reflect.New(t)
+	c.strictPrefix = prefix
+	if _, err := c.load(context.Background(), f, wrapper.Interface()); err != nil {
+		return err
+	}
+	sub := wrapper.Elem()
+	for range names {
+		sub = sub.Field(0)
+	}
+	v.Elem().Set(sub)
+	return nil
+}
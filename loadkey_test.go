@@ -0,0 +1,136 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/pflag"
+)
+
+type testLoadKeyConfig struct {
+	Host string `koanf:"host"`
+	Port int    `koanf:"port" default:"5432"`
+	User string `koanf:"user,required"`
+}
+
+func TestLoadKey(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "config.yaml")
+	writeTestFile(t, name, "name: app\ndatabase:\n  primary:\n    host: file\n    user: admin\n")
+	t.Setenv(testPrefix+"DATABASE_PRIMARY_HOST", "env")
+
+	cases := []struct {
+		prefix  string
+		args    []string
+		want    testLoadKeyConfig
+		wantErr error
+	}{
+		{
+			prefix: "database.primary",
+			want:   testLoadKeyConfig{Host: "env", Port: 5432, User: "admin"},
+		},
+		{
+			prefix: "database.primary",
+			args:   []string{"--database.primary.port=1"},
+			want:   testLoadKeyConfig{Host: "env", Port: 1, User: "admin"},
+		},
+		{
+			prefix:  "database.replica",
+			wantErr: MissingRequiredError,
+		},
+	}
+	for _, tc := range cases {
+		f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+		AddConfigFlag(f)
+		f.Int("database.primary.port", 0, "")
+		if err := f.Parse(append([]string{"--" + FileArgName + "=" + name}, tc.args...)); err != nil {
+			t.Fatalf("f.Parse failed unexpectedly: %v", err)
+		}
+		c, err := New(testPrefix, testDelimiter, WithStrict())
+		if err != nil {
+			t.Fatalf("New failed unexpectedly: %v", err)
+		}
+
+		var got testLoadKeyConfig
+		err = c.LoadKey(tc.prefix, f, &got)
+		if !errors.Is(err, tc.wantErr) {
+			t.Errorf("LoadKey(%q) err: got=%v want=%v", tc.prefix, err, tc.wantErr)
+			continue
+		}
+		if diff := cmp.Diff(tc.want, got); err == nil && diff != "" {
+			t.Errorf("LoadKey(%q) mismatch (-want +got):\n%s", tc.prefix, diff)
+		}
+	}
+}
+
+func TestLoadKeyStrict(t *testing.T) {
+	cases := []struct {
+		name    string
+		file    string
+		wantErr error
+	}{
+		{
+			name: "unknown outside prefix",
+			file: "name: app\ndatabase:\n  primary:\n    user: admin\n  replica:\n    hots: x\n",
+		},
+		{
+			name:    "unknown inside prefix",
+			file:    "database:\n  primary:\n    user: admin\n    hots: x\n",
+			wantErr: UnknownKeyError,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			name := filepath.Join(t.TempDir(), "config.yaml")
+			writeTestFile(t, name, tc.file)
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			AddConfigFlag(f)
+			if err := f.Parse([]string{"--" + FileArgName + "=" + name}); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+			c, err := New(testPrefix, testDelimiter, WithStrict())
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+
+			var got testLoadKeyConfig
+			if err := c.LoadKey("database.primary", f, &got); !errors.Is(err, tc.wantErr) {
+				t.Errorf("LoadKey err: got=%v want=%v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadKeyNotPointer(t *testing.T) {
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	if err := c.LoadKey("database", f, testLoadKeyConfig{}); !errors.Is(err, NotStructError) {
+		t.Errorf("LoadKey err: got=%v want=%v", err, NotStructError)
+	}
+}
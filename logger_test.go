@@ -0,0 +1,64 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestWithLogger(t *testing.T) {
+	dir := t.TempDir()
+	first, second := filepath.Join(dir, "a.yaml"), filepath.Join(dir, "b.yaml")
+	writeTestFile(t, first, fmt.Sprintf("%s: %d\n", testKey1, testValue1))
+	writeTestFile(t, second, fmt.Sprintf("%s: %d\n", testKey1, testValue2))
+
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	f.StringSlice(FileArgName, nil, testNoHelpMessage)
+	args := []string{
+		fmt.Sprintf("--%s=%s", FileArgName, first),
+		fmt.Sprintf("--%s=%s", FileArgName, second),
+	}
+	if err := f.Parse(args); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+
+	var buf bytes.Buffer
+	c, err := New(testPrefix, testDelimiter, WithConflicts(ConflictWarn), WithLogger(log.New(&buf, "", 0)))
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	var cfg testConfig
+	if err := c.Load(f, &cfg); err != nil {
+		t.Fatalf("Load err: got=%v want=nil", err)
+	}
+	if !strings.Contains(buf.String(), "conflict") {
+		t.Errorf("logged: got=%q want a conflict", buf.String())
+	}
+}
@@ -0,0 +1,63 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"fmt"
+)
+
+// WatchLogLevel calls set with the value of key, or "log.level" (using the
+// configured delimiter) if key is empty, in the current snapshot of w and again
+// every time a reload changes it. It is intended to switch the level of a
+// logger while the process runs, for example with a slog.LevelVar:
+//
+//	goconfig.WatchLogLevel(w, "", func(level string) error {
+//	  return levelVar.UnmarshalText([]byte(level))
+//	})
+//
+// Errors returned by set are logged and the previous level is kept. Watching
+// stops when w stops.
+func WatchLogLevel[T any](w *Watcher[T], key string, set func(level string) error) {
+	if key == "" {
+		key = Key{"log", "level"}.Format(w.c.delimiter)
+	}
+
+	ch := w.SubscribePrefix(key)
+	go func() {
+		for snap := range ch {
+			m, err := w.c.flatten(snap.Config)
+			if err != nil {
+				w.c.logf("WatchLogLevel: %v", err)
+				continue
+			}
+			v, ok := m[key]
+			if !ok {
+				w.c.logf("WatchLogLevel: no field for key %s", key)
+				continue
+			}
+			if err := set(fmt.Sprint(v)); err != nil {
+				w.c.logf("WatchLogLevel %s: %v", key, err)
+			}
+		}
+	}()
+}
@@ -0,0 +1,64 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"testing"
+	"time"
+)
+
+type testLogConfig struct {
+	Log struct {
+		Level string `koanf:"level"`
+	} `koanf:"log"`
+}
+
+func TestWatchLogLevel(t *testing.T) {
+	w := &Watcher[testLogConfig]{c: Config{delimiter: testDelimiter}, current: Snapshot[testLogConfig]{Version: 1}}
+	w.current.Config.Log.Level = "info"
+
+	levels := make(chan string, 2)
+	WatchLogLevel(w, "", func(level string) error {
+		levels <- level
+		return nil
+	})
+
+	wantLevel := func(want string) {
+		t.Helper()
+		select {
+		case got := <-levels:
+			if got != want {
+				t.Errorf("level: got=%q want=%q", got, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for level %q", want)
+		}
+	}
+	wantLevel("info")
+
+	var next testLogConfig
+	next.Log.Level = "debug"
+	w.publish(next, []KeyChange{{Path: "log.level", Old: "info", New: "debug"}})
+	wantLevel("debug")
+	w.close()
+}
@@ -0,0 +1,166 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/knadh/koanf/maps"
+)
+
+var (
+	BadManifestKindError = errors.New("manifest kind must be ConfigMap or Secret")
+	BadManifestKeyError  = errors.New("invalid ConfigMap or Secret data key")
+)
+
+// Kinds of manifests produced by Manifest.
+const (
+	KindConfigMap = "ConfigMap"
+	KindSecret    = "Secret"
+)
+
+// manifestKeyPattern matches the keys Kubernetes allows in ConfigMap and
+// Secret data.
+var manifestKeyPattern = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+
+// ManifestOptions controls the manifest produced by Manifest.
+type ManifestOptions struct {
+	// Kind is KindConfigMap or KindSecret. If empty, KindConfigMap is used.
+	Kind string
+	// Name and Namespace are placed in the manifest metadata.
+	Name      string
+	Namespace string
+	// FileName, if set, stores the whole configuration as a single YAML file
+	// under this key, suitable for mounting and passing with --config.
+	// Otherwise every value is stored under the name of the environment
+	// variable that sets it, suitable for use with envFrom.
+	FileName string
+}
+
+type manifestMetadata struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace,omitempty"`
+}
+
+type manifest struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   manifestMetadata  `yaml:"metadata"`
+	Type       string            `yaml:"type,omitempty"`
+	Data       map[string]string `yaml:"data"`
+}
+
+// Manifest renders cfg as a Kubernetes ConfigMap or Secret manifest. Passing
+// the struct filled in by Load renders the effective configuration, while
+// passing a struct containing example values renders an example.
+func (c Config) Manifest(cfg interface{}, o ManifestOptions) ([]byte, error) {
+	kind := o.Kind
+	if kind == "" {
+		kind = KindConfigMap
+	}
+	if kind != KindConfigMap && kind != KindSecret {
+		return nil, fmt.Errorf("Manifest kind %q: %w", kind, BadManifestKindError)
+	}
+
+	if kind == KindConfigMap {
+		c.warnSecrets("Manifest", cfg)
+	}
+
+	flat, err := c.flatten(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("Manifest: %w", err)
+	}
+
+	data := make(map[string]string)
+	if o.FileName != "" {
+		b, err := marshalYAML(maps.Unflatten(flat, c.delimiter))
+		if err != nil {
+			return nil, fmt.Errorf("Manifest marshal: %w", err)
+		}
+		data[o.FileName] = string(b)
+	} else {
+		tags, err := c.envTags(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("Manifest: %w", err)
+		}
+		for k, v := range flat {
+			name, ok := tags[k]
+			if !ok {
+				name = c.envName(k)
+			}
+			data[name] = manifestValue(v)
+		}
+	}
+
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if !manifestKeyPattern.MatchString(k) {
+			return nil, fmt.Errorf("Manifest key %q: %w", k, BadManifestKeyError)
+		}
+		if kind == KindSecret {
+			data[k] = base64.StdEncoding.EncodeToString([]byte(data[k]))
+		}
+	}
+
+	m := manifest{
+		APIVersion: "v1",
+		Kind:       kind,
+		Metadata: manifestMetadata{
+			Name:      o.Name,
+			Namespace: o.Namespace,
+		},
+		Data: data,
+	}
+	if kind == KindSecret {
+		m.Type = "Opaque"
+	}
+	b, err := marshalYAML(m)
+	if err != nil {
+		return nil, fmt.Errorf("Manifest marshal: %w", err)
+	}
+	return b, nil
+}
+
+// manifestValue formats v the way it would be written in an environment
+// variable. Slices are joined with commas.
+func manifestValue(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() != reflect.Uint8 {
+		ss := make([]string, rv.Len())
+		for i := range ss {
+			ss[i] = fmt.Sprint(rv.Index(i).Interface())
+		}
+		return strings.Join(ss, ",")
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,125 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestManifest(t *testing.T) {
+	cfg := testConfig{
+		Value1: testValue1,
+		Nested: testConfig1{
+			NestedVal: testValue2,
+		},
+	}
+
+	cases := []struct {
+		name    string
+		opts    ManifestOptions
+		want    string
+		wantErr error
+	}{
+		{
+			name: "configmap env",
+			opts: ManifestOptions{Name: "app", Namespace: "ns"},
+			want: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+  namespace: ns
+data:
+  TEST_NESTED_NESTEDVALUE: "102"
+  TEST_VALUE1: "101"
+  TEST_VALUE2: "0"
+  TEST_VALUE3: "0"
+`,
+		},
+		{
+			name: "configmap file",
+			opts: ManifestOptions{Name: "app", FileName: "config.yaml"},
+			want: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+data:
+  config.yaml: |
+    nested:
+      nestedvalue: 102
+    value1: 101
+    value2: 0
+    value3: 0
+`,
+		},
+		{
+			name: "secret",
+			opts: ManifestOptions{Kind: KindSecret, Name: "app"},
+			want: `apiVersion: v1
+kind: Secret
+metadata:
+  name: app
+type: Opaque
+data:
+  TEST_NESTED_NESTEDVALUE: MTAy
+  TEST_VALUE1: MTAx
+  TEST_VALUE2: MA==
+  TEST_VALUE3: MA==
+`,
+		},
+		{
+			name:    "bad kind",
+			opts:    ManifestOptions{Kind: "Pod"},
+			wantErr: BadManifestKindError,
+		},
+		{
+			name:    "bad key",
+			opts:    ManifestOptions{FileName: "config/app.yaml"},
+			wantErr: BadManifestKeyError,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := New(testPrefix, testDelimiter)
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+
+			got, err := c.Manifest(cfg, tc.opts)
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Errorf("Manifest err: got=%v want=%v", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Manifest err: got=%v want=nil", err)
+			}
+			if diff := cmp.Diff(tc.want, string(got)); diff != "" {
+				t.Errorf("Manifest mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
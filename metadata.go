@@ -0,0 +1,104 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+)
+
+// SourceMetadata names the build and runtime metadata layer when reporting
+// where values came from.
+const SourceMetadata = "metadata"
+
+// Top level keys of the metadata layer.
+const (
+	MetadataRuntimeKey = "runtime"
+	MetadataBuildKey   = "build"
+)
+
+// WithMetadata adds read only keys describing the running binary. They are
+// loaded after every other source, so they cannot be overridden:
+//
+//	runtime.hostname, runtime.pid, runtime.num_cpu, runtime.goos,
+//	runtime.goarch, runtime.go_version
+//	build.version, build.path, build.commit, build.time, build.modified
+//
+// The build keys come from the information embedded by the go command and are
+// empty if it is not available.
+func WithMetadata() Option {
+	return func(c *Config) {
+		c.metadata = true
+	}
+}
+
+// metadataProvider is a koanf.Provider for build and runtime metadata.
+type metadataProvider struct{}
+
+// ReadBytes is not supported because metadata is read as a map.
+func (metadataProvider) ReadBytes() ([]byte, error) {
+	return nil, errors.New("metadataProvider does not support ReadBytes")
+}
+
+// Read returns the metadata.
+func (metadataProvider) Read() (map[string]interface{}, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("hostname: %w", err)
+	}
+	build := map[string]interface{}{
+		"version":  "",
+		"path":     "",
+		"commit":   "",
+		"time":     "",
+		"modified": false,
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		build["version"] = bi.Main.Version
+		build["path"] = bi.Main.Path
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				build["commit"] = s.Value
+			case "vcs.time":
+				build["time"] = s.Value
+			case "vcs.modified":
+				build["modified"] = s.Value == "true"
+			}
+		}
+	}
+	return map[string]interface{}{
+		MetadataRuntimeKey: map[string]interface{}{
+			"hostname":   hostname,
+			"pid":        os.Getpid(),
+			"num_cpu":    runtime.NumCPU(),
+			"goos":       runtime.GOOS,
+			"goarch":     runtime.GOARCH,
+			"go_version": runtime.Version(),
+		},
+		MetadataBuildKey: build,
+	}, nil
+}
@@ -0,0 +1,73 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+type testMetadataConfig struct {
+	Runtime struct {
+		Hostname string `koanf:"hostname"`
+		NumCPU   int    `koanf:"num_cpu"`
+		GOOS     string `koanf:"goos"`
+	} `koanf:"runtime"`
+	Build struct {
+		Version string `koanf:"version"`
+	} `koanf:"build"`
+}
+
+func TestLoadMetadata(t *testing.T) {
+	// Metadata cannot be overridden by the environment.
+	t.Setenv(strings.ToUpper(testPrefix+"runtime_goos"), "plan9")
+
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	c, err := New(testPrefix, testDelimiter, WithMetadata())
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	var cfg testMetadataConfig
+	if err := c.Load(f, &cfg); err != nil {
+		t.Fatalf("Load err: got=%v want=nil", err)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Fatalf("os.Hostname failed unexpectedly: %v", err)
+	}
+	if got, want := cfg.Runtime.Hostname, hostname; got != want {
+		t.Errorf("hostname: got=%q want=%q", got, want)
+	}
+	if got, want := cfg.Runtime.NumCPU, runtime.NumCPU(); got != want {
+		t.Errorf("num_cpu: got=%d want=%d", got, want)
+	}
+	if got, want := cfg.Runtime.GOOS, runtime.GOOS; got != want {
+		t.Errorf("goos: got=%q want=%q", got, want)
+	}
+}
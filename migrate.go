@@ -0,0 +1,115 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/knadh/koanf/v2"
+)
+
+var (
+	UnsupportedVersionError = errors.New("unsupported configuration version")
+)
+
+// VersionKey is the top level key of a config file holding the version of
+// its format, as in version: 2.
+const VersionKey = "version"
+
+// Migration rewrites the values of a config file from one version of its
+// format to the next. values is nested, as in the file, and may be modified.
+type Migration func(values map[string]interface{}) (map[string]interface{}, error)
+
+// WithMigration registers m to migrate config files from version from to
+// version from+1, so that old config files keep working as the configuration
+// struct changes. Before a config file is used, the migrations from the
+// version in its VersionKey, or 1 if it has none, are applied in order, and
+// VersionKey is removed. The current version is one more than the highest
+// registered from, and Load fails with UnsupportedVersionError for files with
+// a higher version or a version with no migration.
+//
+//	c, err := goconfig.New("APP_", ".", goconfig.WithMigration(1, func(v map[string]interface{}) (map[string]interface{}, error) {
+//		v["db"] = map[string]interface{}{"host": v["dbhost"]}
+//		delete(v, "dbhost")
+//		return v, nil
+//	}))
+func WithMigration(from int, m Migration) Option {
+	return func(c *Config) {
+		if c.migrations == nil {
+			c.migrations = make(map[int]Migration)
+		}
+		c.migrations[from] = m
+	}
+}
+
+// currentVersion returns the version of the config file format that needs no
+// migration.
+func (c Config) currentVersion() int {
+	current := 1
+	for from := range c.migrations {
+		if from+1 > current {
+			current = from + 1
+		}
+	}
+	return current
+}
+
+// migrate returns the values k of a config file migrated to the current
+// version, without VersionKey.
+func (c Config) migrate(k *koanf.Koanf) (*koanf.Koanf, error) {
+	version := 1
+	if v := k.Get(VersionKey); v != nil {
+		n, err := strconv.Atoi(fmt.Sprint(v))
+		if err != nil {
+			return nil, fmt.Errorf("%s %v: %w", VersionKey, v, UnsupportedVersionError)
+		}
+		version = n
+	}
+	current := c.currentVersion()
+	if version > current {
+		return nil, fmt.Errorf("%s %d is newer than %d: %w", VersionKey, version, current, UnsupportedVersionError)
+	}
+	k.Delete(VersionKey)
+	if version == current {
+		return k, nil
+	}
+
+	values := k.Raw()
+	for ; version < current; version++ {
+		m, ok := c.migrations[version]
+		if !ok {
+			return nil, fmt.Errorf("%s %d has no migration: %w", VersionKey, version, UnsupportedVersionError)
+		}
+		var err error
+		if values, err = m(values); err != nil {
+			return nil, fmt.Errorf("migrate %s %d: %w", VersionKey, version, err)
+		}
+	}
+	migrated := koanf.New(k.Delim())
+	if err := migrated.Load(mapProvider{m: values, delim: k.Delim()}, nil); err != nil {
+		return nil, err
+	}
+	return migrated, nil
+}
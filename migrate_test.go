@@ -0,0 +1,120 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/pflag"
+)
+
+var errTestMigration = errors.New("migration failed")
+
+func TestMigration(t *testing.T) {
+	// Version 1 had dbhost, version 2 moved it to db.host and version 3
+	// renamed title to name.
+	opts := []Option{
+		WithStrict(),
+		WithMigration(1, func(v map[string]interface{}) (map[string]interface{}, error) {
+			if _, ok := v["fail"]; ok {
+				return nil, errTestMigration
+			}
+			v["db"] = map[string]interface{}{"host": v["dbhost"]}
+			delete(v, "dbhost")
+			return v, nil
+		}),
+		WithMigration(2, func(v map[string]interface{}) (map[string]interface{}, error) {
+			v["name"] = v["title"]
+			delete(v, "title")
+			return v, nil
+		}),
+	}
+	cases := []struct {
+		name    string
+		file    string
+		wantErr error
+	}{
+		{
+			name: "unversioned",
+			file: "dbhost: h\ntitle: app\n",
+		},
+		{
+			name: "version 2",
+			file: "version: 2\ndb:\n  host: h\ntitle: app\n",
+		},
+		{
+			name: "current",
+			file: "version: 3\ndb:\n  host: h\nname: app\n",
+		},
+		{
+			name:    "newer",
+			file:    "version: 4\nname: app\n",
+			wantErr: UnsupportedVersionError,
+		},
+		{
+			name:    "no migration",
+			file:    "version: 0\nname: app\n",
+			wantErr: UnsupportedVersionError,
+		},
+		{
+			name:    "bad version",
+			file:    "version: two\nname: app\n",
+			wantErr: UnsupportedVersionError,
+		},
+		{
+			name:    "migration fails",
+			file:    "fail: true\n",
+			wantErr: errTestMigration,
+		},
+	}
+	var want testMountConfig
+	want.Name = "app"
+	want.DB.Host = "h"
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			name := filepath.Join(t.TempDir(), "config.yaml")
+			writeTestFile(t, name, tc.file)
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			AddConfigFlag(f)
+			if err := f.Parse([]string{"--" + FileArgName + "=" + name}); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+			c, err := New(testPrefix, testDelimiter, opts...)
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+
+			var got testMountConfig
+			err = c.Load(f, &got)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Load err: got=%v want=%v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(want, got); err == nil && diff != "" {
+				t.Errorf("Load mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
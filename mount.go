@@ -0,0 +1,79 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"strings"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// optionalFilePrefix marks a config file argument as optional.
+const optionalFilePrefix = "?"
+
+// fileArg is a config file named on the command line.
+type fileArg struct {
+	name string
+	// mount, if not empty, is the key the contents of the file are loaded
+	// under.
+	mount string
+	// optional is set for files that are skipped if they do not exist.
+	optional bool
+	// base, if not empty, is the config file this file overrides, as a
+	// profile file overrides the file it belongs to.
+	base string
+}
+
+// parseFileArg splits a config file argument of the form name:mount, such as
+// db.yaml:db, which loads the keys of db.yaml under db. The colons in URLs and
+// Windows drive letters do not introduce a mount because a mount cannot
+// contain a slash or backslash. A leading question mark, as in ?local.yaml,
+// marks the file optional.
+func parseFileArg(s string) fileArg {
+	optional := strings.HasPrefix(s, optionalFilePrefix)
+	s = strings.TrimPrefix(s, optionalFilePrefix)
+	i := strings.LastIndex(s, ":")
+	if i <= 0 || i == len(s)-1 || strings.ContainsAny(s[i+1:], `/\`) {
+		return fileArg{name: s, optional: optional}
+	}
+	return fileArg{name: s[:i], mount: s[i+1:], optional: optional}
+}
+
+// fileNames returns the names of the config files in args.
+func fileNames(args []fileArg) []string {
+	names := make([]string, len(args))
+	for i, a := range args {
+		names[i] = a.name
+	}
+	return names
+}
+
+// mount returns a copy of k with every key moved under prefix.
+func mount(k *koanf.Koanf, prefix string) (*koanf.Koanf, error) {
+	mounted := koanf.New(k.Delim())
+	p := mapProvider{m: map[string]interface{}{prefix: k.Raw()}, delim: k.Delim()}
+	if err := mounted.Load(p, nil); err != nil {
+		return nil, err
+	}
+	return mounted, nil
+}
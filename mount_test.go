@@ -0,0 +1,146 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/pflag"
+)
+
+func TestParseFileArg(t *testing.T) {
+	cases := []struct {
+		arg  string
+		want fileArg
+	}{
+		{"config.yaml", fileArg{name: "config.yaml"}},
+		{"db.yaml:db", fileArg{name: "db.yaml", mount: "db"}},
+		{"db.yaml:db.primary", fileArg{name: "db.yaml", mount: "db.primary"}},
+		{"https://example.com/c.yaml", fileArg{name: "https://example.com/c.yaml"}},
+		{"http://example.com:8080/c.yaml", fileArg{name: "http://example.com:8080/c.yaml"}},
+		{"https://example.com/c.yaml:app", fileArg{name: "https://example.com/c.yaml", mount: "app"}},
+		{`C:\config.yaml`, fileArg{name: `C:\config.yaml`}},
+		{"config.yaml:", fileArg{name: "config.yaml:"}},
+		{"?local.yaml", fileArg{name: "local.yaml", optional: true}},
+		{"?db.yaml:db", fileArg{name: "db.yaml", mount: "db", optional: true}},
+	}
+	for _, tc := range cases {
+		if got := parseFileArg(tc.arg); got != tc.want {
+			t.Errorf("parseFileArg(%q): got=%+v want=%+v", tc.arg, got, tc.want)
+		}
+	}
+}
+
+type testMountConfig struct {
+	Name string `koanf:"name"`
+	DB   struct {
+		Host string `koanf:"host"`
+		Pool struct {
+			Size int `koanf:"size"`
+		} `koanf:"pool"`
+	} `koanf:"db"`
+}
+
+func TestLoadMountedFile(t *testing.T) {
+	dir := t.TempDir()
+	main := filepath.Join(dir, "main.yaml")
+	writeTestFile(t, main, "name: app\n")
+	db := filepath.Join(dir, "db.yaml")
+	writeTestFile(t, db, "host: localhost\npool:\n  size: 4\n")
+
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	f.StringSlice(FileArgName, nil, testNoHelpMessage)
+	args := []string{
+		fmt.Sprintf("--%s=%s", FileArgName, main),
+		fmt.Sprintf("--%s=%s:db", FileArgName, db),
+	}
+	if err := f.Parse(args); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	var got testMountConfig
+	if err := c.Load(f, &got); err != nil {
+		t.Fatalf("Load err: got=%v want=nil", err)
+	}
+	var want testMountConfig
+	want.Name = "app"
+	want.DB.Host = "localhost"
+	want.DB.Pool.Size = 4
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Load mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLoadOptionalFile(t *testing.T) {
+	dir := t.TempDir()
+	main := filepath.Join(dir, "main.yaml")
+	writeTestFile(t, main, "name: app\n")
+	missing := filepath.Join(dir, "missing.yaml")
+
+	cases := []struct {
+		name      string
+		arg       string
+		wantErr   error
+		wantState SourceState
+	}{
+		{name: "optional", arg: "?" + missing, wantState: SourceMissing},
+		{name: "required", arg: missing, wantErr: fs.ErrNotExist, wantState: SourceFailed},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			AddConfigFlag(f)
+			args := []string{"--" + FileArgName + "=" + main, "--" + FileArgName + "=" + tc.arg}
+			if err := f.Parse(args); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+			c, err := New(testPrefix, testDelimiter)
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+
+			var got testMountConfig
+			r, err := c.LoadWithResult(f, &got)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Load err: got=%v want=%v", err, tc.wantErr)
+			}
+			if got, want := got.Name, "app"; err == nil && got != want {
+				t.Errorf("Name: got=%q want=%q", got, want)
+			}
+			for _, st := range r.SourceStatus() {
+				if st.Name == missing && st.State != tc.wantState {
+					t.Errorf("%s state: got=%q want=%q", missing, st.State, tc.wantState)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	NotOneOfError = errors.New("value is not one of the allowed values")
+)
+
+// oneofTagName is the struct tag listing the allowed values of a field, as in
+// `oneof:"debug,info,warn,error"`.
+const oneofTagName = "oneof"
+
+// checkOneOf returns an error naming the key, value and allowed values of the
+// first field of cfg whose value is not allowed by its oneof tag. Every element
+// of a slice field must be allowed.
+func (c Config) checkOneOf(cfg interface{}) error {
+	v, err := structValue(cfg)
+	if err != nil {
+		return err
+	}
+	return walkFields(v, "", c.delimiter, func(key string, sf reflect.StructField, v reflect.Value) error {
+		tag, ok := sf.Tag.Lookup(oneofTagName)
+		if !ok {
+			return nil
+		}
+		allowed := strings.Split(tag, ",")
+		values := []reflect.Value{v}
+		if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+			values = values[:0]
+			for i := 0; i < v.Len(); i++ {
+				values = append(values, v.Index(i))
+			}
+		}
+		for _, value := range values {
+			s := fmt.Sprint(value.Interface())
+			if !contains(allowed, s) {
+				if isSecret(sf) {
+					s = RedactedValue
+				}
+				list := strings.Join(allowed, ", ")
+				return newMessageError(MsgNotOneOf, map[string]string{"key": key, "value": s, "allowed": list},
+					fmt.Errorf("Load %s: %q not in [%s]: %w", key, s, list, NotOneOfError))
+			}
+		}
+		return nil
+	})
+}
+
+// contains reports whether ss contains s.
+func contains(ss []string, s string) bool {
+	for _, e := range ss {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
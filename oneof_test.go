@@ -0,0 +1,89 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+type testOneOfConfig struct {
+	Level string   `koanf:"level" oneof:"debug,info,warn,error"`
+	Modes []string `koanf:"modes" oneof:"a,b"`
+}
+
+func TestLoadOneOf(t *testing.T) {
+	cases := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{
+			name: "allowed",
+			args: []string{"--level=warn", "--modes=a,b"},
+		},
+		{
+			name:    "not allowed",
+			args:    []string{"--level=trace"},
+			wantErr: `level: "trace" not in [debug, info, warn, error]`,
+		},
+		{
+			name:    "slice element not allowed",
+			args:    []string{"--level=info", "--modes=a,c"},
+			wantErr: `modes: "c" not in [a, b]`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			f.String("level", "info", testNoHelpMessage)
+			f.StringSlice("modes", nil, testNoHelpMessage)
+			if err := f.Parse(tc.args); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+
+			c, err := New(testPrefix, testDelimiter)
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+
+			var cfg testOneOfConfig
+			err = c.Load(f, &cfg)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Errorf("Load err: got=%v want=nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, NotOneOfError) {
+				t.Fatalf("Load err: got=%v want=%v", err, NotOneOfError)
+			}
+			if !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("Load err: got=%q want=%q", err, tc.wantErr)
+			}
+		})
+	}
+}
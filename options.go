@@ -0,0 +1,57 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+// Option configures optional behavior of a Config. Options are passed to New.
+type Option func(*Config)
+
+// WithEnvPrefix sets the prefix of environment variables, replacing the one
+// passed to New.
+func WithEnvPrefix(prefix string) Option {
+	return func(c *Config) {
+		c.prefix = prefix
+	}
+}
+
+// WithDelimiter sets the delimiter of nested keys, replacing the one passed to
+// New. It must be a single character.
+func WithDelimiter(delimiter string) Option {
+	return func(c *Config) {
+		c.delimiter = delimiter
+	}
+}
+
+// WithMustLoadHandler sets the function MustLoad calls with the error if
+// loading fails, for example to print it and exit:
+//
+//	goconfig.WithMustLoadHandler(func(err error) {
+//		fmt.Fprintln(os.Stderr, err)
+//		os.Exit(2)
+//	})
+//
+// Load never calls it.
+func WithMustLoadHandler(fn func(err error)) Option {
+	return func(c *Config) {
+		c.mustLoadHandler = fn
+	}
+}
@@ -0,0 +1,210 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	SchemeNotAllowedError = errors.New("config source scheme not allowed")
+	TooLargeError         = errors.New("config source too large")
+)
+
+// FileScheme is the scheme used by SourcePolicy.AllowedSchemes for local
+// config files.
+const FileScheme = "file"
+
+// SourcePolicy limits how remote config files, such as http(s) and object
+// storage URLs, and sources added with WithSource, such as ExecProvider and
+// RedisProvider, are fetched, both by Load and when Watch polls them. Non-zero
+// values take precedence over source specific settings such as HTTPOptions.
+type SourcePolicy struct {
+	// Timeout limits each attempt to fetch a config file or a source. It
+	// applies to sources that are ContextProviders.
+	Timeout time.Duration
+	// MaxSize limits the size of a fetched config file in bytes, and of a
+	// source that returns bytes for its Parser.
+	MaxSize int64
+	// AllowedSchemes, if not nil, lists the schemes that may be used in
+	// config file names. Use FileScheme to allow local files. Sources are
+	// chosen by the program rather than named by its input, so they are
+	// not restricted.
+	AllowedSchemes []string
+	// Retries is the number of additional attempts made after a failure,
+	// and Backoff the delay before the first retry. The delay doubles after
+	// each retry.
+	Retries int
+	Backoff time.Duration
+}
+
+// WithSourcePolicy sets the policy applied to remote config files and
+// sources.
+func WithSourcePolicy(p SourcePolicy) Option {
+	return func(c *Config) {
+		c.policy = p
+	}
+}
+
+// checkScheme returns an error if scheme is not allowed by p.
+func (p SourcePolicy) checkScheme(scheme string) error {
+	if p.AllowedSchemes == nil {
+		return nil
+	}
+	for _, s := range p.AllowedSchemes {
+		if strings.EqualFold(s, scheme) {
+			return nil
+		}
+	}
+	return fmt.Errorf("scheme %q: %w", scheme, SchemeNotAllowedError)
+}
+
+// httpOptions returns the options used for http(s) sources with the policy
+// applied.
+func (c Config) httpOptions() HTTPOptions {
+	o := c.http
+	if c.policy.Timeout > 0 {
+		o.Timeout = c.policy.Timeout
+	}
+	if c.policy.Retries > 0 {
+		o.Retries = c.policy.Retries
+	}
+	if c.policy.Backoff > 0 {
+		o.Backoff = c.policy.Backoff
+	}
+	o.maxSize = c.policy.MaxSize
+	return o
+}
+
+// checkSize returns an error if b is longer than maxSize. A maxSize of zero
+// means there is no limit.
+func checkSize(b []byte, maxSize int64) error {
+	if maxSize > 0 && int64(len(b)) > maxSize {
+		return fmt.Errorf("more than %d bytes: %w", maxSize, TooLargeError)
+	}
+	return nil
+}
+
+// retryableError marks an error from a fetch attempt that may succeed if
+// tried again.
+type retryableError struct {
+	err error
+}
+
+func (e retryableError) Error() string { return e.err.Error() }
+func (e retryableError) Unwrap() error { return e.err }
+
+// withRetries calls fetch until it succeeds, returns an error that is not a
+// retryableError, or retries additional attempts have been made. The delay
+// between attempts starts at backoff and doubles after each retry.
+func withRetries[T any](ctx context.Context, retries int, backoff time.Duration, fetch func(context.Context) (T, error)) (T, error) {
+	var zero T
+	var err error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return zero, ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+		var v T
+		v, err = fetch(ctx)
+		if err == nil {
+			return v, nil
+		}
+		var re retryableError
+		if !errors.As(err, &re) {
+			break
+		}
+	}
+	return zero, err
+}
+
+// attemptContext returns the context for one attempt to fetch a source, which
+// is limited by the policy timeout.
+func (p SourcePolicy) attemptContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if p.Timeout > 0 {
+		return context.WithTimeout(ctx, p.Timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// fetchObject retrieves key from bucket in s, applying the policy. Every
+// error from the store is retried.
+func (c Config) fetchObject(ctx context.Context, s ObjectStore, bucket, key string) ([]byte, error) {
+	return withRetries(ctx, c.policy.Retries, c.policy.Backoff, func(ctx context.Context) ([]byte, error) {
+		ctx, cancel := c.policy.attemptContext(ctx)
+		defer cancel()
+		b, err := s.GetObject(ctx, bucket, key)
+		if err != nil {
+			return nil, retryableError{err}
+		}
+		if err := checkSize(b, c.policy.MaxSize); err != nil {
+			return nil, err
+		}
+		return b, nil
+	})
+}
+
+// policyProvider is a koanf.Provider that reads a Source with ctx, applying
+// the policy. Every error from the source is retried.
+type policyProvider struct {
+	s      Source
+	ctx    context.Context
+	policy SourcePolicy
+}
+
+// ReadBytes reads the source, limited to the policy MaxSize.
+func (p policyProvider) ReadBytes() ([]byte, error) {
+	return withRetries(p.ctx, p.policy.Retries, p.policy.Backoff, func(ctx context.Context) ([]byte, error) {
+		ctx, cancel := p.policy.attemptContext(ctx)
+		defer cancel()
+		b, err := sourceProvider(ctx, p.s).ReadBytes()
+		if err != nil {
+			return nil, retryableError{err}
+		}
+		if err := checkSize(b, p.policy.MaxSize); err != nil {
+			return nil, err
+		}
+		return b, nil
+	})
+}
+
+// Read reads the source.
+func (p policyProvider) Read() (map[string]interface{}, error) {
+	return withRetries(p.ctx, p.policy.Retries, p.policy.Backoff, func(ctx context.Context) (map[string]interface{}, error) {
+		ctx, cancel := p.policy.attemptContext(ctx)
+		defer cancel()
+		m, err := sourceProvider(ctx, p.s).Read()
+		if err != nil {
+			return nil, retryableError{err}
+		}
+		return m, nil
+	})
+}
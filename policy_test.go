@@ -0,0 +1,215 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/knadh/koanf/parsers/json"
+	"github.com/spf13/pflag"
+)
+
+func TestSourcePolicy(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"%s": %d}`, testKey1, testValue1)
+	}))
+	defer srv.Close()
+
+	var storeCalls int
+	store := ObjectStoreFunc(func(_ context.Context, bucket, key string) ([]byte, error) {
+		storeCalls++
+		if storeCalls < 3 {
+			return nil, errors.New("unavailable")
+		}
+		return []byte(fmt.Sprintf(`{"%s": %d}`, testKey1, testValue1)), nil
+	})
+
+	cases := []struct {
+		name          string
+		file          string
+		policy        SourcePolicy
+		wantErr       error
+		wantStoreCall int
+	}{
+		{
+			name:   "file allowed",
+			file:   testFileName(testGoodJSONConfig),
+			policy: SourcePolicy{AllowedSchemes: []string{FileScheme}},
+		},
+		{
+			name:    "file not allowed",
+			file:    testFileName(testGoodJSONConfig),
+			policy:  SourcePolicy{AllowedSchemes: []string{"https"}},
+			wantErr: SchemeNotAllowedError,
+		},
+		{
+			name:    "http not allowed",
+			file:    srv.URL + "/config.json",
+			policy:  SourcePolicy{AllowedSchemes: []string{"https", FileScheme}},
+			wantErr: SchemeNotAllowedError,
+		},
+		{
+			name:    "http too large",
+			file:    srv.URL + "/config.json",
+			policy:  SourcePolicy{MaxSize: 4},
+			wantErr: TooLargeError,
+		},
+		{
+			name:          "object store retried",
+			file:          "s3://bucket/config.json",
+			policy:        SourcePolicy{Retries: 2},
+			wantStoreCall: 3,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			storeCalls = 0
+
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			f.StringSlice(FileArgName, nil, testNoHelpMessage)
+			if err := f.Parse([]string{fmt.Sprintf("--%s=%s", FileArgName, tc.file)}); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+
+			c, err := New(testPrefix, testDelimiter, WithSourcePolicy(tc.policy), WithObjectStore("s3", store))
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+
+			var cfg testConfig
+			err = c.Load(f, &cfg)
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Errorf("Load err: got=%v want=%v", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load err: got=%v want=nil", err)
+			}
+			if got, want := cfg.Value1, testValue1; got != want {
+				t.Errorf("Value1: got=%d want=%d", got, want)
+			}
+			if strings.HasPrefix(tc.file, "s3://") {
+				if got, want := storeCalls, tc.wantStoreCall; got != want {
+					t.Errorf("store calls: got=%d want=%d", got, want)
+				}
+			}
+		})
+	}
+}
+
+// testPolicySource is a source whose first failures reads fail. If block is
+// set, reads wait until their context is done.
+type testPolicySource struct {
+	failures int
+	block    bool
+	calls    int
+}
+
+func (p *testPolicySource) ReadBytes() ([]byte, error) {
+	return p.ReadBytesContext(context.Background())
+}
+
+func (p *testPolicySource) Read() (map[string]interface{}, error) {
+	return nil, errors.New("testPolicySource does not support Read")
+}
+
+func (p *testPolicySource) ReadBytesContext(ctx context.Context) ([]byte, error) {
+	p.calls++
+	if p.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if p.calls <= p.failures {
+		return nil, errors.New("unavailable")
+	}
+	return []byte(fmt.Sprintf(`{"%s": %d}`, testKey1, testValue1)), nil
+}
+
+func (p *testPolicySource) ReadContext(context.Context) (map[string]interface{}, error) {
+	return p.Read()
+}
+
+func TestSourcePolicyForSources(t *testing.T) {
+	cases := []struct {
+		name      string
+		source    *testPolicySource
+		policy    SourcePolicy
+		wantErr   error
+		wantCalls int
+	}{
+		{
+			name:      "retried",
+			source:    &testPolicySource{failures: 2},
+			policy:    SourcePolicy{Retries: 2, Backoff: time.Millisecond},
+			wantCalls: 3,
+		},
+		{
+			name:      "too large",
+			source:    &testPolicySource{},
+			policy:    SourcePolicy{MaxSize: 4},
+			wantErr:   TooLargeError,
+			wantCalls: 1,
+		},
+		{
+			name:      "timeout",
+			source:    &testPolicySource{block: true},
+			policy:    SourcePolicy{Timeout: 10 * time.Millisecond},
+			wantErr:   context.DeadlineExceeded,
+			wantCalls: 1,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			if err := f.Parse(nil); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+			source := Source{Name: "test", Provider: tc.source, Parser: json.Parser()}
+			c, err := New(testPrefix, testDelimiter, WithSource(source), WithSourcePolicy(tc.policy))
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+
+			var cfg testConfig
+			err = c.Load(f, &cfg)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Load err: got=%v want=%v", err, tc.wantErr)
+			}
+			if err == nil && cfg.Value1 != testValue1 {
+				t.Errorf("Value1: got=%d want=%d", cfg.Value1, testValue1)
+			}
+			if got, want := tc.source.calls, tc.wantCalls; got != want {
+				t.Errorf("source calls: got=%d want=%d", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// WithPollInterval makes Watch poll remote config files, such as http(s) and
+// object storage URLs, and sources that cannot report their own changes, every
+// interval. The configuration is only reloaded when the content of one of them
+// actually changed. http(s) URLs are polled with conditional requests using
+// the ETag returned by the server, and all other content is compared by hash.
+func WithPollInterval(interval time.Duration) Option {
+	return func(c *Config) {
+		c.pollInterval = interval
+	}
+}
+
+// pollTarget is a remote source of configuration that is polled for changes.
+type pollTarget struct {
+	name string
+	// url is set for http(s) config files, which are polled using ETags.
+	url      string
+	provider koanf.Provider
+	parsed   bool
+
+	etag string
+	hash [sha256.Size]byte
+}
+
+// pollTargets returns the targets polling files and sources, skipping local
+// files, which are watched.
+func (c Config) pollTargets(ctx context.Context, files []string, sources []Source) []*pollTarget {
+	var targets []*pollTarget
+	for _, name := range files {
+		if localFile(name) && c.fsys == nil || isStdin(name) {
+			continue
+		}
+		t := &pollTarget{name: name}
+		if strings.HasPrefix(name, "http://") || strings.HasPrefix(name, "https://") {
+			t.url = name
+		} else {
+			p, err := c.provider(ctx, name)
+			if err != nil {
+				continue
+			}
+			t.provider = p
+		}
+		targets = append(targets, t)
+	}
+	for _, s := range sources {
+		// Sources are read as Load reads them, with the SourcePolicy.
+		p := policyProvider{s: s, ctx: ctx, policy: c.policy}
+		targets = append(targets, &pollTarget{name: s.Name, provider: p, parsed: s.Parser == nil})
+	}
+	return targets
+}
+
+// poll fetches t and reports whether its content changed since the last poll.
+// The first poll only records the content.
+func (c Config) poll(ctx context.Context, t *pollTarget) (bool, error) {
+	var b []byte
+	var err error
+	switch {
+	case t.url != "":
+		var etag string
+		b, etag, err = c.httpOptions().fetchOnce(ctx, t.url, t.etag)
+		if errors.Is(err, errNotModified) {
+			return false, nil
+		}
+		t.etag = etag
+	case t.parsed:
+		var m map[string]interface{}
+		if m, err = t.provider.Read(); err == nil {
+			b, err = json.Marshal(m)
+		}
+	default:
+		b, err = t.provider.ReadBytes()
+	}
+	if err != nil {
+		return false, err
+	}
+
+	hash := sha256.Sum256(b)
+	first := t.hash == [sha256.Size]byte{}
+	changed := !first && hash != t.hash
+	t.hash = hash
+	return changed, nil
+}
+
+// pollLoop polls targets every interval until ctx is done, calling
+// notify when any of them changed.
+func (c Config) pollLoop(ctx context.Context, interval time.Duration, targets []*pollTarget, notify func()) {
+	for _, t := range targets {
+		if _, err := c.poll(ctx, t); err != nil {
+			c.logf("poll %s: %v", t.name, err)
+		}
+	}
+
+	ticker := c.clock().NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			for _, t := range targets {
+				changed, err := c.poll(ctx, t)
+				if err != nil {
+					c.logf("poll %s: %v", t.name, err)
+					continue
+				}
+				if changed {
+					notify()
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/knadh/koanf/parsers/json"
+	"github.com/spf13/pflag"
+)
+
+func TestWatchPollsHTTP(t *testing.T) {
+	var mu sync.Mutex
+	version := 1
+	notModified := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		etag := fmt.Sprintf(`"%d"`, version)
+		if r.Header.Get("If-None-Match") == etag {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		fmt.Fprintf(w, `{"%s": %d}`, testKey1, testValue1+version-1)
+	}))
+	defer srv.Close()
+
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	f.StringSlice(FileArgName, nil, testNoHelpMessage)
+	if err := f.Parse([]string{fmt.Sprintf("--%s=%s/config.json", FileArgName, srv.URL)}); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+
+	c, err := New(testPrefix, testDelimiter, WithPollInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	changes := make(chan testConfig, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var cfg testConfig
+	err = Watch(ctx, c, f, &cfg, func(_, new testConfig) error {
+		changes <- new
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Watch err: got=%v want=nil", err)
+	}
+
+	// Unchanged content must not trigger a reload.
+	select {
+	case got := <-changes:
+		t.Fatalf("unexpected reload: %+v", got)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	mu.Lock()
+	if notModified == 0 {
+		t.Errorf("conditional requests: got=0 want>0")
+	}
+	version = 2
+	mu.Unlock()
+
+	select {
+	case got := <-changes:
+		if got, want := got.Value1, testValue2; got != want {
+			t.Errorf("Value1: got=%d want=%d", got, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for reload")
+	}
+}
+
+func TestPollSourcePolicy(t *testing.T) {
+	cases := []struct {
+		name      string
+		source    *testPolicySource
+		policy    SourcePolicy
+		wantErr   error
+		wantCalls int
+	}{
+		{
+			name:      "retried",
+			source:    &testPolicySource{failures: 2},
+			policy:    SourcePolicy{Retries: 2, Backoff: time.Millisecond},
+			wantCalls: 3,
+		},
+		{
+			name:      "timeout",
+			source:    &testPolicySource{block: true},
+			policy:    SourcePolicy{Timeout: 10 * time.Millisecond},
+			wantErr:   context.DeadlineExceeded,
+			wantCalls: 1,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := New(testPrefix, testDelimiter, WithSourcePolicy(tc.policy))
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+			source := Source{Name: "test", Provider: tc.source, Parser: json.Parser()}
+			targets := c.pollTargets(context.Background(), nil, []Source{source})
+			if got, want := len(targets), 1; got != want {
+				t.Fatalf("targets: got=%d want=%d", got, want)
+			}
+			if _, err := c.poll(context.Background(), targets[0]); !errors.Is(err, tc.wantErr) {
+				t.Errorf("poll err: got=%v want=%v", err, tc.wantErr)
+			}
+			if got, want := tc.source.calls, tc.wantCalls; got != want {
+				t.Errorf("source calls: got=%d want=%d", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	BadPrecedenceError = errors.New("precedence must list files, sources, env and flags exactly once")
+)
+
+// Groups of layers ordered by WithPrecedence, in addition to SourceEnv and
+// SourceFlags.
+const (
+	// PrecedenceFiles is every config file, in the order given.
+	PrecedenceFiles = "files"
+	// PrecedenceSources is every Source added with WithSource, in the order
+	// added.
+	PrecedenceSources = "sources"
+)
+
+// DefaultPrecedence is the order, from lowest to highest precedence, in which
+// Load merges configuration unless WithPrecedence is used.
+var DefaultPrecedence = []string{PrecedenceFiles, PrecedenceSources, SourceEnv, SourceFlags}
+
+// WithPrecedence sets the order, from lowest to highest precedence, in which
+// config files, sources, the environment and flags are merged. order must list
+// PrecedenceFiles, PrecedenceSources, SourceEnv and SourceFlags exactly once,
+// otherwise New fails with BadPrecedenceError. For example, to make the
+// environment the final authority:
+//
+//	goconfig.WithPrecedence(goconfig.PrecedenceFiles, goconfig.PrecedenceSources, goconfig.SourceFlags, goconfig.SourceEnv)
+//
+// Defaults always have the lowest precedence, and the compiled overlay and
+// metadata the highest.
+func WithPrecedence(order ...string) Option {
+	return func(c *Config) {
+		c.precedence = order
+	}
+}
+
+// checkPrecedence returns an error if order, when not nil, is not a
+// permutation of DefaultPrecedence.
+func checkPrecedence(order []string) error {
+	if order == nil {
+		return nil
+	}
+	got := append([]string(nil), order...)
+	want := append([]string(nil), DefaultPrecedence...)
+	sort.Strings(got)
+	sort.Strings(want)
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		return fmt.Errorf("precedence %v: %w", order, BadPrecedenceError)
+	}
+	return nil
+}
+
+// precedenceOrder returns the order in which layers are merged.
+func (c Config) precedenceOrder() []string {
+	if c.precedence == nil {
+		return DefaultPrecedence
+	}
+	return c.precedence
+}
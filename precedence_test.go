@@ -0,0 +1,93 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestWithPrecedence(t *testing.T) {
+	cases := []struct {
+		name    string
+		order   []string
+		want    int
+		wantErr error
+	}{
+		{
+			name: "default flags win",
+			want: testValue2,
+		},
+		{
+			name:  "env wins",
+			order: []string{PrecedenceFiles, PrecedenceSources, SourceFlags, SourceEnv},
+			want:  testValue1,
+		},
+		{
+			name:    "missing group",
+			order:   []string{PrecedenceFiles, SourceFlags, SourceEnv},
+			wantErr: BadPrecedenceError,
+		},
+		{
+			name:    "unknown group",
+			order:   []string{PrecedenceFiles, PrecedenceSources, SourceFlags, "other"},
+			wantErr: BadPrecedenceError,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(strings.ToUpper(testPrefix+testKey1), strconv.Itoa(testValue1))
+
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			f.Int(testKey1, testDefaultValue1, testNoHelpMessage)
+			if err := f.Parse([]string{fmt.Sprintf("--%s=%d", testKey1, testValue2)}); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+
+			var opts []Option
+			if tc.order != nil {
+				opts = append(opts, WithPrecedence(tc.order...))
+			}
+			c, err := New(testPrefix, testDelimiter, opts...)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("New err: got=%v want=%v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			var cfg testConfig
+			if err := c.Load(f, &cfg); err != nil {
+				t.Fatalf("Load err: got=%v want=nil", err)
+			}
+			if got := cfg.Value1; got != tc.want {
+				t.Errorf("Value1: got=%d want=%d", got, tc.want)
+			}
+		})
+	}
+}
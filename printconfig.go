@@ -0,0 +1,93 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/pflag"
+)
+
+var (
+	PrintedConfigError = errors.New("configuration printed")
+)
+
+// PrintConfigArgName is the name of the flag that prints the configuration.
+const PrintConfigArgName = "print-config"
+
+// WithPrintConfig makes Load write the effective configuration to w, or
+// standard output if w is nil, when the flag PrintConfigArgName is set, and
+// then fail with PrintedConfigError so that the program can exit:
+//
+//	if err := c.Load(f, &cfg); errors.Is(err, goconfig.PrintedConfigError) {
+//		os.Exit(0)
+//	}
+//
+// The configuration is written by Dump, so secrets are redacted, in the format
+// given with the flag, YAML by default.
+func WithPrintConfig(w io.Writer) Option {
+	return func(c *Config) {
+		if w == nil {
+			w = os.Stdout
+		}
+		c.printConfig = w
+	}
+}
+
+// AddPrintConfigFlag defines the flag named PrintConfigArgName in f. It can be
+// given without a value, as --print-config, or with a format, as
+// --print-config=json.
+func AddPrintConfigFlag(f *pflag.FlagSet) {
+	f.String(PrintConfigArgName, "", "print the effective configuration, as yaml or json, and exit")
+	f.Lookup(PrintConfigArgName).NoOptDefVal = FormatYAML
+}
+
+// printConfigKey reports whether key holds the format of PrintConfigArgName
+// rather than a configuration value.
+func (c Config) printConfigKey(key string) bool {
+	return c.printConfig != nil && key == PrintConfigArgName
+}
+
+// printConfigIfRequested writes cfg, which was loaded successfully, if
+// WithPrintConfig was used and PrintConfigArgName is set in f, and then
+// returns PrintedConfigError.
+func (c Config) printConfigIfRequested(f *pflag.FlagSet, cfg interface{}) error {
+	if c.printConfig == nil || !f.Changed(PrintConfigArgName) {
+		return nil
+	}
+	format, err := f.GetString(PrintConfigArgName)
+	if err != nil {
+		return &FlagError{Err: fmt.Errorf("get %s flag: %v", PrintConfigArgName, err)}
+	}
+	b, err := c.Dump(cfg, format)
+	if err != nil {
+		return err
+	}
+	if _, err := c.printConfig.Write(b); err != nil {
+		return fmt.Errorf("print config: %w", err)
+	}
+	return PrintedConfigError
+}
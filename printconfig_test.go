@@ -0,0 +1,85 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/pflag"
+)
+
+func TestWithPrintConfig(t *testing.T) {
+	cases := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "not set",
+		},
+		{
+			name:    "yaml",
+			args:    []string{"--" + PrintConfigArgName},
+			want:    fmt.Sprintf("nested:\n  nestedvalue: 0\nvalue1: %d\nvalue2: 0\nvalue3: 0\n", testValue1),
+			wantErr: PrintedConfigError,
+		},
+		{
+			name:    "json",
+			args:    []string{"--" + PrintConfigArgName + "=json"},
+			want:    fmt.Sprintf("{\n  \"nested\": {\n    \"nestedvalue\": 0\n  },\n  \"value1\": %d,\n  \"value2\": 0,\n  \"value3\": 0\n}\n", testValue1),
+			wantErr: PrintedConfigError,
+		},
+		{
+			name:    "bad format",
+			args:    []string{"--" + PrintConfigArgName + "=xml"},
+			wantErr: UnknownFormatError,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			f.Int(testKey1, 0, testNoHelpMessage)
+			AddPrintConfigFlag(f)
+			if err := f.Parse(append([]string{fmt.Sprintf("--%s=%d", testKey1, testValue1)}, tc.args...)); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+			var buf bytes.Buffer
+			c, err := New(testPrefix, testDelimiter, WithPrintConfig(&buf), WithStrict())
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+			var got testConfig
+			if err := c.Load(f, &got); !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Load err: got=%v want=%v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, buf.String()); diff != "" {
+				t.Errorf("printed config mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
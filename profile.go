@@ -0,0 +1,118 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// ProfileArgName is the name of the flag that selects profiles.
+const ProfileArgName = "profile"
+
+// WithProfiles makes Load read the active profiles from the flag
+// ProfileArgName or, if it is not set, the environment variable named by the
+// prefix followed by PROFILE, such as APP_PROFILE. Several profiles may be
+// separated by commas. For each local config file, such as config.yaml, Load
+// also loads the optional file config.<profile>.yaml immediately after it, so
+// that it overrides the base file.
+func WithProfiles() Option {
+	return func(c *Config) {
+		c.profiles = true
+	}
+}
+
+// AddProfileFlag defines the flag named ProfileArgName in f.
+func AddProfileFlag(f *pflag.FlagSet) {
+	f.String(ProfileArgName, "", "configuration profiles, separated by commas")
+}
+
+// Profiles returns the active profiles, in the order their files are loaded.
+// It returns nil if WithProfiles was not used or no profile is selected.
+func (c Config) Profiles(f *pflag.FlagSet) ([]string, error) {
+	if !c.profiles {
+		return nil, nil
+	}
+	v := os.Getenv(c.envName(ProfileArgName))
+	if p := f.Lookup(ProfileArgName); p != nil && p.Changed {
+		var err error
+		if v, err = f.GetString(ProfileArgName); err != nil {
+			return nil, fmt.Errorf("get %s flag: %v", ProfileArgName, err)
+		}
+	}
+	var profiles []string
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			profiles = append(profiles, s)
+		}
+	}
+	return profiles, nil
+}
+
+// profileKey reports whether key holds the active profiles rather than a
+// configuration value.
+func (c Config) profileKey(key string) bool {
+	return c.profiles && key == ProfileArgName
+}
+
+// withProfiles returns args with the profile files of each local config file
+// following it. Profile files that are already in args are not repeated.
+func withProfiles(args []fileArg, profiles []string) []fileArg {
+	if len(profiles) == 0 {
+		return args
+	}
+	seen := make(map[string]bool)
+	for _, fa := range args {
+		seen[fa.name] = true
+	}
+	var out []fileArg
+	for _, fa := range args {
+		out = append(out, fa)
+		if !localFile(fa.name) || isStdin(fa.name) {
+			continue
+		}
+		for _, p := range profiles {
+			name := profileFile(fa.name, p)
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			out = append(out, fileArg{name: name, mount: fa.mount, optional: true, base: fa.name})
+		}
+	}
+	return out
+}
+
+// profileFile returns the name of the file for profile next to name, with the
+// profile inserted before the extension.
+func profileFile(name, profile string) string {
+	ext := path.Ext(name)
+	if strings.ContainsAny(ext, `/\`) {
+		ext = ""
+	}
+	return strings.TrimSuffix(name, ext) + "." + profile + ext
+}
@@ -0,0 +1,164 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/pflag"
+)
+
+func TestLoadProfiles(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "config.yaml")
+	writeTestFile(t, base, "name: base\ndb:\n  host: localhost\n")
+	writeTestFile(t, filepath.Join(dir, "config.prod.yaml"), "name: prod\n")
+	writeTestFile(t, filepath.Join(dir, "config.eu.yaml"), "db:\n  host: eu.example.com\n")
+
+	cases := []struct {
+		name     string
+		args     []string
+		env      string
+		wantName string
+		wantDB   string
+	}{
+		{
+			name:     "no profile",
+			wantName: "base",
+			wantDB:   "localhost",
+		},
+		{
+			name:     "flag",
+			args:     []string{"--" + ProfileArgName + "=prod"},
+			wantName: "prod",
+			wantDB:   "localhost",
+		},
+		{
+			name:     "env",
+			env:      "prod,eu",
+			wantName: "prod",
+			wantDB:   "eu.example.com",
+		},
+		{
+			name:     "flag overrides env",
+			args:     []string{"--" + ProfileArgName + "=staging"},
+			env:      "prod",
+			wantName: "base",
+			wantDB:   "localhost",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(testPrefix+"PROFILE", tc.env)
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			AddConfigFlag(f)
+			AddProfileFlag(f)
+			if err := f.Parse(append([]string{"--" + FileArgName + "=" + base}, tc.args...)); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+			c, err := New(testPrefix, testDelimiter, WithProfiles(), WithStrict())
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+			var got testMountConfig
+			if err := c.Load(f, &got); err != nil {
+				t.Fatalf("Load err: got=%v want=nil", err)
+			}
+			var want testMountConfig
+			want.Name, want.DB.Host = tc.wantName, tc.wantDB
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("Load mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLoadProfilesConflicts(t *testing.T) {
+	cases := []struct {
+		name    string
+		other   string
+		wantErr error
+	}{
+		{
+			name:  "profile overrides its file",
+			other: "extra: x\n",
+		},
+		{
+			name:    "other file conflicts with profile",
+			other:   "db:\n  host: other.example.com\n",
+			wantErr: ConflictError,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			base, other := filepath.Join(dir, "config.yaml"), filepath.Join(dir, "other.yaml")
+			writeTestFile(t, base, "name: base\ndb:\n  host: localhost\n")
+			writeTestFile(t, filepath.Join(dir, "config.prod.yaml"), "name: prod\ndb:\n  host: prod.example.com\n")
+			writeTestFile(t, other, tc.other)
+
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			AddConfigFlag(f)
+			AddProfileFlag(f)
+			args := []string{"--" + FileArgName + "=" + base, "--" + FileArgName + "=" + other, "--" + ProfileArgName + "=prod"}
+			if err := f.Parse(args); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+			c, err := New(testPrefix, testDelimiter, WithProfiles(), WithConflicts(ConflictFail))
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+			var got testMountConfig
+			if err := c.Load(f, &got); !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Load err: got=%v want=%v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestWithProfiles(t *testing.T) {
+	args := []fileArg{
+		{name: "db.yaml", mount: "m"},
+		{name: "config.yaml"},
+		{name: "conf.d/app"},
+		{name: "https://example.com/config.yaml"},
+		{name: "config.prod.yaml"},
+	}
+	want := []fileArg{
+		{name: "db.yaml", mount: "m"},
+		{name: "db.prod.yaml", mount: "m", optional: true, base: "db.yaml"},
+		{name: "config.yaml"},
+		{name: "conf.d/app"},
+		{name: "conf.d/app.prod", optional: true, base: "conf.d/app"},
+		{name: "https://example.com/config.yaml"},
+		{name: "config.prod.yaml"},
+		{name: "config.prod.prod.yaml", optional: true, base: "config.prod.yaml"},
+	}
+	got := withProfiles(args, []string{"prod"})
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(fileArg{})); diff != "" {
+		t.Errorf("withProfiles mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,96 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"strings"
+)
+
+// KeyOrigin describes a value that a source supplied for a key.
+type KeyOrigin struct {
+	// Source names the layer, as in SourceStatus.
+	Source string
+	// Name is how the source named the key: the environment variable, the
+	// flag or the key as written in a file.
+	Name string
+	// Value is the value supplied, or RedactedValue for fields tagged
+	// secret.
+	Value interface{}
+}
+
+// Explanation describes where the value of a key came from.
+type Explanation struct {
+	Key string
+	// Final supplied the value that was used.
+	Final KeyOrigin
+	// Overridden lists the sources whose values Final replaced, from the
+	// lowest precedence to the highest.
+	Overridden []KeyOrigin
+}
+
+// keyOrigin records a value supplied for a key while loading.
+type keyOrigin struct {
+	source string
+	value  interface{}
+}
+
+// recordOrigin records that source supplied v for key.
+func (l *loaded) recordOrigin(source, key string, v interface{}) {
+	if l.origins == nil {
+		l.origins = make(map[string][]keyOrigin)
+	}
+	l.origins[key] = append(l.origins[key], keyOrigin{source: source, value: v})
+}
+
+// Explain describes which source supplied the value of key, a delimited key
+// such as db.host, and which sources it overrode. It returns false if no
+// source supplied key.
+func (r *Result) Explain(key string) (Explanation, bool) {
+	origins := r.l.origins[key]
+	if len(origins) == 0 {
+		return Explanation{}, false
+	}
+	e := Explanation{Key: key}
+	for i, o := range origins {
+		ko := KeyOrigin{Source: o.source, Name: r.l.originName(o.source, key), Value: r.l.redact(key, o.value)}
+		if i == len(origins)-1 {
+			e.Final = ko
+		} else {
+			e.Overridden = append(e.Overridden, ko)
+		}
+	}
+	return e, true
+}
+
+// originName returns how source named key.
+func (l *loaded) originName(source, key string) string {
+	if source == SourceFlags {
+		return "--" + l.flagName(key)
+	}
+	for _, s := range l.spellings[strings.ToLower(key)] {
+		if s.Source == source {
+			return s.Raw
+		}
+	}
+	return key
+}
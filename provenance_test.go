@@ -0,0 +1,105 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/pflag"
+)
+
+func TestResultExplain(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "config.yaml")
+	writeTestFile(t, name, "name: file\ndb:\n  host: file\n  password: file\n")
+	t.Setenv(testPrefix+"NAME", "env")
+	t.Setenv(testPrefix+"DB_PASSWORD", "env")
+
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	AddConfigFlag(f)
+	f.String("name", "", testNoHelpMessage)
+	if err := f.Parse([]string{"--" + FileArgName + "=" + name, "--name=flag"}); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	var cfg struct {
+		Name string `koanf:"name"`
+		DB   struct {
+			Host     string `koanf:"host"`
+			Password string `koanf:"password,secret"`
+		} `koanf:"db"`
+	}
+	r, err := c.LoadWithResult(f, &cfg)
+	if err != nil {
+		t.Fatalf("LoadWithResult err: got=%v want=nil", err)
+	}
+
+	cases := []struct {
+		key  string
+		want Explanation
+	}{
+		{
+			key: "name",
+			want: Explanation{
+				Key:   "name",
+				Final: KeyOrigin{Source: SourceFlags, Name: "--name", Value: "flag"},
+				Overridden: []KeyOrigin{
+					{Source: name, Name: "name", Value: "file"},
+					{Source: SourceEnv, Name: testPrefix + "NAME", Value: "env"},
+				},
+			},
+		},
+		{
+			key: "db.host",
+			want: Explanation{
+				Key:   "db.host",
+				Final: KeyOrigin{Source: name, Name: "db.host", Value: "file"},
+			},
+		},
+		{
+			key: "db.password",
+			want: Explanation{
+				Key:        "db.password",
+				Final:      KeyOrigin{Source: SourceEnv, Name: testPrefix + "DB_PASSWORD", Value: RedactedValue},
+				Overridden: []KeyOrigin{{Source: name, Name: "db.password", Value: RedactedValue}},
+			},
+		},
+	}
+	for _, tc := range cases {
+		got, ok := r.Explain(tc.key)
+		if !ok {
+			t.Errorf("Explain(%q) ok: got=false want=true", tc.key)
+			continue
+		}
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("Explain(%q) mismatch (-want +got):\n%s", tc.key, diff)
+		}
+	}
+	if _, ok := r.Explain("missing"); ok {
+		t.Errorf("Explain(missing) ok: got=true want=false")
+	}
+}
@@ -0,0 +1,94 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package providertest_test
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/bretmckee/goconfig/providertest"
+	kjson "github.com/knadh/koanf/parsers/json"
+	"github.com/knadh/koanf/v2"
+)
+
+// memoryProvider is an example provider serving JSON held in memory. Set
+// replaces the JSON and reports the change to the Watch callback.
+type memoryProvider struct {
+	mu   sync.Mutex
+	data []byte
+	cb   func(event interface{}, err error)
+}
+
+// ReadBytes returns the JSON for the json parser.
+func (p *memoryProvider) ReadBytes() ([]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]byte(nil), p.data...), nil
+}
+
+// Read is not supported because the contents must be parsed.
+func (p *memoryProvider) Read() (map[string]interface{}, error) {
+	return nil, errors.New("memoryProvider does not support Read")
+}
+
+// Watch makes Set call cb.
+func (p *memoryProvider) Watch(cb func(event interface{}, err error)) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cb = cb
+	return nil
+}
+
+// Set replaces the JSON served by p. The callback is called after the new
+// JSON is visible to ReadBytes.
+func (p *memoryProvider) Set(data []byte) {
+	p.mu.Lock()
+	p.data = data
+	cb := p.cb
+	p.mu.Unlock()
+	if cb != nil {
+		cb(nil, nil)
+	}
+}
+
+func marshal(t *testing.T, data map[string]interface{}) []byte {
+	t.Helper()
+	b, err := json.Marshal(data)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	return b
+}
+
+func TestMemoryProvider(t *testing.T) {
+	providertest.Run(t, providertest.Harness{
+		New: func(t *testing.T, data map[string]interface{}) (koanf.Provider, koanf.Parser) {
+			return &memoryProvider{data: marshal(t, data)}, kjson.Parser()
+		},
+		Update: func(t *testing.T, p koanf.Provider, data map[string]interface{}) {
+			p.(*memoryProvider).Set(marshal(t, data))
+		},
+	})
+}
@@ -0,0 +1,245 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package providertest checks that a third-party configuration provider
+// behaves like the built-in ones inside the goconfig Load pipeline. Provider
+// authors call Run from a test with a Harness that creates their provider:
+//
+//	func TestConformance(t *testing.T) {
+//	  providertest.Run(t, providertest.Harness{
+//	    New: func(t *testing.T, data map[string]interface{}) (koanf.Provider, koanf.Parser) {
+//	      return myprovider.New(startFakeBackend(t, data)), nil
+//	    },
+//	  })
+//	}
+//
+// A provider is a koanf.Provider, used with goconfig.WithSource. Providers
+// whose Parser is nil return an already parsed map from Read; others return
+// bytes for the parser from ReadBytes. Either way the configuration is nested
+// maps rather than keys joined with a delimiter, so that it works with every
+// delimiter. Providers that can report changes implement
+// goconfig.SourceWatcher.
+//
+// The example in this package's tests is a complete provider that passes.
+package providertest
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bretmckee/goconfig"
+	"github.com/bretmckee/goconfig/core"
+	"github.com/knadh/koanf/v2"
+	"github.com/spf13/pflag"
+)
+
+// Harness creates the provider under test.
+type Harness struct {
+	// New returns a provider, and its parser if it returns bytes, serving
+	// data. Values in data are strings, ints, bools, string slices and
+	// nested maps of the same.
+	New func(t *testing.T, data map[string]interface{}) (koanf.Provider, koanf.Parser)
+	// Update changes the data served by p, which New returned. It is only
+	// needed for providers implementing goconfig.SourceWatcher.
+	Update func(t *testing.T, p koanf.Provider, data map[string]interface{})
+	// WatchTimeout bounds how long Run waits for a change to be reported.
+	// It defaults to ten seconds.
+	WatchTimeout time.Duration
+}
+
+// envPrefix is the environment variable prefix used by Run.
+const envPrefix = "PROVIDERTEST_"
+
+// testConfig is the configuration served to the provider under test.
+type testConfig struct {
+	Name   string `koanf:"name"`
+	Level  string `koanf:"level"`
+	Extra  string `koanf:"extra"`
+	Dotted string `koanf:"dotted.key"`
+	Nested struct {
+		Value int      `koanf:"value"`
+		List  []string `koanf:"list"`
+	} `koanf:"nested"`
+}
+
+// testData returns the data served to the provider and the configuration it
+// decodes to.
+func testData(name string) (map[string]interface{}, testConfig) {
+	data := map[string]interface{}{
+		"name":       name,
+		"level":      "source",
+		"dotted.key": "dotted",
+		"nested": map[string]interface{}{
+			"value": 7,
+			"list":  []string{"a", "b"},
+		},
+	}
+	var want testConfig
+	want.Name = name
+	want.Level = "source"
+	want.Dotted = "dotted"
+	want.Nested.Value = 7
+	want.Nested.List = []string{"a", "b"}
+	return data, want
+}
+
+// Run checks the provider created by h against the goconfig provider
+// contract: reading, delimiter independence, precedence inside Load and, for
+// providers implementing goconfig.SourceWatcher, watch semantics.
+func Run(t *testing.T, h Harness) {
+	t.Helper()
+	t.Run("Read", func(t *testing.T) { testRead(t, h) })
+	t.Run("Delimiter", func(t *testing.T) { testDelimiter(t, h) })
+	t.Run("Precedence", func(t *testing.T) { testPrecedence(t, h) })
+	t.Run("Watch", func(t *testing.T) { testWatch(t, h) })
+}
+
+// read loads p with parser using delim and decodes the result.
+func read(t *testing.T, p koanf.Provider, parser koanf.Parser, delim string) testConfig {
+	t.Helper()
+	k := koanf.New(delim)
+	if err := k.Load(p, parser); err != nil {
+		t.Fatalf("Load provider: %v", err)
+	}
+	var got testConfig
+	if err := core.Unmarshal(k.Raw(), &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	return got
+}
+
+func check(t *testing.T, what string, got, want testConfig) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s: got=%+v want=%+v", what, got, want)
+	}
+}
+
+// testRead checks that the provider serves its data, and the same data when
+// read again.
+func testRead(t *testing.T, h Harness) {
+	data, want := testData("source")
+	p, parser := h.New(t, data)
+	check(t, "first read", read(t, p, parser, "."), want)
+	check(t, "second read", read(t, p, parser, "."), want)
+}
+
+// testDelimiter checks that the provider does not depend on the delimiter,
+// as it would if it joined keys itself.
+func testDelimiter(t *testing.T, h Harness) {
+	data, want := testData("source")
+	p, parser := h.New(t, data)
+	check(t, `read with delimiter "/"`, read(t, p, parser, "/"), want)
+}
+
+// testPrecedence checks that the provider is loaded after config files and
+// before the environment.
+func testPrecedence(t *testing.T, h Harness) {
+	data, want := testData("source")
+	p, parser := h.New(t, data)
+
+	file := filepath.Join(t.TempDir(), "config.json")
+	b, err := json.Marshal(map[string]string{"name": "file", "level": "file", "extra": "file"})
+	if err != nil {
+		t.Fatalf("marshal config file: %v", err)
+	}
+	if err := os.WriteFile(file, b, 0o600); err != nil {
+		t.Fatalf("write config file: %v", err)
+	}
+	t.Setenv(envPrefix+"LEVEL", "env")
+
+	c, err := goconfig.New(envPrefix, ".", goconfig.WithSource(goconfig.Source{Name: "provider", Provider: p, Parser: parser}))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	f := pflag.NewFlagSet("providertest", pflag.ContinueOnError)
+	goconfig.AddConfigFlag(f)
+	if err := f.Parse([]string{"--" + goconfig.FileArgName + "=" + file}); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+
+	var got testConfig
+	if err := c.Load(f, &got); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want.Extra = "file"
+	want.Level = "env"
+	check(t, "Load", got, want)
+}
+
+// testWatch checks that a watching provider reports a change made after
+// Watch returns, and that Read returns the new data once it has been
+// reported.
+func testWatch(t *testing.T, h Harness) {
+	data, _ := testData("source")
+	p, parser := h.New(t, data)
+	w, ok := p.(goconfig.SourceWatcher)
+	if !ok {
+		t.Skip("provider does not implement goconfig.SourceWatcher")
+	}
+	if h.Update == nil {
+		t.Fatalf("provider implements goconfig.SourceWatcher but Harness.Update is nil")
+	}
+	if c, ok := p.(interface{ Close() error }); ok {
+		defer c.Close()
+	}
+	timeout := h.WatchTimeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+
+	events := make(chan error, 1)
+	err := w.Watch(func(_ interface{}, err error) {
+		select {
+		case events <- err:
+		default:
+		}
+	})
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	updated, want := testData("updated")
+	h.Update(t, p, updated)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	for {
+		select {
+		case err := <-events:
+			if err != nil {
+				t.Fatalf("Watch callback err: got=%v want=nil", err)
+			}
+			// Reports of earlier changes may arrive first.
+			if got := read(t, p, parser, "."); reflect.DeepEqual(got, want) {
+				return
+			}
+		case <-ctx.Done():
+			t.Fatalf("change not reported within %v", timeout)
+		}
+	}
+}
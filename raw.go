@@ -0,0 +1,89 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/knadh/koanf/v2"
+	"github.com/spf13/pflag"
+)
+
+// Names of in-memory configuration when reporting where values came from.
+const (
+	// SourceRaw names configuration set with WithRawConfig.
+	SourceRaw = "raw"
+	// SourceReader names the configuration passed to LoadReader.
+	SourceReader = "reader"
+)
+
+// rawConfig is configuration held in memory.
+type rawConfig struct {
+	name   string
+	data   []byte
+	format string
+}
+
+// WithRawConfig adds data, a YAML or JSON document, as configuration that is
+// loaded before the config files, so they override it. It is intended for
+// defaults embedded in the binary and for tests. WithRawConfig may be used
+// more than once; later documents override earlier ones.
+func WithRawConfig(data []byte) Option {
+	return func(c *Config) {
+		c.raw = append(c.raw, rawConfig{name: SourceRaw, data: data, format: FormatYAML})
+	}
+}
+
+// rawLayers returns the layers for the in-memory configuration.
+func (c Config) rawLayers() []layer {
+	var layers []layer
+	for _, raw := range c.raw {
+		raw := raw
+		layers = append(layers, layer{
+			name: raw.name,
+			desc: raw.name,
+			open: func(*loaded) (koanf.Provider, koanf.Parser, error) {
+				parser, err := c.formatParser(raw.format)
+				if err != nil {
+					return nil, nil, err
+				}
+				return bytesProvider(func() ([]byte, error) { return raw.data, nil }), parser, nil
+			},
+		})
+	}
+	return layers
+}
+
+// LoadReader loads cfg like Load, but with the configuration read from r, in
+// format, such as FormatYAML, in place of config files. There are no flags,
+// so only defaults, r, sources and the environment are loaded.
+func (c Config) LoadReader(r io.Reader, format string, cfg interface{}) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("LoadReader: %w", err)
+	}
+	c.raw = append(c.raw[:len(c.raw):len(c.raw)], rawConfig{name: SourceReader, data: data, format: format})
+	c.noFiles = true
+	return c.Load(pflag.NewFlagSet(SourceReader, pflag.ContinueOnError), cfg)
+}
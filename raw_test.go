@@ -0,0 +1,117 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestWithRawConfig(t *testing.T) {
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	AddConfigFlag(f)
+	if err := f.Parse([]string{"--" + FileArgName + "=" + testFileName(testGoodJSONConfig)}); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	c, err := New(testPrefix, testDelimiter,
+		WithRawConfig([]byte(fmt.Sprintf("%s: 1\n%s: 1\n%s: 1\n", testKey1, testKey2, testKey3))),
+		WithRawConfig([]byte(fmt.Sprintf(`{"%s": %d}`, testKey3, testValue3))),
+	)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	var got testConfig
+	r, err := c.LoadWithResult(f, &got)
+	if err != nil {
+		t.Fatalf("Load err: got=%v want=nil", err)
+	}
+	// The config file overrides the raw configuration.
+	want := testConfig{Value1: testValue1, Value2: 1, Value3: testValue3}
+	want.Nested.NestedVal = testValue2
+	if got != want {
+		t.Errorf("Load: got=%+v want=%+v", got, want)
+	}
+	if got, want := r.SourceStatus()[0].Name, SourceRaw; got != want {
+		t.Errorf("first source: got=%q want=%q", got, want)
+	}
+}
+
+func TestLoadReader(t *testing.T) {
+	cases := []struct {
+		name    string
+		input   string
+		format  string
+		env     map[string]string
+		want    testConfig
+		wantErr error
+	}{
+		{
+			name:   "json",
+			input:  fmt.Sprintf(`{"%s": %d}`, testKey1, testValue1),
+			format: FormatJSON,
+			want:   testConfig{Value1: testValue1},
+		},
+		{
+			name:   "env overrides reader",
+			input:  fmt.Sprintf("%s: 1\n%s: %d\n", testKey1, testKey2, testValue2),
+			format: FormatYAML,
+			env:    map[string]string{testPrefix + "VALUE1": fmt.Sprint(testValue1)},
+			want:   testConfig{Value1: testValue1, Value2: testValue2},
+		},
+		{
+			name:   "files are ignored",
+			input:  "",
+			format: FormatYAML,
+			env:    map[string]string{testPrefix + "CONFIG": testFileName(testGoodJSONConfig)},
+		},
+		{
+			name:    "unknown format",
+			format:  "xml",
+			wantErr: UnknownFormatError,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			c, err := New(testPrefix, testDelimiter)
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+			var got testConfig
+			err = c.LoadReader(strings.NewReader(tc.input), tc.format, &got)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("LoadReader err: got=%v want=%v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("LoadReader: got=%+v want=%+v", got, tc.want)
+			}
+		})
+	}
+}
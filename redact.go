@@ -0,0 +1,149 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// RedactedValue replaces the values of fields tagged secret, as in
+// `secret:"true"` or `koanf:"password,secret"`, in the errors, log messages
+// and key changes produced by the package.
+const RedactedValue = "[REDACTED]"
+
+// secretKeys returns the delimited keys of the fields of cfg tagged secret.
+func (c Config) secretKeys(cfg interface{}) map[string]bool {
+	v, err := structValue(cfg)
+	if err != nil {
+		return nil
+	}
+	keys := make(map[string]bool)
+	_ = walkFields(v, "", c.delimiter, func(key string, sf reflect.StructField, _ reflect.Value) error {
+		if isSecret(sf) {
+			keys[key] = true
+		}
+		return nil
+	})
+	return keys
+}
+
+// isSecret reports whether key is, or is below, a field tagged secret.
+func (l *loaded) isSecret(key string) bool {
+	return secretKey(l.secrets, key, l.k.Delim())
+}
+
+// secretKey reports whether key is, or is below, one of secrets, keys
+// delimited by delim.
+func secretKey(secrets map[string]bool, key, delim string) bool {
+	for s := range secrets {
+		if key == s || strings.HasPrefix(key, s+delim) {
+			return true
+		}
+	}
+	return false
+}
+
+// redact returns RedactedValue if key is secret, and otherwise v.
+func (l *loaded) redact(key string, v interface{}) interface{} {
+	if v != nil && l.isSecret(key) {
+		return RedactedValue
+	}
+	return v
+}
+
+// redactError returns err, the failure to unmarshal values that could not be
+// attributed to one field, or a copy of it if its message quotes the value of
+// a secret key. In the copy each quoted value, as the decoder quotes values,
+// is replaced by RedactedValue, so that short values such as 1 do not change
+// unrelated text. The copy does not wrap err, so that the values cannot be
+// recovered with errors.Unwrap or errors.As.
+func (l *loaded) redactError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var values []string
+	for _, key := range l.k.Keys() {
+		if l.isSecret(key) {
+			values = append(values, secretStrings(l.k.Get(key))...)
+		}
+	}
+	// Longer values are replaced first, so that a value containing another
+	// is not partly revealed.
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+	var quoted []string
+	for _, v := range values {
+		quoted = append(quoted, "'"+v+"'", RedactedValue, strconv.Quote(v), RedactedValue)
+	}
+	r := strings.NewReplacer(quoted...)
+
+	var me *mapstructure.Error
+	if errors.As(err, &me) {
+		redacted := &mapstructure.Error{Errors: make([]string, len(me.Errors))}
+		for i, e := range me.Errors {
+			redacted.Errors[i] = r.Replace(e)
+		}
+		if redacted.Error() == me.Error() {
+			return err
+		}
+		return redacted
+	}
+	if msg := r.Replace(err.Error()); msg != err.Error() {
+		return errors.New(msg)
+	}
+	return err
+}
+
+// secretFieldError is the error reported for a secret field whose value
+// cannot be decoded, which leaves out the decoder's message because it quotes
+// the value.
+func secretFieldError() error {
+	return fmt.Errorf("value %s cannot be decoded", RedactedValue)
+}
+
+// secretStrings returns the non empty strings that v is printed as.
+func secretStrings(v interface{}) []string {
+	var ss []string
+	switch v := v.(type) {
+	case nil:
+	case []interface{}:
+		for _, e := range v {
+			ss = append(ss, secretStrings(e)...)
+		}
+	case map[string]interface{}:
+		for _, e := range v {
+			ss = append(ss, secretStrings(e)...)
+		}
+	default:
+		if s := fmt.Sprint(v); s != "" {
+			ss = append(ss, s)
+		}
+	}
+	return ss
+}
@@ -0,0 +1,153 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/knadh/koanf/v2"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/pflag"
+)
+
+type testRedactConfig struct {
+	Pin      int    `koanf:"pin,secret"`
+	Password string `koanf:"password" secret:"true" oneof:"a,b" validate:"min=20"`
+}
+
+func TestRedactSecrets(t *testing.T) {
+	const secret = "hunter2-hunter2"
+	cases := []struct {
+		name     string
+		contents []string
+		opts     []Option
+		wantErr  error
+	}{
+		{
+			name:     "unmarshal",
+			contents: []string{"pin: " + secret + "\n"},
+			wantErr:  &UnmarshalError{},
+		},
+		{
+			name:     "oneof",
+			contents: []string{"password: " + secret + "\n"},
+			wantErr:  NotOneOfError,
+		},
+		{
+			name:     "validation",
+			contents: []string{"password: a\n"},
+			opts:     []Option{WithValidation()},
+			wantErr:  &ValidationError{},
+		},
+		{
+			name:     "conflict",
+			contents: []string{"password: " + secret + "\n", "password: a\n"},
+			opts:     []Option{WithConflicts(ConflictFail)},
+			wantErr:  ConflictError,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			var args []string
+			for i, contents := range tc.contents {
+				name := filepath.Join(dir, string(rune('a'+i))+".yaml")
+				writeTestFile(t, name, contents)
+				args = append(args, "--"+FileArgName+"="+name)
+			}
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			AddConfigFlag(f)
+			if err := f.Parse(args); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+			c, err := New(testPrefix, testDelimiter, tc.opts...)
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+			var cfg testRedactConfig
+			err = c.Load(f, &cfg)
+			switch want := tc.wantErr.(type) {
+			case *UnmarshalError:
+				if !errors.As(err, &want) {
+					t.Fatalf("Load err: got=%v want=%T", err, want)
+				}
+			case *ValidationError:
+				if !errors.As(err, &want) {
+					t.Fatalf("Load err: got=%v want=%T", err, want)
+				}
+				for _, fe := range want.Fields {
+					if fe.Key == "password" && fe.Value != RedactedValue {
+						t.Errorf("FieldError.Value: got=%v want=%s", fe.Value, RedactedValue)
+					}
+				}
+			default:
+				if !errors.Is(err, want) {
+					t.Fatalf("Load err: got=%v want=%v", err, want)
+				}
+			}
+			if strings.Contains(err.Error(), secret) {
+				t.Errorf("Load err: got=%v, which contains the secret", err)
+			}
+		})
+	}
+}
+
+func TestKeyChangesRedacted(t *testing.T) {
+	newLoaded := func(password string) *loaded {
+		l := &loaded{k: koanf.New(testDelimiter), secrets: map[string]bool{"db": true}}
+		if err := l.k.Set("db.password", password); err != nil {
+			t.Fatalf("k.Set failed unexpectedly: %v", err)
+		}
+		return l
+	}
+	changes := keyChanges(newLoaded("old"), newLoaded("new"))
+	if len(changes) != 1 || changes[0].Old != RedactedValue || changes[0].New != RedactedValue {
+		t.Errorf("keyChanges: got=%+v want values %s", changes, RedactedValue)
+	}
+}
+
+func TestRedactErrorQuotedOnly(t *testing.T) {
+	l := &loaded{k: koanf.New(testDelimiter), secrets: map[string]bool{"pin": true}}
+	if err := l.k.Set("pin", "1"); err != nil {
+		t.Fatalf("k.Set failed unexpectedly: %v", err)
+	}
+	inner := &mapstructure.Error{Errors: []string{`cannot parse 'pin' as int: parsing "1": 1 error`}}
+	err := l.redactError(inner)
+
+	want := `1 error(s) decoding:
+
+* cannot parse 'pin' as int: parsing [REDACTED]: 1 error`
+	if got := err.Error(); got != want {
+		t.Errorf("redactError: got=%q want=%q", got, want)
+	}
+	var me *mapstructure.Error
+	if !errors.As(err, &me) || me == inner {
+		t.Errorf("redactError: got=%#v want a redacted copy of the mapstructure.Error", err)
+	}
+	if errors.Unwrap(err) != nil {
+		t.Errorf("errors.Unwrap: got=%v want=nil", errors.Unwrap(err))
+	}
+}
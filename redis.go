@@ -0,0 +1,168 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/knadh/koanf/maps"
+)
+
+// RedisClient is the subset of a Redis client used by RedisProvider. The
+// package does not depend on a Redis library, so callers adapt the client of
+// their choice.
+type RedisClient interface {
+	// HGetAll returns all fields and values of the hash stored at key.
+	HGetAll(ctx context.Context, key string) (map[string]string, error)
+	// GetPrefix returns the values of all keys starting with prefix, keyed
+	// by their full names. It is typically implemented with SCAN and MGET.
+	GetPrefix(ctx context.Context, prefix string) (map[string]string, error)
+}
+
+// RedisSubscriber is implemented by a RedisClient that supports pub/sub. The
+// returned channel receives a value for every message published on channel
+// and is closed when ctx is done.
+type RedisSubscriber interface {
+	Subscribe(ctx context.Context, channel string) (<-chan string, error)
+}
+
+// RedisOptions controls where RedisProvider reads configuration from.
+type RedisOptions struct {
+	// Client is used to read from Redis.
+	Client RedisClient
+	// Hash, if set, names a hash whose fields are configuration keys, and
+	// Prefix is ignored. Otherwise every key starting with Prefix is a
+	// configuration key with Prefix removed.
+	Hash   string
+	Prefix string
+	// Delimiter separates nested keys in field and key names. If empty "."
+	// is used.
+	Delimiter string
+	// Channel, if set, is a pub/sub channel on which a message is published
+	// whenever the configuration changes. It is used by Watch.
+	Channel string
+}
+
+// RedisProvider is a koanf.Provider that reads configuration from Redis. It
+// can be added to a Config using WithSource.
+type RedisProvider struct {
+	opts   RedisOptions
+	mu     sync.Mutex
+	cancel context.CancelFunc
+}
+
+// NewRedisProvider returns a RedisProvider reading as described by o.
+func NewRedisProvider(o RedisOptions) *RedisProvider {
+	if o.Delimiter == "" {
+		o.Delimiter = "."
+	}
+	return &RedisProvider{opts: o}
+}
+
+// ReadBytes is not supported because Redis values are read as a map.
+func (p *RedisProvider) ReadBytes() ([]byte, error) {
+	return nil, errors.New("RedisProvider does not support ReadBytes")
+}
+
+// ReadBytesContext is not supported because Redis values are read as a map.
+func (p *RedisProvider) ReadBytesContext(context.Context) ([]byte, error) {
+	return p.ReadBytes()
+}
+
+// Read returns the configuration stored in Redis.
+func (p *RedisProvider) Read() (map[string]interface{}, error) {
+	return p.ReadContext(context.Background())
+}
+
+// ReadContext is like Read, but passes ctx to the Redis client.
+func (p *RedisProvider) ReadContext(ctx context.Context) (map[string]interface{}, error) {
+	var values map[string]string
+	var err error
+	if p.opts.Hash != "" {
+		values, err = p.opts.Client.HGetAll(ctx, p.opts.Hash)
+	} else {
+		values, err = p.opts.Client.GetPrefix(ctx, p.opts.Prefix)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("redis read: %w", err)
+	}
+
+	flat := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		// Hash fields are keys as they are.
+		if p.opts.Hash == "" {
+			k = strings.TrimPrefix(k, p.opts.Prefix)
+		}
+		flat[k] = v
+	}
+	return maps.Unflatten(flat, p.opts.Delimiter), nil
+}
+
+// Watch calls cb every time a message is published on the configured
+// channel. It returns once the subscription is established. Call Close to
+// stop watching.
+func (p *RedisProvider) Watch(cb func(event interface{}, err error)) error {
+	sub, ok := p.opts.Client.(RedisSubscriber)
+	if !ok {
+		return fmt.Errorf("redis watch: client: %w", NoSubscriberError)
+	}
+	if p.opts.Channel == "" {
+		return fmt.Errorf("redis watch: no channel configured: %w", NoSubscriberError)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := sub.Subscribe(ctx, p.opts.Channel)
+	if err != nil {
+		cancel()
+		return fmt.Errorf("redis subscribe %s: %w", p.opts.Channel, err)
+	}
+
+	p.mu.Lock()
+	if p.cancel != nil {
+		p.cancel()
+	}
+	p.cancel = cancel
+	p.mu.Unlock()
+
+	go func() {
+		for msg := range ch {
+			cb(msg, nil)
+		}
+	}()
+	return nil
+}
+
+// Close stops a Watch in progress.
+func (p *RedisProvider) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cancel != nil {
+		p.cancel()
+		p.cancel = nil
+	}
+	return nil
+}
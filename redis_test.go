@@ -0,0 +1,240 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/pflag"
+)
+
+type fakeRedis struct {
+	hashes map[string]map[string]string
+	keys   map[string]string
+	msgs   chan string
+}
+
+func (r *fakeRedis) HGetAll(_ context.Context, key string) (map[string]string, error) {
+	h, ok := r.hashes[key]
+	if !ok {
+		return nil, errors.New("no such hash")
+	}
+	return h, nil
+}
+
+func (r *fakeRedis) GetPrefix(_ context.Context, prefix string) (map[string]string, error) {
+	m := make(map[string]string)
+	for k, v := range r.keys {
+		if strings.HasPrefix(k, prefix) {
+			m[k] = v
+		}
+	}
+	return m, nil
+}
+
+func (r *fakeRedis) Subscribe(_ context.Context, _ string) (<-chan string, error) {
+	return r.msgs, nil
+}
+
+func TestLoadViaRedis(t *testing.T) {
+	want := testConfig{
+		Value1: testValue1,
+		Nested: testConfig1{
+			NestedVal: testValue2,
+		},
+	}
+	client := &fakeRedis{
+		hashes: map[string]map[string]string{
+			"app": {
+				testKey1:                            strconv.Itoa(testValue1),
+				testNestedTag + "." + testNestedKey: strconv.Itoa(testValue2),
+			},
+		},
+		keys: map[string]string{
+			"app:" + testKey1: strconv.Itoa(testValue1),
+			"app:" + testNestedTag + "." + testNestedKey: strconv.Itoa(testValue2),
+			"other:" + testKey2:                          strconv.Itoa(testValue3),
+		},
+	}
+
+	cases := []struct {
+		name    string
+		opts    RedisOptions
+		wantErr bool
+	}{
+		{
+			name: "hash",
+			opts: RedisOptions{Client: client, Hash: "app"},
+		},
+		{
+			name: "hash ignores prefix",
+			opts: RedisOptions{Client: client, Hash: "app", Prefix: "val"},
+		},
+		{
+			name: "prefix",
+			opts: RedisOptions{Client: client, Prefix: "app:"},
+		},
+		{
+			name:    "missing hash",
+			opts:    RedisOptions{Client: client, Hash: "missing"},
+			wantErr: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+
+			src := Source{Name: "redis", Provider: NewRedisProvider(tc.opts)}
+			c, err := New(testPrefix, testDelimiter, WithSource(src))
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+
+			var cfg testConfig
+			err = c.Load(f, &cfg)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("Load err: got=nil want=<non-nil>")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load err: got=%v want=nil", err)
+			}
+			if diff := cmp.Diff(want, cfg); diff != "" {
+				t.Errorf("Load cfg mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRedisProviderWatch(t *testing.T) {
+	client := &fakeRedis{msgs: make(chan string)}
+	p := NewRedisProvider(RedisOptions{Client: client, Hash: "app", Channel: "reload"})
+	defer p.Close()
+
+	events := make(chan interface{}, 1)
+	if err := p.Watch(func(event interface{}, err error) {
+		events <- event
+	}); err != nil {
+		t.Fatalf("Watch err: got=%v want=nil", err)
+	}
+
+	client.msgs <- "changed"
+	select {
+	case got := <-events:
+		if want := "changed"; got != want {
+			t.Errorf("event: got=%v want=%v", got, want)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for event")
+	}
+	close(client.msgs)
+}
+
+func TestRedisProviderWatchNoSubscriber(t *testing.T) {
+	p := NewRedisProvider(RedisOptions{Client: struct{ RedisClient }{}, Channel: "reload"})
+	if err := p.Watch(func(interface{}, error) {}); !errors.Is(err, NoSubscriberError) {
+		t.Errorf("Watch err: got=%v want=%v", err, NoSubscriberError)
+	}
+}
+
+// testPolledRedis is a RedisClient without Subscribe whose hash can be
+// changed while it is polled.
+type testPolledRedis struct {
+	mu   sync.Mutex
+	hash map[string]string
+}
+
+func (r *testPolledRedis) HGetAll(context.Context, string) (map[string]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	h := make(map[string]string, len(r.hash))
+	for k, v := range r.hash {
+		h[k] = v
+	}
+	return h, nil
+}
+
+func (r *testPolledRedis) GetPrefix(context.Context, string) (map[string]string, error) {
+	return nil, errors.New("not supported")
+}
+
+func (r *testPolledRedis) set(key, value string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hash[key] = value
+}
+
+func TestWatchPollsRedisWithoutSubscriber(t *testing.T) {
+	client := &testPolledRedis{hash: map[string]string{testKey1: strconv.Itoa(testValue1)}}
+
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	if err := f.Parse(nil); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	p := NewRedisProvider(RedisOptions{Client: client, Hash: "app"})
+	c, err := New(testPrefix, testDelimiter,
+		WithSource(Source{Name: "redis", Provider: p}),
+		WithPollInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	changes := make(chan testConfig, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var cfg testConfig
+	err = Watch(ctx, c, f, &cfg, func(_, new testConfig) error {
+		changes <- new
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Watch err: got=%v want=nil", err)
+	}
+
+	// Let the first poll record the initial content.
+	select {
+	case got := <-changes:
+		t.Fatalf("unexpected reload: %+v", got)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	client.set(testKey1, strconv.Itoa(testValue2))
+	select {
+	case got := <-changes:
+		if got, want := got.Value1, testValue2; got != want {
+			t.Errorf("Value1: got=%d want=%d", got, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for reload")
+	}
+}
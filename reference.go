@@ -0,0 +1,148 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/knadh/koanf/v2"
+)
+
+var (
+	UnresolvedReferenceError = errors.New("reference to a key that is not set")
+	ReferenceCycleError      = errors.New("keys refer to each other")
+)
+
+// WithReferences makes Load replace references to other keys in string
+// values, once every layer has been merged, so that values are not repeated:
+//
+//	url: http://${nested.host}:${nested.port}
+//
+// ${key:-default} is replaced by default if key is not set. A value that is
+// only a reference takes the value of the key as is, so it may be a number
+// or list. Referenced values may contain references, and keys that refer
+// to each other fail Load with ReferenceCycleError. A reference to a key that
+// is not set, with no default, fails Load with UnresolvedReferenceError. $${
+// is a literal ${. With WithInterpolation, environment variables are expanded
+// first and references are resolved only if no such variable is set.
+func WithReferences() Option {
+	return func(c *Config) {
+		c.references = true
+	}
+}
+
+// wholeReference matches a value that is a single reference.
+var wholeReference = regexp.MustCompile(`^\$\{([^}]*)\}$`)
+
+// referenceResolver resolves the references in the values of k.
+type referenceResolver struct {
+	k         *koanf.Koanf
+	resolved  map[string]interface{}
+	resolving map[string]bool
+}
+
+// resolveReferences replaces the references in the values of k as described
+// by WithReferences.
+func resolveReferences(k *koanf.Koanf) error {
+	r := referenceResolver{
+		k:         k,
+		resolved:  make(map[string]interface{}),
+		resolving: make(map[string]bool),
+	}
+	keys := k.Keys()
+	sort.Strings(keys)
+	values := make(map[string]interface{}, len(keys))
+	for _, key := range keys {
+		v, err := r.resolve(key)
+		if err != nil {
+			return err
+		}
+		values[key] = v
+	}
+	for _, key := range keys {
+		if v := values[key]; v != nil {
+			if err := k.Set(key, v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resolve returns the value of key with its references resolved, or nil if it
+// has none.
+func (r *referenceResolver) resolve(key string) (interface{}, error) {
+	if v, ok := r.resolved[key]; ok {
+		return v, nil
+	}
+	if r.resolving[key] {
+		return nil, fmt.Errorf("%s: %w", key, ReferenceCycleError)
+	}
+	r.resolving[key] = true
+	defer delete(r.resolving, key)
+
+	v, changed, err := rewriteStrings(r.k.Get(key), func(s string) (interface{}, error) {
+		if m := wholeReference.FindStringSubmatch(s); m != nil {
+			name, def, hasDefault := strings.Cut(m[1], ":-")
+			if !r.k.Exists(name) && hasDefault {
+				return def, nil
+			}
+			return r.value(key, name)
+		}
+		return expandBraces(s, true, func(ref string) (string, bool, error) {
+			name, def, hasDefault := strings.Cut(ref, ":-")
+			if !r.k.Exists(name) && hasDefault {
+				return def, true, nil
+			}
+			v, err := r.value(key, name)
+			if err != nil {
+				return "", false, err
+			}
+			return fmt.Sprint(v), true, nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !changed {
+		v = nil
+	}
+	r.resolved[key] = v
+	return v, nil
+}
+
+// value returns the value of name, referred to by key, with its references
+// resolved.
+func (r *referenceResolver) value(key, name string) (interface{}, error) {
+	if !r.k.Exists(name) {
+		return nil, fmt.Errorf("%s: ${%s}: %w", key, name, UnresolvedReferenceError)
+	}
+	if v, err := r.resolve(name); err != nil || v != nil {
+		return v, err
+	}
+	return r.k.Get(name), nil
+}
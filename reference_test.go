@@ -0,0 +1,120 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knadh/koanf/v2"
+	"github.com/spf13/pflag"
+)
+
+func TestResolveReferences(t *testing.T) {
+	cases := []struct {
+		name    string
+		values  map[string]interface{}
+		want    map[string]interface{}
+		wantErr error
+	}{
+		{
+			name:   "string",
+			values: map[string]interface{}{"host": "db", "port": 5432, "url": "http://${host}:${port}/"},
+			want:   map[string]interface{}{"host": "db", "port": 5432, "url": "http://db:5432/"},
+		},
+		{
+			name:   "whole value keeps type",
+			values: map[string]interface{}{"port": 5432, "copy": "${port}"},
+			want:   map[string]interface{}{"port": 5432, "copy": 5432},
+		},
+		{
+			name:   "chained",
+			values: map[string]interface{}{"a": "${b}/a", "b": "${c}/b", "c": "c"},
+			want:   map[string]interface{}{"a": "c/b/a", "b": "c/b", "c": "c"},
+		},
+		{
+			name:   "list",
+			values: map[string]interface{}{"host": "db", "hosts": []interface{}{"${host}", "other"}},
+			want:   map[string]interface{}{"host": "db", "hosts": []interface{}{"db", "other"}},
+		},
+		{
+			name:   "default and escape",
+			values: map[string]interface{}{"a": "${missing:-x}", "b": "$${a}"},
+			want:   map[string]interface{}{"a": "x", "b": "${a}"},
+		},
+		{
+			name:    "unresolved",
+			values:  map[string]interface{}{"a": "${missing}"},
+			wantErr: UnresolvedReferenceError,
+		},
+		{
+			name:    "cycle",
+			values:  map[string]interface{}{"a": "${b}", "b": "x${a}"},
+			wantErr: ReferenceCycleError,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			k := koanf.New(testDelimiter)
+			if err := k.Load(mapProvider{m: tc.values, delim: testDelimiter}, nil); err != nil {
+				t.Fatalf("k.Load failed unexpectedly: %v", err)
+			}
+			err := resolveReferences(k)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("resolveReferences err: got=%v want=%v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, k.All()); err == nil && diff != "" {
+				t.Errorf("resolveReferences mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWithReferences(t *testing.T) {
+	t.Setenv("GOCONFIG_TEST_HOST", "db.example.com")
+	name := filepath.Join(t.TempDir(), "config.yaml")
+	writeTestFile(t, name, "name: ${GOCONFIG_TEST_HOST}/${db.host}\ndb:\n  host: ${GOCONFIG_TEST_HOST}\n")
+
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	AddConfigFlag(f)
+	f.String("db.host", "", testNoHelpMessage)
+	if err := f.Parse([]string{"--" + FileArgName + "=" + name, "--db.host=override"}); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	c, err := New(testPrefix, testDelimiter, WithInterpolation(), WithReferences())
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	var got testMountConfig
+	if err := c.Load(f, &got); err != nil {
+		t.Fatalf("Load err: got=%v want=nil", err)
+	}
+	var want testMountConfig
+	want.Name = "db.example.com/override"
+	want.DB.Host = "override"
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Load mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,161 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+var (
+	BadShortFlagError = errors.New("flag shorthand must be one unused character")
+)
+
+// shortTagName is the struct tag giving the single letter shorthand of the
+// flag defined for a field by RegisterFlags, as in `short:"p"`.
+const shortTagName = "short"
+
+// hiddenTagName is the struct tag that hides the flag defined for a field by
+// RegisterFlags from the help text, as in `hidden:"true"`.
+const hiddenTagName = "hidden"
+
+// deprecatedTagName is the struct tag that marks the flag defined for a field
+// by RegisterFlags as deprecated, as in `deprecated:"use --db-url instead"`.
+const deprecatedTagName = "deprecated"
+
+// RegisterFlags defines a flag in f for every field of cfg, so that the flags
+// do not have to be kept in step with the struct by hand. A flag is named by
+// the key of its field, or its flag tag, has the type of the field, the help
+// tag of the field as its usage and the default tag of the field as its
+// default. A short tag gives the flag a shorthand, as in -p, and RegisterFlags
+// fails with BadShortFlagError if it is not a single character or is already
+// used. A hidden tag hides the flag from the help text, and a deprecated tag
+// hides it and prints its message when it is used, so that flags can be
+// retired gradually. Flags already defined in f are left alone, as are fields
+// of types flags cannot hold, such as maps with values that are not strings.
+// Call RegisterFlags before f.Parse.
+func (c Config) RegisterFlags(f *pflag.FlagSet, cfg interface{}) error {
+	v, err := structValue(cfg)
+	if err != nil {
+		return fmt.Errorf("RegisterFlags: %w", err)
+	}
+	return walkFields(v, "", c.delimiter, func(key string, sf reflect.StructField, _ reflect.Value) error {
+		name := fieldFlagName(key, sf)
+		if f.Lookup(name) != nil {
+			return nil
+		}
+		short := sf.Tag.Get(shortTagName)
+		if short != "" && (len(short) != 1 || f.ShorthandLookup(short) != nil) {
+			return fmt.Errorf("RegisterFlags %s shorthand %q: %w", key, short, BadShortFlagError)
+		}
+		if !defineFlag(f, name, short, sf.Type, sf.Tag.Get(helpTagName)) {
+			return nil
+		}
+		if hidden, _ := strconv.ParseBool(sf.Tag.Get(hiddenTagName)); hidden {
+			if err := f.MarkHidden(name); err != nil {
+				return fmt.Errorf("RegisterFlags %s: %w", key, err)
+			}
+		}
+		if msg := sf.Tag.Get(deprecatedTagName); msg != "" {
+			if err := f.MarkDeprecated(name, msg); err != nil {
+				return fmt.Errorf("RegisterFlags %s: %w", key, err)
+			}
+		}
+		d, ok := sf.Tag.Lookup(defaultTagName)
+		if !ok || isSecret(sf) {
+			return nil
+		}
+		fl := f.Lookup(name)
+		if err := fl.Value.Set(d); err != nil {
+			return fmt.Errorf("RegisterFlags %s default %q: %w", key, d, err)
+		}
+		fl.DefValue = fl.Value.String()
+		return nil
+	})
+}
+
+// defineFlag defines the flag name, with the shorthand short if it is not
+// empty, in f for a field of type t, and reports whether flags can hold t.
+func defineFlag(f *pflag.FlagSet, name, short string, t reflect.Type, usage string) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t {
+	case reflect.TypeOf(time.Duration(0)):
+		f.DurationP(name, short, 0, usage)
+		return true
+	case reflect.TypeOf([]time.Duration(nil)):
+		f.DurationSliceP(name, short, nil, usage)
+		return true
+	case reflect.TypeOf(net.IP(nil)):
+		f.IPP(name, short, nil, usage)
+		return true
+	}
+	if isTextUnmarshaler(t) {
+		f.StringP(name, short, "", usage)
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		f.BoolP(name, short, false, usage)
+	case reflect.String:
+		f.StringP(name, short, "", usage)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		f.IntP(name, short, 0, usage)
+	case reflect.Int64:
+		f.Int64P(name, short, 0, usage)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		f.UintP(name, short, 0, usage)
+	case reflect.Uint64:
+		f.Uint64P(name, short, 0, usage)
+	case reflect.Float32, reflect.Float64:
+		f.Float64P(name, short, 0, usage)
+	case reflect.Slice:
+		switch t.Elem().Kind() {
+		case reflect.String:
+			f.StringSliceP(name, short, nil, usage)
+		case reflect.Int:
+			f.IntSliceP(name, short, nil, usage)
+		case reflect.Bool:
+			f.BoolSliceP(name, short, nil, usage)
+		case reflect.Float64:
+			f.Float64SliceP(name, short, nil, usage)
+		default:
+			return false
+		}
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String || t.Elem().Kind() != reflect.String {
+			return false
+		}
+		f.StringToStringP(name, short, nil, usage)
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,218 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/pflag"
+)
+
+type testRegisterFlagsConfig struct {
+	Name    string            `koanf:"name" help:"name of the service"`
+	Port    int               `koanf:"port" default:"8080"`
+	Debug   bool              `koanf:"debug"`
+	Ratio   float64           `koanf:"ratio"`
+	Timeout time.Duration     `koanf:"timeout" default:"5s"`
+	Hosts   []string          `koanf:"hosts"`
+	IP      net.IP            `koanf:"ip"`
+	Labels  map[string]string `koanf:"labels"`
+	Counts  map[string]int    `koanf:"counts"`
+	DB      struct {
+		URL string `koanf:"url" flag:"db-url"`
+	} `koanf:"db"`
+}
+
+func TestRegisterFlags(t *testing.T) {
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	f.Bool("debug", true, "defined by hand")
+	if err := c.RegisterFlags(f, &testRegisterFlagsConfig{}); err != nil {
+		t.Fatalf("RegisterFlags err: got=%v want=nil", err)
+	}
+
+	var got []string
+	f.VisitAll(func(fl *pflag.Flag) {
+		got = append(got, fl.Name+" "+fl.Value.Type()+" "+fl.DefValue+" "+fl.Usage)
+	})
+	want := []string{
+		"db-url string  ",
+		"debug bool true defined by hand",
+		"hosts stringSlice [] ",
+		"ip ip <nil> ",
+		"labels stringToString [] ",
+		"name string  name of the service",
+		"port int 8080 ",
+		"ratio float64 0 ",
+		"timeout duration 5s ",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("RegisterFlags flags mismatch (-want +got):\n%s", diff)
+	}
+
+	args := []string{
+		"--name=app", "--ratio=0.5", "--hosts=a,b", "--ip=10.0.0.1",
+		"--labels=k=v", "--db-url=postgres://db", "--debug=false",
+	}
+	if err := f.Parse(args); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	var cfg testRegisterFlagsConfig
+	if err := c.Load(f, &cfg); err != nil {
+		t.Fatalf("Load err: got=%v want=nil", err)
+	}
+	wantCfg := testRegisterFlagsConfig{
+		Name:    "app",
+		Port:    8080,
+		Ratio:   0.5,
+		Timeout: 5 * time.Second,
+		Hosts:   []string{"a", "b"},
+		IP:      net.IPv4(10, 0, 0, 1),
+		Labels:  map[string]string{"k": "v"},
+	}
+	wantCfg.DB.URL = "postgres://db"
+	if diff := cmp.Diff(wantCfg, cfg); diff != "" {
+		t.Errorf("Load mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRegisterFlagsBadDefault(t *testing.T) {
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	cfg := struct {
+		Port int `koanf:"port" default:"x"`
+	}{}
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	if err := c.RegisterFlags(f, &cfg); err == nil {
+		t.Errorf("RegisterFlags err: got=nil want=error")
+	}
+}
+
+func TestRegisterFlagsShort(t *testing.T) {
+	type cfg struct {
+		Port    int  `koanf:"port" short:"p"`
+		Verbose bool `koanf:"verbose" short:"v"`
+	}
+	cases := []struct {
+		name    string
+		cfg     interface{}
+		defined string
+		args    []string
+		want    cfg
+		wantErr error
+	}{
+		{
+			name: "shorthands",
+			cfg:  &cfg{},
+			args: []string{"-p", "9090", "-v"},
+			want: cfg{Port: 9090, Verbose: true},
+		},
+		{
+			name: "too long",
+			cfg: &struct {
+				Port int `koanf:"port" short:"po"`
+			}{},
+			wantErr: BadShortFlagError,
+		},
+		{
+			name:    "used",
+			cfg:     &cfg{},
+			defined: "v",
+			wantErr: BadShortFlagError,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := New(testPrefix, testDelimiter)
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			if tc.defined != "" {
+				f.BoolP("version", tc.defined, false, "")
+			}
+			if err := c.RegisterFlags(f, tc.cfg); !errors.Is(err, tc.wantErr) {
+				t.Fatalf("RegisterFlags err: got=%v want=%v", err, tc.wantErr)
+			}
+			if tc.wantErr != nil {
+				return
+			}
+			if err := f.Parse(tc.args); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+			var got cfg
+			if err := c.Load(f, &got); err != nil {
+				t.Fatalf("Load err: got=%v want=nil", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Load mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRegisterFlagsHiddenDeprecated(t *testing.T) {
+	var cfg struct {
+		Name  string `koanf:"name" help:"name of the service"`
+		Debug bool   `koanf:"debug" hidden:"true"`
+		Host  string `koanf:"host" deprecated:"use --name instead"`
+	}
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	var out bytes.Buffer
+	f.SetOutput(&out)
+	if err := c.RegisterFlags(f, &cfg); err != nil {
+		t.Fatalf("RegisterFlags err: got=%v want=nil", err)
+	}
+	wantUsage := "      --name string   name of the service\n"
+	if diff := cmp.Diff(wantUsage, f.FlagUsages()); diff != "" {
+		t.Errorf("FlagUsages mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := f.Parse([]string{"--debug", "--host=h"}); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	wantOut := "Flag --host has been deprecated, use --name instead\n"
+	if diff := cmp.Diff(wantOut, out.String()); diff != "" {
+		t.Errorf("deprecation message mismatch (-want +got):\n%s", diff)
+	}
+	if err := c.Load(f, &cfg); err != nil {
+		t.Fatalf("Load err: got=%v want=nil", err)
+	}
+	if !cfg.Debug || cfg.Host != "h" {
+		t.Errorf("Load: got debug=%t host=%q want debug=true host=%q", cfg.Debug, cfg.Host, "h")
+	}
+}
@@ -0,0 +1,125 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"syscall"
+
+	"github.com/spf13/pflag"
+)
+
+// reloader re-runs Load with the inputs of an initial load and reports
+// changes. It is not safe for concurrent use.
+type reloader[T any] struct {
+	c        Config
+	f        *pflag.FlagSet
+	current  T
+	last     *loaded
+	onChange func(old, new T) error
+	// onReload, if set, is called after every accepted reload.
+	onReload func(next T, changes []KeyChange)
+}
+
+// newReloader loads cfg and returns a reloader that reports changes from it.
+func newReloader[T any](ctx context.Context, c Config, f *pflag.FlagSet, cfg *T, onChange func(old, new T) error) (*reloader[T], error) {
+	l, err := c.load(ctx, f, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &reloader[T]{c: c, f: f, current: *cfg, last: l, onChange: onChange}, nil
+}
+
+// reload runs Load again and calls onChange if the result differs from the
+// current value. If either fails, the error is logged and the current value is
+// kept.
+func (r *reloader[T]) reload(ctx context.Context) {
+	if err := r.next(ctx); err != nil {
+		r.c.logf("reload: %v", err)
+	}
+}
+
+// next is reload, but returns the error of Load or onChange.
+func (r *reloader[T]) next(ctx context.Context) error {
+	var next T
+	l, err := r.c.load(ctx, r.f, &next)
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(r.current, next) {
+		// Nothing changed, for example a file was touched or rewritten
+		// with the same content, so callbacks are not called.
+		r.last = l
+		return nil
+	}
+	if err := r.onChange(r.current, next); err != nil {
+		return fmt.Errorf("onChange: %w", err)
+	}
+	changes := keyChanges(r.last, l)
+	r.current, r.last = next, l
+	if r.c.onKeyChange != nil && len(changes) > 0 {
+		r.c.onKeyChange(changes)
+	}
+	if r.onReload != nil {
+		r.onReload(next, changes)
+	}
+	return nil
+}
+
+// ReloadOnSignal loads cfg and then runs the full Load again, with the same
+// flag set, config files and precedence, every time the process receives one
+// of sigs, or SIGHUP if none are given. onChange is called with the previous
+// and new values. If the reload or onChange fails, the error is logged and the
+// previous value is kept as the current one.
+//
+// ReloadOnSignal returns after the initial load. The signal handler is removed
+// when ctx is done. cfg is not modified after ReloadOnSignal returns.
+func ReloadOnSignal[T any](ctx context.Context, c Config, f *pflag.FlagSet, cfg *T, onChange func(old, new T) error, sigs ...os.Signal) error {
+	r, err := newReloader(ctx, c, f, cfg, onChange)
+	if err != nil {
+		return err
+	}
+	if len(sigs) == 0 {
+		sigs = []os.Signal{syscall.SIGHUP}
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+
+	go func() {
+		defer signal.Stop(ch)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ch:
+				r.reload(ctx)
+			}
+		}
+	}()
+	return nil
+}
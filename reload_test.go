@@ -0,0 +1,128 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build unix
+
+package goconfig
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+func TestReloadOnSignal(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "config.json")
+	writeTestFile(t, name, fmt.Sprintf(`{"%s": %d}`, testKey1, testValue1))
+
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	f.Int(testKey2, testDefaultValue2, testNoHelpMessage)
+	f.StringSlice(FileArgName, nil, testNoHelpMessage)
+	args := []string{
+		fmt.Sprintf("--%s=%s", FileArgName, name),
+		fmt.Sprintf("--%s=%d", testKey2, testValue3),
+	}
+	if err := f.Parse(args); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	changes := make(chan testConfig, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var cfg testConfig
+	err = ReloadOnSignal(ctx, c, f, &cfg, func(_, new testConfig) error {
+		changes <- new
+		return nil
+	}, syscall.SIGUSR1)
+	if err != nil {
+		t.Fatalf("ReloadOnSignal err: got=%v want=nil", err)
+	}
+
+	// The flag must still take precedence over the file after reloading.
+	writeTestFile(t, name, fmt.Sprintf(`{"%s": %d, "%s": %d}`, testKey1, testValue2, testKey2, testValue1))
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatalf("syscall.Kill failed unexpectedly: %v", err)
+	}
+
+	select {
+	case got := <-changes:
+		if got, want := got.Value1, testValue2; got != want {
+			t.Errorf("Value1: got=%d want=%d", got, want)
+		}
+		if got, want := got.Value2, testValue3; got != want {
+			t.Errorf("Value2: got=%d want=%d", got, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for reload")
+	}
+}
+
+func TestReloadSkipsUnchanged(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "config.json")
+	writeTestFile(t, name, fmt.Sprintf(`{"%s": %d}`, testKey1, testValue1))
+
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	f.StringSlice(FileArgName, nil, testNoHelpMessage)
+	if err := f.Parse([]string{fmt.Sprintf("--%s=%s", FileArgName, name)}); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+
+	var keyChanges int
+	c, err := New(testPrefix, testDelimiter, WithOnKeyChange(func([]KeyChange) { keyChanges++ }))
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	var calls int
+	var cfg testConfig
+	r, err := newReloader(context.Background(), c, f, &cfg, func(_, _ testConfig) error {
+		calls++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("newReloader err: got=%v want=nil", err)
+	}
+
+	// Rewriting the same values with different formatting is not a change.
+	writeTestFile(t, name, fmt.Sprintf(`{ "%s" : %d }`, testKey1, testValue1))
+	r.reload(context.Background())
+	if calls != 0 || keyChanges != 0 {
+		t.Errorf("unchanged reload: onChange calls=%d key change calls=%d want 0", calls, keyChanges)
+	}
+
+	writeTestFile(t, name, fmt.Sprintf(`{"%s": %d}`, testKey1, testValue2))
+	r.reload(context.Background())
+	if calls != 1 || keyChanges != 1 {
+		t.Errorf("changed reload: onChange calls=%d key change calls=%d want 1", calls, keyChanges)
+	}
+}
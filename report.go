@@ -0,0 +1,100 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// LoadReport summarizes what a Load used, for example to log a line at
+// startup.
+type LoadReport struct {
+	// Files lists the config files that were loaded, in order.
+	Files []string
+	// EnvVars lists the environment variables that supplied keys, sorted.
+	EnvVars []string
+	// ChangedFlags lists the flags set on the command line and
+	// DefaultedFlags those left at their defaults, sorted.
+	ChangedFlags   []string
+	DefaultedFlags []string
+	// Keys lists the configuration keys set by a source other than
+	// defaults, sorted.
+	Keys []string
+}
+
+// String returns the report as a single line.
+func (r *LoadReport) String() string {
+	return fmt.Sprintf("config: files=[%s] env=[%s] flags=[%s] keys=%d",
+		strings.Join(r.Files, ","), strings.Join(r.EnvVars, ","), strings.Join(r.ChangedFlags, ","), len(r.Keys))
+}
+
+// LoadWithReport is like Load, but also returns a LoadReport. The report is
+// returned even if Load fails, describing the sources read before the
+// failure.
+func (c Config) LoadWithReport(f *pflag.FlagSet, cfg interface{}) (*LoadReport, error) {
+	res, err := c.LoadWithResult(f, cfg)
+	return c.report(res.l, f), err
+}
+
+// report returns the LoadReport for l, loaded with the flags in f.
+func (c Config) report(l *loaded, f *pflag.FlagSet) *LoadReport {
+	rep := &LoadReport{}
+	for _, st := range l.status {
+		if st.State == SourceLoaded && l.files[st.Name] {
+			rep.Files = append(rep.Files, st.Name)
+		}
+	}
+
+	env := make(map[string]bool)
+	for _, spellings := range l.spellings {
+		for _, s := range spellings {
+			if s.Source == SourceEnv {
+				env[s.Raw] = true
+			}
+		}
+	}
+	for name := range env {
+		rep.EnvVars = append(rep.EnvVars, name)
+	}
+	sort.Strings(rep.EnvVars)
+
+	f.VisitAll(func(fl *pflag.Flag) {
+		if fl.Changed {
+			rep.ChangedFlags = append(rep.ChangedFlags, fl.Name)
+		} else {
+			rep.DefaultedFlags = append(rep.DefaultedFlags, fl.Name)
+		}
+	})
+
+	for key := range l.sources {
+		if l.supplied(f, key) && !c.controlKey(key) {
+			rep.Keys = append(rep.Keys, key)
+		}
+	}
+	sort.Strings(rep.Keys)
+	return rep
+}
@@ -0,0 +1,75 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/pflag"
+)
+
+func TestLoadWithReport(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "config.yaml")
+	writeTestFile(t, name, fmt.Sprintf("%s: %d\n", testKey1, testValue1))
+	t.Setenv(testPrefix+"VALUE2", fmt.Sprint(testValue2))
+
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	AddConfigFlag(f)
+	f.Int(testKey3, 0, testNoHelpMessage)
+	f.Int(testNestedTag+testDelimiter+testNestedKey, 0, testNoHelpMessage)
+	args := []string{
+		"--" + FileArgName + "=" + name,
+		"--" + FileArgName + "=?" + filepath.Join(dir, "missing.yaml"),
+		fmt.Sprintf("--%s=%d", testKey3, testValue3),
+	}
+	if err := f.Parse(args); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	var cfg testConfig
+	got, err := c.LoadWithReport(f, &cfg)
+	if err != nil {
+		t.Fatalf("LoadWithReport err: got=%v want=nil", err)
+	}
+	want := &LoadReport{
+		Files:          []string{name},
+		EnvVars:        []string{testPrefix + "VALUE2"},
+		ChangedFlags:   []string{FileArgName, testKey3},
+		DefaultedFlags: []string{testNestedTag + testDelimiter + testNestedKey},
+		Keys:           []string{testKey1, testKey2, testKey3},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("LoadWithReport mismatch (-want +got):\n%s", diff)
+	}
+	wantLine := fmt.Sprintf("config: files=[%s] env=[%sVALUE2] flags=[%s,%s] keys=3", name, testPrefix, FileArgName, testKey3)
+	if got := got.String(); got != wantLine {
+		t.Errorf("String: got=%q want=%q", got, wantLine)
+	}
+}
@@ -0,0 +1,66 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+
+	"github.com/spf13/pflag"
+)
+
+var (
+	NoSourceError = errors.New("no configuration source provided any value")
+)
+
+// WithRequireAnySource makes Load fail with NoSourceError when no config file,
+// Source, environment variable or explicitly set flag supplied any value, so
+// that a service started without its configuration does not silently run on
+// defaults. Default tags, flag defaults, the compiled overlay and metadata do
+// not count.
+func WithRequireAnySource() Option {
+	return func(c *Config) {
+		c.requireAnySource = true
+	}
+}
+
+// anySource reports whether any layer other than defaults, the compiled
+// overlay and metadata supplied a value. Naming config files that contain no
+// values does not count.
+func (c Config) anySource(l *loaded, f *pflag.FlagSet) bool {
+	for _, st := range l.status {
+		switch st.Name {
+		case SourceDefaults, SourceFlags, SourceCompiled, SourceMetadata:
+			continue
+		}
+		if st.State == SourceLoaded && st.Keys > 0 {
+			return true
+		}
+	}
+	changed := false
+	f.Visit(func(fl *pflag.Flag) {
+		if !c.controlKey(fl.Name) {
+			changed = true
+		}
+	})
+	return changed
+}
@@ -0,0 +1,95 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestRequireAnySource(t *testing.T) {
+	cases := []struct {
+		name    string
+		args    []string
+		env     bool
+		wantErr bool
+	}{
+		{
+			name:    "defaults only",
+			wantErr: true,
+		},
+		{
+			name: "config file",
+			args: []string{fmt.Sprintf("--%s=%s", FileArgName, testFileName(testGoodJSONConfig))},
+		},
+		{
+			name:    "empty config file",
+			args:    []string{fmt.Sprintf("--%s=%s", FileArgName, testFileName("empty.json"))},
+			wantErr: true,
+		},
+		{
+			name: "flag",
+			args: []string{fmt.Sprintf("--%s=%d", testKey1, testValue1)},
+		},
+		{
+			name: "env",
+			env:  true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.env {
+				t.Setenv(strings.ToUpper(testPrefix+testKey2), strconv.Itoa(testValue2))
+			}
+
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			f.Int(testKey1, testDefaultValue1, testNoHelpMessage)
+			f.StringSlice(FileArgName, nil, testNoHelpMessage)
+			if err := f.Parse(tc.args); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+
+			c, err := New(testPrefix, testDelimiter, WithRequireAnySource())
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+
+			var cfg testConfig
+			err = c.Load(f, &cfg)
+			if tc.wantErr {
+				if !errors.Is(err, NoSourceError) {
+					t.Errorf("Load err: got=%v want=%v", err, NoSourceError)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Load err: got=%v want=nil", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+var (
+	MissingRequiredError = errors.New("required keys not set")
+)
+
+// requiredOption is the koanf tag option that marks a field as required, as in
+// `koanf:"port,required"`.
+const requiredOption = "required"
+
+// checkRequired returns an error listing every field of cfg tagged as required
+// whose key no layer supplied. Default tags and flag defaults do not count as
+// supplying a value.
+func (c Config) checkRequired(l *loaded, f *pflag.FlagSet, cfg interface{}) error {
+	v, err := structValue(cfg)
+	if err != nil {
+		return err
+	}
+	var missing []string
+	err = walkFields(v, "", c.delimiter, func(key string, sf reflect.StructField, _ reflect.Value) error {
+		if hasTagOption(sf, requiredOption) && !l.supplied(f, key) {
+			missing = append(missing, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		keys := strings.Join(missing, ", ")
+		return newMessageError(MsgMissingRequired, map[string]string{"keys": keys},
+			fmt.Errorf("Load %s: %w", keys, MissingRequiredError))
+	}
+	return nil
+}
+
+// supplied reports whether a layer other than defaults supplied key, or a key
+// below it, such as an entry of a map or slice field.
+func (l *loaded) supplied(f *pflag.FlagSet, key string) bool {
+	if l.suppliedLeaf(f, key) {
+		return true
+	}
+	prefix := key + l.k.Delim()
+	for k := range l.sources {
+		if strings.HasPrefix(k, prefix) && l.suppliedLeaf(f, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// suppliedLeaf reports whether a layer other than defaults supplied key
+// itself.
+func (l *loaded) suppliedLeaf(f *pflag.FlagSet, key string) bool {
+	source, ok := l.sources[key]
+	if !ok {
+		return false
+	}
+	switch source {
+	case SourceDefaults:
+		return false
+	case SourceFlags:
+		return f.Changed(l.flagName(key))
+	}
+	return true
+}
@@ -0,0 +1,182 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+type testRequiredConfig struct {
+	Value1 int `koanf:"value1,required"`
+	Value2 int `koanf:"value2,required"`
+	Value3 int `koanf:"value3"`
+}
+
+func TestLoadRequired(t *testing.T) {
+	cases := []struct {
+		name        string
+		args        []string
+		env         bool
+		wantMissing []string
+	}{
+		{
+			name:        "nothing set",
+			wantMissing: []string{testKey1, testKey2},
+		},
+		{
+			name:        "flag default does not count",
+			args:        []string{fmt.Sprintf("--%s=%d", testKey3, testValue3)},
+			wantMissing: []string{testKey1, testKey2},
+		},
+		{
+			name:        "flag set",
+			args:        []string{fmt.Sprintf("--%s=%d", testKey1, testValue1)},
+			wantMissing: []string{testKey2},
+		},
+		{
+			name: "flag and env set",
+			args: []string{fmt.Sprintf("--%s=%d", testKey1, testValue1)},
+			env:  true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.env {
+				t.Setenv(strings.ToUpper(testPrefix+testKey2), strconv.Itoa(testValue2))
+			}
+
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			f.Int(testKey1, testDefaultValue1, testNoHelpMessage)
+			f.Int(testKey3, testDefaultValue3, testNoHelpMessage)
+			if err := f.Parse(tc.args); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+
+			c, err := New(testPrefix, testDelimiter)
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+
+			var cfg testRequiredConfig
+			err = c.Load(f, &cfg)
+			if tc.wantMissing == nil {
+				if err != nil {
+					t.Errorf("Load err: got=%v want=nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, MissingRequiredError) {
+				t.Fatalf("Load err: got=%v want=%v", err, MissingRequiredError)
+			}
+			if want := strings.Join(tc.wantMissing, ", "); !strings.Contains(err.Error(), want) {
+				t.Errorf("Load err: got=%q want keys %q", err, want)
+			}
+		})
+	}
+}
+
+type testRecursiveConfig struct {
+	Name string               `koanf:"name,required"`
+	Next *testRecursiveConfig `koanf:"next"`
+}
+
+func TestLoadRecursiveType(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "config.json")
+	writeTestFile(t, name, `{"name": "a", "next": {"name": "b"}}`)
+
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	f.StringSlice(FileArgName, nil, testNoHelpMessage)
+	if err := f.Parse([]string{fmt.Sprintf("--%s=%s", FileArgName, name)}); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	c, err := New(testPrefix, testDelimiter, WithStrict())
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	var cfg testRecursiveConfig
+	if err := c.Load(f, &cfg); err != nil {
+		t.Fatalf("Load err: got=%v want=nil", err)
+	}
+	if cfg.Name != "a" || cfg.Next == nil || cfg.Next.Name != "b" {
+		t.Errorf("Load: got=%+v want name a and next name b", cfg)
+	}
+}
+
+type testRequiredCollectionConfig struct {
+	Labels map[string]string `koanf:"labels,required"`
+	Hosts  []string          `koanf:"hosts,required"`
+}
+
+func TestLoadRequiredCollections(t *testing.T) {
+	cases := []struct {
+		name        string
+		contents    string
+		wantMissing string
+	}{
+		{
+			name:     "supplied by file",
+			contents: `{"labels": {"team": "a"}, "hosts": ["h1", "h2"]}`,
+		},
+		{
+			name:        "map missing",
+			contents:    `{"hosts": ["h1"]}`,
+			wantMissing: "labels",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			name := filepath.Join(t.TempDir(), "config.json")
+			writeTestFile(t, name, tc.contents)
+
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			f.StringSlice(FileArgName, nil, testNoHelpMessage)
+			if err := f.Parse([]string{fmt.Sprintf("--%s=%s", FileArgName, name)}); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+			c, err := New(testPrefix, testDelimiter)
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+
+			var cfg testRequiredCollectionConfig
+			err = c.Load(f, &cfg)
+			if tc.wantMissing == "" {
+				if err != nil {
+					t.Errorf("Load err: got=%v want=nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, MissingRequiredError) || !strings.Contains(err.Error(), tc.wantMissing) {
+				t.Errorf("Load err: got=%v want %v for %q", err, MissingRequiredError, tc.wantMissing)
+			}
+		})
+	}
+}
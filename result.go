@@ -0,0 +1,102 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"context"
+	"time"
+
+	"github.com/knadh/koanf/v2"
+	"github.com/spf13/pflag"
+)
+
+// SourceState describes what happened to a source during Load.
+type SourceState string
+
+// States of a source.
+const (
+	// SourceLoaded means the source was read and merged.
+	SourceLoaded SourceState = "loaded"
+	// SourceSkipped means the source was not read because an earlier one
+	// failed.
+	SourceSkipped SourceState = "skipped"
+	// SourceFailed means reading or merging the source failed.
+	SourceFailed SourceState = "failed"
+	// SourceMissing means an optional config file does not exist.
+	SourceMissing SourceState = "missing"
+)
+
+// SourceStatus describes a single source of configuration during Load.
+type SourceStatus struct {
+	// Name is the config file, Source name or one of SourceDefaults,
+	// SourceEnv, SourceFlags, SourceCompiled and SourceMetadata.
+	Name  string
+	State SourceState
+	// Duration is how long reading and merging the source took.
+	Duration time.Duration
+	// Keys is the number of keys the source supplied.
+	Keys int
+	// Err is the error if State is SourceFailed.
+	Err error
+}
+
+// Result describes a completed Load.
+type Result struct {
+	l *loaded
+	// controlKey reports whether a key controls Load, such as the config
+	// file flag, rather than being configuration.
+	controlKey func(key string) bool
+}
+
+// SourceStatus returns the status of every source, in the order they were
+// loaded.
+func (r *Result) SourceStatus() []SourceStatus {
+	return append([]SourceStatus(nil), r.l.status...)
+}
+
+// Timings returns the time taken by each stage of Load in the order they ran.
+// It is empty unless WithTiming was used.
+func (r *Result) Timings() []StageTiming {
+	if r.l.timings == nil {
+		return nil
+	}
+	return append([]StageTiming(nil), *r.l.timings...)
+}
+
+// LoadWithResult is like Load, but also returns a Result describing the load.
+// The Result is returned even if Load fails, so that it can be logged.
+func (c Config) LoadWithResult(f *pflag.FlagSet, cfg interface{}) (*Result, error) {
+	l, err := c.load(context.Background(), f, cfg)
+	if err == nil {
+		err = c.printConfigIfRequested(f, cfg)
+	}
+	return &Result{l: l, controlKey: c.controlKey}, err
+}
+
+// Koanf returns the koanf instance that Load merged every source into, for
+// features the package does not provide. It holds the values before they were
+// unmarshaled, including keys that control Load, such as the config file
+// flag. Changing it does not change the configuration struct.
+func (r *Result) Koanf() *koanf.Koanf {
+	return r.l.k
+}
@@ -0,0 +1,122 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/spf13/pflag"
+)
+
+func TestResultSourceStatus(t *testing.T) {
+	good := testFileName(testGoodJSONConfig)
+
+	cases := []struct {
+		name    string
+		files   []string
+		want    []SourceStatus
+		wantErr bool
+	}{
+		{
+			name:  "all loaded",
+			files: []string{good},
+			want: []SourceStatus{
+				{Name: good, State: SourceLoaded, Keys: 2},
+				{Name: SourceEnv, State: SourceLoaded},
+				{Name: SourceFlags, State: SourceLoaded, Keys: 1},
+			},
+		},
+		{
+			name:  "failure skips remaining sources",
+			files: []string{good, testBadFileName},
+			want: []SourceStatus{
+				{Name: good, State: SourceLoaded, Keys: 2},
+				{Name: testBadFileName, State: SourceFailed},
+				{Name: SourceEnv, State: SourceSkipped},
+				{Name: SourceFlags, State: SourceSkipped},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			f.StringSlice(FileArgName, nil, testNoHelpMessage)
+			var args []string
+			for _, file := range tc.files {
+				args = append(args, fmt.Sprintf("--%s=%s", FileArgName, file))
+			}
+			if err := f.Parse(args); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+
+			c, err := New(testPrefix, testDelimiter)
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+
+			var cfg testConfig
+			r, err := c.LoadWithResult(f, &cfg)
+			if got, want := err != nil, tc.wantErr; got != want {
+				t.Errorf("LoadWithResult err: got=%v wantErr=%v", err, want)
+			}
+			got := r.SourceStatus()
+			for i := range got {
+				if got[i].State == SourceFailed && got[i].Err == nil {
+					t.Errorf("SourceStatus[%d] Err: got=nil want=non-nil", i)
+				}
+			}
+			opts := cmpopts.IgnoreFields(SourceStatus{}, "Duration", "Err")
+			if diff := cmp.Diff(tc.want, got, opts); diff != "" {
+				t.Errorf("SourceStatus mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestResultKoanf(t *testing.T) {
+	t.Setenv(testPrefix+"VALUE1", "7")
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	if err := f.Parse(nil); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	var cfg testConfig
+	r, err := c.LoadWithResult(f, &cfg)
+	if err != nil {
+		t.Fatalf("LoadWithResult err: got=%v want=nil", err)
+	}
+	k := r.Koanf()
+	if got := k.Int("value1"); got != 7 {
+		t.Errorf("Koanf Int(value1): got=%d want=%d", got, 7)
+	}
+	if got := k.Delim(); got != testDelimiter {
+		t.Errorf("Koanf Delim: got=%q want=%q", got, testDelimiter)
+	}
+}
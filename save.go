@@ -0,0 +1,83 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// WithWriteOptions sets how Save replaces config files, for example to keep
+// backups of their previous contents.
+func WithWriteOptions(o WriteOptions) Option {
+	return func(c *Config) {
+		c.writeOptions = o
+	}
+}
+
+// Save writes cfg to the config file path in format, so that settings changed
+// at runtime, for example by a "config set" command, are used by the next
+// Load. If format is empty, it is detected from the name of path as Load
+// does. YAML, TOML and JSON are supported; other formats fail with
+// UnknownFormatError.
+//
+// Values are written as Dump writes them, except that secret fields keep their
+// values. An existing YAML file is updated as UpdateFile updates it, so its
+// comments, key order and keys that are not fields of cfg are kept, and nil
+// fields are removed. TOML and JSON files are written in full, so the comments
+// of an existing TOML file are lost. The file is replaced using WriteFile with
+// the options set by WithWriteOptions.
+func (c Config) Save(cfg interface{}, path, format string) error {
+	if format == "" {
+		format = detectFormat(path)
+	}
+	b, err := c.save(cfg, path, format)
+	if err != nil {
+		return fmt.Errorf("Save %s: %w", path, err)
+	}
+	return WriteFile(path, b, c.writeOptions)
+}
+
+// save returns the new contents of the config file path for Save.
+func (c Config) save(cfg interface{}, path, format string) ([]byte, error) {
+	switch strings.ToLower(format) {
+	case FormatYAML, "yml":
+	default:
+		return c.encode("Save", cfg, format, false)
+	}
+	src, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c.encode("Save", cfg, format, false)
+	}
+	if err != nil {
+		return nil, err
+	}
+	flat, err := c.dumpValues("Save", cfg, false)
+	if err != nil {
+		return nil, err
+	}
+	return c.updateYAML(src, flat)
+}
@@ -0,0 +1,214 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// jsonSchemaDraft identifies the JSON Schema dialect produced by the package.
+const jsonSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// schema is a JSON Schema describing a configuration value.
+type schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+	Properties           map[string]*schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *schema            `json:"items,omitempty"`
+	AdditionalProperties *schema            `json:"additionalProperties,omitempty"`
+}
+
+// typeSchema returns the schema for values of type t.
+func typeSchema(t reflect.Type) *schema {
+	return expandSchema(t, make(map[reflect.Type]bool))
+}
+
+// expandSchema is typeSchema, where expanding holds the struct types being
+// expanded. A struct type that contains itself is described by an empty
+// schema where it recurs.
+func expandSchema(t reflect.Type, expanding map[reflect.Type]bool) *schema {
+	if isNested(t) {
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if expanding[t] {
+			return &schema{}
+		}
+		expanding[t] = true
+		defer delete(expanding, t)
+		s := &schema{
+			Type:       "object",
+			Properties: make(map[string]*schema),
+		}
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if name, ok := fieldName(sf); ok {
+				s.Properties[name] = fieldSchema(sf, expanding)
+				if hasTagOption(sf, requiredOption) {
+					s.Required = append(s.Required, name)
+				}
+			}
+		}
+		return s
+	}
+
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == reflect.TypeOf(time.Duration(0)) || isTextUnmarshaler(t) {
+		return &schema{Type: "string"}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &schema{Type: "number"}
+	case reflect.String:
+		return &schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &schema{Type: "array", Items: expandSchema(t.Elem(), expanding)}
+	case reflect.Map:
+		return &schema{Type: "object", AdditionalProperties: expandSchema(t.Elem(), expanding)}
+	default:
+		return &schema{}
+	}
+}
+
+// fieldSchema returns the schema for the struct field sf, described by its
+// help, oneof and default tags. Secret fields have no default.
+func fieldSchema(sf reflect.StructField, expanding map[reflect.Type]bool) *schema {
+	s := expandSchema(sf.Type, expanding)
+	s.Description = sf.Tag.Get(helpTagName)
+	if tag, ok := sf.Tag.Lookup(oneofTagName); ok {
+		values := s
+		if s.Items != nil {
+			values = s.Items
+		}
+		for _, v := range strings.Split(tag, ",") {
+			values.Enum = append(values.Enum, schemaValue(values.Type, v))
+		}
+	}
+	if d, ok := sf.Tag.Lookup(defaultTagName); ok && !isSecret(sf) {
+		if s.Type == "array" && s.Items != nil {
+			var items []interface{}
+			for _, v := range strings.Split(d, ",") {
+				items = append(items, schemaValue(s.Items.Type, v))
+			}
+			s.Default = items
+		} else {
+			s.Default = schemaValue(s.Type, d)
+		}
+	}
+	return s
+}
+
+// schemaValue returns the text of a tag as a value of the schema type typ, or
+// the text itself if it is not valid for typ.
+func schemaValue(typ, text string) interface{} {
+	switch typ {
+	case "boolean", "integer", "number":
+		var v interface{}
+		if err := yaml.Unmarshal([]byte(text), &v); err == nil {
+			return v
+		}
+	}
+	return text
+}
+
+// rootSchema returns the schema for the configuration struct cfg.
+func rootSchema(cfg interface{}) (*schema, error) {
+	v, err := structValue(cfg)
+	if err != nil {
+		return nil, err
+	}
+	s := typeSchema(v.Type())
+	s.Schema = jsonSchemaDraft
+	return s, nil
+}
+
+// JSONSchema returns a JSON Schema document describing cfg, so that editors and
+// CI can check config files before they are deployed. Fields are described by
+// their help tags, the allowed values of their oneof tags, their default tags
+// and the values set by WithDefaults, and are required if their koanf tag has
+// the required option.
+func (c Config) JSONSchema(cfg interface{}) ([]byte, error) {
+	s, err := rootSchema(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("JSONSchema: %w", err)
+	}
+	if err := c.defaultSchemas(s, cfg); err != nil {
+		return nil, fmt.Errorf("JSONSchema defaults: %w", err)
+	}
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("JSONSchema marshal: %w", err)
+	}
+	return append(b, '\n'), nil
+}
+
+// defaultSchemas sets the default of every field of s that has no default tag
+// to its non-zero value set by WithDefaults.
+func (c Config) defaultSchemas(s *schema, cfg interface{}) error {
+	if c.defaults == nil {
+		return nil
+	}
+	defaults, err := c.flatten(c.defaults)
+	if err != nil {
+		return err
+	}
+	v, err := structValue(cfg)
+	if err != nil {
+		return err
+	}
+	return walkFields(v, "", c.delimiter, func(key string, sf reflect.StructField, _ reflect.Value) error {
+		d, ok := defaults[key]
+		if !ok || isSecret(sf) || d == nil || reflect.ValueOf(d).IsZero() {
+			return nil
+		}
+		fs := s
+		for _, name := range strings.Split(key, c.delimiter) {
+			if fs = fs.Properties[name]; fs == nil {
+				return nil
+			}
+		}
+		if fs.Default == nil {
+			if fs.Default, err = dumpValue(d); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,126 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type testSchemaConfig struct {
+	Name    string        `koanf:"name,required" help:"name of the service"`
+	Level   string        `koanf:"level" default:"info" oneof:"debug,info"`
+	Port    int           `koanf:"port" default:"8080"`
+	Ports   []int         `koanf:"ports" oneof:"80,443"`
+	Timeout time.Duration `koanf:"timeout"`
+	Token   string        `koanf:"token,secret" default:"abc"`
+}
+
+func TestJSONSchema(t *testing.T) {
+	c, err := New(testPrefix, testDelimiter, WithDefaults(testSchemaConfig{Timeout: 5 * time.Second}))
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	got, err := c.JSONSchema(testSchemaConfig{})
+	if err != nil {
+		t.Fatalf("JSONSchema err: got=%v want=nil", err)
+	}
+	want := `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "properties": {
+    "level": {
+      "type": "string",
+      "enum": [
+        "debug",
+        "info"
+      ],
+      "default": "info"
+    },
+    "name": {
+      "type": "string",
+      "description": "name of the service"
+    },
+    "port": {
+      "type": "integer",
+      "default": 8080
+    },
+    "ports": {
+      "type": "array",
+      "items": {
+        "type": "integer",
+        "enum": [
+          80,
+          443
+        ]
+      }
+    },
+    "timeout": {
+      "type": "string",
+      "default": "5s"
+    },
+    "token": {
+      "type": "string"
+    }
+  },
+  "required": [
+    "name"
+  ]
+}
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("JSONSchema mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestJSONSchemaRecursive(t *testing.T) {
+	c, err := New(testPrefix, testDelimiter, WithDefaults(testRecursiveConfig{Name: "a"}))
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	got, err := c.JSONSchema(testRecursiveConfig{})
+	if err != nil {
+		t.Fatalf("JSONSchema err: got=%v want=nil", err)
+	}
+	want := `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "properties": {
+    "name": {
+      "type": "string",
+      "default": "a"
+    },
+    "next": {}
+  },
+  "required": [
+    "name"
+  ]
+}
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("JSONSchema mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,76 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// search configures the config file search of WithSearchPaths.
+type search struct {
+	app  string
+	file string
+}
+
+// WithSearchPaths makes Load look for config files when none are named by the
+// file flag or its environment variable, unless the FileList of the file flag
+// was cleared with --config=. It loads, in this order so that later
+// files override earlier ones, whichever of these exist:
+//
+//	/etc/<app>/<file>
+//	$XDG_CONFIG_HOME/<app>/<file>, see os.UserConfigDir
+//	<file> in the working directory, or the base directory of WithBaseDir
+//
+// file defaults to <app>.yaml if empty.
+func WithSearchPaths(app, file string) Option {
+	return func(c *Config) {
+		if file == "" {
+			file = app + ".yaml"
+		}
+		c.search = &search{app: app, file: file}
+	}
+}
+
+// SearchPaths returns the config files WithSearchPaths looks for, in the order
+// they are loaded, for example to list them in help text. It returns nil if
+// WithSearchPaths was not used.
+func (c Config) SearchPaths() []string {
+	if c.search == nil {
+		return nil
+	}
+	paths := []string{filepath.Join(string(filepath.Separator), "etc", c.search.app, c.search.file)}
+	if dir, err := os.UserConfigDir(); err == nil {
+		paths = append(paths, filepath.Join(dir, c.search.app, c.search.file))
+	}
+	return append(paths, c.search.file)
+}
+
+// searchFiles returns the optional config files found by WithSearchPaths.
+func (c Config) searchFiles() []string {
+	var names []string
+	for _, p := range c.SearchPaths() {
+		names = append(names, optionalFilePrefix+p)
+	}
+	return names
+}
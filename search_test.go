@@ -0,0 +1,105 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/pflag"
+)
+
+func TestWithSearchPaths(t *testing.T) {
+	const app = "goconfigtestapp"
+	dir := t.TempDir()
+	xdg := filepath.Join(dir, "xdg")
+	cwd := filepath.Join(dir, "cwd")
+	for _, d := range []string{filepath.Join(xdg, app), cwd} {
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			t.Fatalf("os.MkdirAll failed unexpectedly: %v", err)
+		}
+	}
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	good, err := filepath.Abs(testFileName(testGoodJSONConfig))
+	if err != nil {
+		t.Fatalf("filepath.Abs failed unexpectedly: %v", err)
+	}
+	writeTestFile(t, filepath.Join(xdg, app, app+".yaml"), fmt.Sprintf("%s: 1\n%s: 1\n", testKey1, testKey2))
+	writeTestFile(t, filepath.Join(cwd, app+".yaml"), fmt.Sprintf("%s: %d\n", testKey2, testValue2))
+
+	cases := []struct {
+		name string
+		args []string
+		want testConfig
+	}{
+		{
+			name: "search",
+			want: testConfig{Value1: 1, Value2: testValue2},
+		},
+		{
+			name: "config flag disables search",
+			args: []string{"--" + FileArgName + "=" + good},
+			want: testConfig{Value1: testValue1, Nested: testConfig1{NestedVal: testValue2}},
+		},
+		{
+			name: "cleared config flag disables search",
+			args: []string{"--" + FileArgName + "="},
+			want: testConfig{},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			AddConfigFlag(f)
+			if err := f.Parse(tc.args); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+			c, err := New(testPrefix, testDelimiter, WithSearchPaths(app, ""), WithBaseDir(cwd))
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+			var got testConfig
+			if err := c.Load(f, &got); err != nil {
+				t.Fatalf("Load err: got=%v want=nil", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Load mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSearchPaths(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/home/user/.config")
+	c, err := New(testPrefix, testDelimiter, WithSearchPaths("app", "config.json"))
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	want := []string{"/etc/app/config.json", "/home/user/.config/app/config.json", "config.json"}
+	if diff := cmp.Diff(want, c.SearchPaths()); diff != "" {
+		t.Errorf("SearchPaths mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,125 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var (
+	SecretFileConflictError = errors.New("variable and its secret file are both set")
+)
+
+// secretFileTagName is the struct tag that names an environment variable
+// holding the path of a file that contains the value of a field, as in
+// `secretfile:"DB_PASSWORD_FILE"`.
+const secretFileTagName = "secretfile"
+
+// secretFileSuffix is appended to the environment variable of a field to name
+// the variable holding the path of its secret file.
+const secretFileSuffix = "_FILE"
+
+// WithSecretFiles makes Load follow the Docker secrets convention, where the
+// environment variable of a field followed by _FILE names a file holding its
+// value:
+//
+//	$ export APP_DB_PASSWORD_FILE=/run/secrets/db_password
+//
+// A field can name the variable itself with a secretfile tag, which does not
+// require WithSecretFiles. The trailing newline of the file is removed.
+// Setting both the variable of a field and its secret file fails Load with
+// SecretFileConflictError. A variable ending in _FILE that itself sets a field,
+// such as APP_LOG_FILE for a field log_file, is not treated as a secret file.
+func WithSecretFiles() Option {
+	return func(c *Config) {
+		c.secretFiles = true
+	}
+}
+
+// secretFileVar names the environment variable holding the path of the
+// secret file of a field, and the variable that sets the field directly.
+type secretFileVar struct {
+	name string
+	env  string
+}
+
+// secretFileVars returns the environment variables naming the secret files of
+// the fields of cfg, keyed by their delimited keys.
+func (c Config) secretFileVars(cfg interface{}) (map[string]secretFileVar, error) {
+	v, err := structValue(cfg)
+	if err != nil {
+		return nil, err
+	}
+	direct := make(map[string]bool)
+	vars := make(map[string]secretFileVar)
+	err = walkFields(v, "", c.delimiter, func(key string, sf reflect.StructField, _ reflect.Value) error {
+		env := c.fieldEnvName(key, sf)
+		direct[env] = true
+		switch name := sf.Tag.Get(secretFileTagName); {
+		case name != "":
+			vars[key] = secretFileVar{name: name, env: env}
+		case c.secretFiles:
+			vars[key] = secretFileVar{name: env + secretFileSuffix, env: env}
+		}
+		return nil
+	})
+	for key, sv := range vars {
+		if direct[sv.name] {
+			delete(vars, key)
+		}
+	}
+	return vars, err
+}
+
+// readSecretFiles returns the values read from the secret files named by vars,
+// keyed by their delimited keys.
+func readSecretFiles(vars map[string]secretFileVar) (map[string]interface{}, error) {
+	keys := make([]string, 0, len(vars))
+	for key := range vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	values := make(map[string]interface{})
+	for _, key := range keys {
+		sv := vars[key]
+		name, ok := os.LookupEnv(sv.name)
+		if !ok {
+			continue
+		}
+		if _, ok := os.LookupEnv(sv.env); ok {
+			return nil, fmt.Errorf("%s and %s: %w", sv.env, sv.name, SecretFileConflictError)
+		}
+		b, err := os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", sv.name, err)
+		}
+		s := strings.TrimSuffix(string(b), "\n")
+		values[key] = strings.TrimSuffix(s, "\r")
+	}
+	return values, nil
+}
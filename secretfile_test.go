@@ -0,0 +1,132 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/pflag"
+)
+
+type testSecretFileConfig struct {
+	DB struct {
+		Password string `koanf:"password"`
+		Token    string `koanf:"token" secretfile:"TEST_SECRETFILE_TOKEN_FILE"`
+	} `koanf:"db"`
+	Log     string `koanf:"log"`
+	LogFile string `koanf:"log_file"`
+}
+
+func TestSecretFiles(t *testing.T) {
+	dir := t.TempDir()
+	password := filepath.Join(dir, "password")
+	token := filepath.Join(dir, "token")
+	writeTestFile(t, password, "hunter2\n")
+	writeTestFile(t, token, "abc")
+
+	cases := []struct {
+		name    string
+		opts    []Option
+		env     map[string]string
+		want    testSecretFileConfig
+		wantErr error
+	}{
+		{
+			name: "suffix",
+			opts: []Option{WithSecretFiles()},
+			env: map[string]string{
+				testPrefix + "DB_PASSWORD_FILE": password,
+			},
+			want: func() (cfg testSecretFileConfig) {
+				cfg.DB.Password = "hunter2"
+				return cfg
+			}(),
+		},
+		{
+			name: "field ending in file",
+			opts: []Option{WithSecretFiles(), WithEnvSeparator("__")},
+			env: map[string]string{
+				testPrefix + "LOG_FILE": "/var/log/app",
+			},
+			want: testSecretFileConfig{LogFile: "/var/log/app"},
+		},
+		{
+			name: "tag without option",
+			env: map[string]string{
+				"TEST_SECRETFILE_TOKEN_FILE": token,
+			},
+			want: func() (cfg testSecretFileConfig) {
+				cfg.DB.Token = "abc"
+				return cfg
+			}(),
+		},
+		{
+			name: "conflict",
+			opts: []Option{WithSecretFiles()},
+			env: map[string]string{
+				testPrefix + "DB_PASSWORD":      "direct",
+				testPrefix + "DB_PASSWORD_FILE": password,
+			},
+			wantErr: SecretFileConflictError,
+		},
+		{
+			name: "missing file",
+			opts: []Option{WithSecretFiles()},
+			env: map[string]string{
+				testPrefix + "DB_PASSWORD_FILE": filepath.Join(dir, "missing"),
+			},
+			wantErr: fs.ErrNotExist,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			if err := f.Parse(nil); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+			c, err := New(testPrefix, testDelimiter, tc.opts...)
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+			var got testSecretFileConfig
+			err = c.Load(f, &got)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Load err: got=%v want=%v", err, tc.wantErr)
+			}
+			var ee *EnvError
+			if tc.wantErr != nil && !errors.As(err, &ee) {
+				t.Errorf("Load err: got=%T want=*EnvError", err)
+			}
+			if diff := cmp.Diff(tc.want, got); err == nil && diff != "" {
+				t.Errorf("Load mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+)
+
+// SecretWarning describes a field that is not tagged secret but whose value
+// looks like a credential.
+type SecretWarning struct {
+	Key    string
+	Reason string
+}
+
+func (w SecretWarning) String() string {
+	return fmt.Sprintf("%s: %s", w.Key, w.Reason)
+}
+
+// secretPatterns match well known credential formats.
+var secretPatterns = []struct {
+	reason  string
+	pattern *regexp.Regexp
+}{
+	{"AWS access key", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"GitHub token", regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`)},
+	{"Slack token", regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`)},
+	{"Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{"Stripe key", regexp.MustCompile(`\b[sr]k_live_[0-9A-Za-z]{16,}\b`)},
+	{"private key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)},
+	{"JSON web token", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)},
+	{"URL with password", regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^/\s:@]+:[^/\s@]+@`)},
+}
+
+// Values at least entropyMinLength long without whitespace and with at least
+// entropyThreshold bits of entropy per character are reported as likely
+// random tokens.
+const (
+	entropyMinLength = 20
+	entropyThreshold = 4.0
+)
+
+var tokenPattern = regexp.MustCompile(`^\S+$`)
+
+// ScanSecrets returns a warning for every string field in cfg that is not
+// tagged secret:"true" but whose value matches a known credential format or
+// looks like a random token. Detection is best effort, so it is meant to catch
+// fields that are missing the tag, not to prove the configuration is safe.
+func (c Config) ScanSecrets(cfg interface{}) ([]SecretWarning, error) {
+	v, err := structValue(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("ScanSecrets: %w", err)
+	}
+
+	var warnings []SecretWarning
+	err = walkFields(v, "", c.delimiter, func(key string, sf reflect.StructField, v reflect.Value) error {
+		if isSecret(sf) {
+			return nil
+		}
+		for _, s := range stringValues(v) {
+			if reason, ok := detectSecret(s); ok {
+				warnings = append(warnings, SecretWarning{Key: key, Reason: reason})
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ScanSecrets: %w", err)
+	}
+	sort.Slice(warnings, func(i, j int) bool { return warnings[i].Key < warnings[j].Key })
+	return warnings, nil
+}
+
+// warnSecrets logs the warnings returned by ScanSecrets for cfg. It is used
+// before configuration is written anywhere.
+func (c Config) warnSecrets(op string, cfg interface{}) {
+	warnings, err := c.ScanSecrets(cfg)
+	if err != nil {
+		return
+	}
+	for _, w := range warnings {
+		c.logf("%s: %s looks like a credential (%s) but is not tagged secret", op, w.Key, w.Reason)
+	}
+}
+
+// stringValues returns the strings held by v, which may be a string or a
+// slice, array or map of strings.
+func stringValues(v reflect.Value) []string {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return []string{v.String()}
+	case reflect.Slice, reflect.Array:
+		var ss []string
+		for i := 0; i < v.Len(); i++ {
+			ss = append(ss, stringValues(v.Index(i))...)
+		}
+		return ss
+	case reflect.Map:
+		var ss []string
+		iter := v.MapRange()
+		for iter.Next() {
+			ss = append(ss, stringValues(iter.Value())...)
+		}
+		return ss
+	default:
+		return nil
+	}
+}
+
+// detectSecret reports whether s looks like a credential and why.
+func detectSecret(s string) (string, bool) {
+	for _, p := range secretPatterns {
+		if p.pattern.MatchString(s) {
+			return p.reason, true
+		}
+	}
+	if len(s) >= entropyMinLength && tokenPattern.MatchString(s) && entropy(s) >= entropyThreshold {
+		return "high entropy value", true
+	}
+	return "", false
+}
+
+// entropy returns the Shannon entropy of s in bits per character.
+func entropy(s string) float64 {
+	counts := make(map[rune]int)
+	n := 0
+	for _, r := range s {
+		counts[r]++
+		n++
+	}
+	var e float64
+	for _, count := range counts {
+		p := float64(count) / float64(n)
+		e -= p * math.Log2(p)
+	}
+	return e
+}
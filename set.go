@@ -0,0 +1,114 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/knadh/koanf/v2"
+	"github.com/spf13/pflag"
+)
+
+var (
+	BadSetError = errors.New("set must have the form key=value")
+)
+
+// SetArgName is the name of the flag that overrides individual keys.
+const SetArgName = "set"
+
+// SourceSet is the name of the layer holding the values of SetArgName.
+const SourceSet = "set"
+
+// WithSetFlag makes Load read overrides from the repeatable flag SetArgName,
+// each of the form key=value with nested keys separated by the delimiter:
+//
+//	$ ./prog --set nested.val=7 --set value=x
+//
+// Overrides take precedence over files, sources, the environment and flags.
+// A list is written with commas, as in --set hosts=a,b.
+func WithSetFlag() Option {
+	return func(c *Config) {
+		c.setFlag = true
+	}
+}
+
+// AddSetFlag defines the flag named SetArgName in f.
+func AddSetFlag(f *pflag.FlagSet) {
+	f.StringArray(SetArgName, nil, "override a configuration key, as key=value, may be repeated")
+}
+
+// setKey reports whether key holds overrides rather than a configuration
+// value.
+func (c Config) setKey(key string) bool {
+	return c.setFlag && key == SetArgName
+}
+
+// setLayer returns the layer holding the overrides given with SetArgName, and
+// false if WithSetFlag was not used.
+func (c Config) setLayer(f *pflag.FlagSet) (layer, bool) {
+	if !c.setFlag {
+		return layer{}, false
+	}
+	return layer{
+		name: SourceSet,
+		desc: SourceSet,
+		open: func(*loaded) (koanf.Provider, koanf.Parser, error) {
+			m, err := c.setValues(f)
+			if err != nil {
+				return nil, nil, err
+			}
+			return mapProvider{m: m, delim: c.delimiter}, nil, nil
+		},
+	}, true
+}
+
+// setValues returns the overrides given with SetArgName, keyed by their
+// flattened key. Later overrides of a key replace earlier ones.
+func (c Config) setValues(f *pflag.FlagSet) (map[string]interface{}, error) {
+	m := make(map[string]interface{})
+	p := f.Lookup(SetArgName)
+	if p == nil {
+		return m, nil
+	}
+	var sets []string
+	var err error
+	switch p.Value.Type() {
+	case "stringArray":
+		sets, err = f.GetStringArray(SetArgName)
+	default:
+		sets, err = f.GetStringSlice(SetArgName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get %s flag: %v", SetArgName, err)
+	}
+	for _, s := range sets {
+		key, value, ok := strings.Cut(s, "=")
+		if key = strings.TrimSpace(key); !ok || key == "" {
+			return nil, fmt.Errorf("%s %q: %w", SetArgName, s, BadSetError)
+		}
+		m[key] = value
+	}
+	return m, nil
+}
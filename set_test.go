@@ -0,0 +1,90 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/pflag"
+)
+
+func TestWithSetFlag(t *testing.T) {
+	cases := []struct {
+		name    string
+		args    []string
+		want    testConfig
+		wantErr error
+	}{
+		{
+			name: "overrides flags",
+			args: []string{
+				fmt.Sprintf("--%s=1", testKey1),
+				fmt.Sprintf("--%s=%s=%d", SetArgName, testKey1, testValue1),
+				fmt.Sprintf("--%s=%s.%s=%d", SetArgName, testNestedTag, testNestedKey, testValue2),
+			},
+			want: testConfig{Value1: testValue1, Nested: testConfig1{NestedVal: testValue2}},
+		},
+		{
+			name: "last wins",
+			args: []string{
+				fmt.Sprintf("--%s=%s=1", SetArgName, testKey1),
+				fmt.Sprintf("--%s=%s=%d", SetArgName, testKey1, testValue1),
+			},
+			want: testConfig{Value1: testValue1},
+		},
+		{
+			name:    "missing value",
+			args:    []string{"--" + SetArgName + "=" + testKey1},
+			wantErr: BadSetError,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			f.Int(testKey1, 0, testNoHelpMessage)
+			AddSetFlag(f)
+			if err := f.Parse(tc.args); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+			c, err := New(testPrefix, testDelimiter, WithSetFlag(), WithStrict())
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+			var got testConfig
+			err = c.Load(f, &got)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Load err: got=%v want=%v", err, tc.wantErr)
+			}
+			var fe *FlagError
+			if tc.wantErr != nil && !errors.As(err, &fe) {
+				t.Errorf("Load err: got=%T want=*FlagError", err)
+			}
+			if diff := cmp.Diff(tc.want, got); err == nil && diff != "" {
+				t.Errorf("Load mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
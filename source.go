@@ -0,0 +1,173 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/knadh/koanf/providers/file"
+	"github.com/knadh/koanf/v2"
+)
+
+var (
+	UnsupportedSchemeError = errors.New("unsupported config source scheme")
+)
+
+// Source is an additional layer of configuration. Sources are loaded, in the
+// order they were added, after config files and before environment variables.
+type Source struct {
+	// Name identifies the source in errors.
+	Name string
+	// Provider reads the configuration.
+	Provider koanf.Provider
+	// Parser parses the bytes read by Provider. It is nil if Provider
+	// returns an already parsed map from Read.
+	Parser koanf.Parser
+}
+
+// ContextProvider is implemented by source providers that can be canceled,
+// such as those reading from a network service. Load calls ReadContext and
+// ReadBytesContext, rather than Read and ReadBytes, with the context passed to
+// LoadContext, or context.Background for Load.
+type ContextProvider interface {
+	ReadContext(ctx context.Context) (map[string]interface{}, error)
+	ReadBytesContext(ctx context.Context) ([]byte, error)
+}
+
+// contextProvider is a koanf.Provider that reads a ContextProvider with ctx.
+type contextProvider struct {
+	p   ContextProvider
+	ctx context.Context
+}
+
+// ReadBytes calls ReadBytesContext with the context of p.
+func (p contextProvider) ReadBytes() ([]byte, error) {
+	return p.p.ReadBytesContext(p.ctx)
+}
+
+// Read calls ReadContext with the context of p.
+func (p contextProvider) Read() (map[string]interface{}, error) {
+	return p.p.ReadContext(p.ctx)
+}
+
+// sourceProvider returns the provider of s, reading with ctx if it is a
+// ContextProvider.
+func sourceProvider(ctx context.Context, s Source) koanf.Provider {
+	if cp, ok := s.Provider.(ContextProvider); ok {
+		return contextProvider{p: cp, ctx: ctx}
+	}
+	return s.Provider
+}
+
+// WithSource adds s to the sources that are loaded.
+func WithSource(s Source) Option {
+	return func(c *Config) {
+		c.sources = append(c.sources, s)
+	}
+}
+
+// ObjectStore fetches objects from a bucket based storage service such as
+// Amazon S3 or Google Cloud Storage.
+type ObjectStore interface {
+	GetObject(ctx context.Context, bucket, key string) ([]byte, error)
+}
+
+// ObjectStoreFunc adapts a function to the ObjectStore interface.
+type ObjectStoreFunc func(ctx context.Context, bucket, key string) ([]byte, error)
+
+// GetObject calls f(ctx, bucket, key).
+func (f ObjectStoreFunc) GetObject(ctx context.Context, bucket, key string) ([]byte, error) {
+	return f(ctx, bucket, key)
+}
+
+// WithObjectStore registers s to fetch config files named with scheme, for
+// example "s3" for --config=s3://bucket/key or "gs" for
+// --config=gs://bucket/key. The package does not depend on any cloud SDK, so
+// callers provide a store backed by the client of their choice.
+func WithObjectStore(scheme string, s ObjectStore) Option {
+	return func(c *Config) {
+		if c.stores == nil {
+			c.stores = make(map[string]ObjectStore)
+		}
+		c.stores[strings.ToLower(scheme)] = s
+	}
+}
+
+// bytesProvider is a koanf.Provider that reads its contents using a function.
+type bytesProvider func() ([]byte, error)
+
+// ReadBytes returns the contents returned by p.
+func (p bytesProvider) ReadBytes() ([]byte, error) {
+	return p()
+}
+
+// Read is not supported because the contents must be parsed.
+func (p bytesProvider) Read() (map[string]interface{}, error) {
+	return nil, errors.New("bytesProvider does not support Read")
+}
+
+// provider returns the koanf.Provider used to read the config file named by
+// name. Names without a scheme are local files.
+func (c Config) provider(ctx context.Context, name string) (koanf.Provider, error) {
+	if isStdin(name) {
+		return bytesProvider(c.stdinSource().read), nil
+	}
+	if !strings.Contains(name, "://") {
+		if err := c.policy.checkScheme(FileScheme); err != nil {
+			return nil, err
+		}
+		if c.fsys != nil {
+			return c.fsProvider(name), nil
+		}
+		return file.Provider(name), nil
+	}
+	u, err := url.Parse(name)
+	if err != nil {
+		return nil, fmt.Errorf("parse %q: %w", name, err)
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if err := c.policy.checkScheme(scheme); err != nil {
+		return nil, err
+	}
+	if scheme == "http" || scheme == "https" {
+		o := c.httpOptions()
+		return bytesProvider(func() ([]byte, error) {
+			return o.fetch(ctx, name)
+		}), nil
+	}
+	if s, ok := c.stores[scheme]; ok {
+		bucket, key := u.Host, strings.TrimPrefix(u.Path, "/")
+		return bytesProvider(func() ([]byte, error) {
+			b, err := c.fetchObject(ctx, s, bucket, key)
+			if err != nil {
+				return nil, fmt.Errorf("get %s object %s/%s: %w", scheme, bucket, key, err)
+			}
+			return b, nil
+		}), nil
+	}
+	return nil, fmt.Errorf("scheme %q: %w", u.Scheme, UnsupportedSchemeError)
+}
@@ -0,0 +1,135 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/pflag"
+)
+
+func TestLoadViaObjectStore(t *testing.T) {
+	cases := []struct {
+		name        string
+		url         string
+		want        testConfig
+		wantBucket  string
+		wantKey     string
+		wantLoadErr bool
+	}{
+		{
+			name:       "s3 json",
+			url:        "s3://bucket/dir/" + testGoodJSONConfig,
+			wantBucket: "bucket",
+			wantKey:    "dir/" + testGoodJSONConfig,
+			want: testConfig{
+				Value1: testValue1,
+				Nested: testConfig1{
+					NestedVal: testValue2,
+				},
+			},
+		},
+		{
+			name:       "gs yaml",
+			url:        "gs://bucket/" + testGoodYAMLConfig,
+			wantBucket: "bucket",
+			wantKey:    testGoodYAMLConfig,
+			want: testConfig{
+				Value1: testValue1,
+				Nested: testConfig1{
+					NestedVal: testValue2,
+				},
+			},
+		},
+		{
+			name:        "missing object",
+			url:         "s3://bucket/missing.json",
+			wantLoadErr: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotBucket, gotKey string
+			store := ObjectStoreFunc(func(_ context.Context, bucket, key string) ([]byte, error) {
+				gotBucket, gotKey = bucket, key
+				return os.ReadFile(testFileName(path.Base(key)))
+			})
+
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			f.StringSlice(FileArgName, nil, testNoHelpMessage)
+			if err := f.Parse([]string{fmt.Sprintf("--%s=%s", FileArgName, tc.url)}); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+
+			c, err := New(testPrefix, testDelimiter, WithObjectStore("s3", store), WithObjectStore("gs", store))
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+
+			var cfg testConfig
+			err = c.Load(f, &cfg)
+			if tc.wantLoadErr {
+				if err == nil {
+					t.Errorf("Load err: got=nil want=<non-nil>")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load err: got=%v want=nil", err)
+			}
+			if got, want := gotBucket, tc.wantBucket; got != want {
+				t.Errorf("bucket: got=%q want=%q", got, want)
+			}
+			if got, want := gotKey, tc.wantKey; got != want {
+				t.Errorf("key: got=%q want=%q", got, want)
+			}
+			if diff := cmp.Diff(tc.want, cfg); diff != "" {
+				t.Errorf("Load cfg mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLoadUnsupportedScheme(t *testing.T) {
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	f.StringSlice(FileArgName, nil, testNoHelpMessage)
+	if err := f.Parse([]string{fmt.Sprintf("--%s=s3://bucket/%s", FileArgName, testGoodJSONConfig)}); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	var cfg testConfig
+	if err := c.Load(f, &cfg); !errors.Is(err, UnsupportedSchemeError) {
+		t.Errorf("Load err: got=%v want=%v", err, UnsupportedSchemeError)
+	}
+}
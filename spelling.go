@@ -0,0 +1,94 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"sort"
+	"strings"
+)
+
+// Normalizations applied to key spellings, reported in Spelling.Rule.
+const (
+	// RuleExact means the key was used as written.
+	RuleExact = "exact"
+	// RuleCase means the key was matched ignoring case, which unmarshal
+	// does when assigning keys to struct fields.
+	RuleCase = "case"
+	// RuleEnv means an environment variable name had the prefix removed, was
+	// lower cased and had underscores replaced by the delimiter.
+	RuleEnv = "env"
+)
+
+// Spelling is a way a key was written in a source.
+type Spelling struct {
+	// Source names the layer, as in SourceStatus.
+	Source string
+	// Raw is the key as the source wrote it.
+	Raw string
+	// Rule is the normalization that mapped Raw to the key.
+	Rule string
+}
+
+// KeySpelling lists the different spellings that were merged into a single
+// key.
+type KeySpelling struct {
+	Key       string
+	Spellings []Spelling
+}
+
+// recordSpelling records that source wrote key as raw.
+func (l *loaded) recordSpelling(source, raw, key string) {
+	canonical := strings.ToLower(key)
+	rule := RuleExact
+	switch {
+	case source == SourceEnv:
+		rule = RuleEnv
+	case key != canonical:
+		rule = RuleCase
+	}
+	if l.spellings == nil {
+		l.spellings = make(map[string][]Spelling)
+	}
+	l.spellings[canonical] = append(l.spellings[canonical], Spelling{Source: source, Raw: raw, Rule: rule})
+}
+
+// KeySpellings returns the keys that were provided in more than one spelling,
+// for example as HTTP_PORT in the environment, http.port in one file and
+// Http.Port in another, with the normalization that merged each spelling.
+// This helps to find collisions caused by case folding. Keys are sorted and
+// spellings are in the order the sources were loaded.
+func (r *Result) KeySpellings() []KeySpelling {
+	var ks []KeySpelling
+	for key, spellings := range r.l.spellings {
+		raw := make(map[string]bool)
+		for _, s := range spellings {
+			raw[s.Raw] = true
+		}
+		if len(raw) < 2 {
+			continue
+		}
+		ks = append(ks, KeySpelling{Key: key, Spellings: append([]Spelling(nil), spellings...)})
+	}
+	sort.Slice(ks, func(i, j int) bool { return ks[i].Key < ks[j].Key })
+	return ks
+}
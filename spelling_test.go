@@ -0,0 +1,75 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/pflag"
+)
+
+func TestResultKeySpellings(t *testing.T) {
+	dir := t.TempDir()
+	lower := filepath.Join(dir, "lower.yaml")
+	writeTestFile(t, lower, "http:\n  port: 1\nname: a\n")
+	mixed := filepath.Join(dir, "mixed.yaml")
+	writeTestFile(t, mixed, "Http:\n  Port: 2\n")
+	envName := strings.ToUpper(testPrefix) + "HTTP_PORT"
+	t.Setenv(envName, "3")
+
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	f.StringSlice(FileArgName, nil, testNoHelpMessage)
+	args := []string{
+		fmt.Sprintf("--%s=%s", FileArgName, lower),
+		fmt.Sprintf("--%s=%s", FileArgName, mixed),
+	}
+	if err := f.Parse(args); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	var cfg struct{}
+	r, err := c.LoadWithResult(f, &cfg)
+	if err != nil {
+		t.Fatalf("LoadWithResult err: got=%v want=nil", err)
+	}
+
+	want := []KeySpelling{{
+		Key: "http.port",
+		Spellings: []Spelling{
+			{Source: lower, Raw: "http.port", Rule: RuleExact},
+			{Source: mixed, Raw: "Http.Port", Rule: RuleCase},
+			{Source: SourceEnv, Raw: envName, Rule: RuleEnv},
+		},
+	}}
+	if diff := cmp.Diff(want, r.KeySpellings()); diff != "" {
+		t.Errorf("KeySpellings mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,89 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+// StdinName is the config file name that reads the configuration from
+// standard input, as in --config=-.
+const StdinName = "-"
+
+// WithStdinFormat sets the format, such as FormatJSON, of the configuration
+// read from standard input. The default is FormatYAML, which also accepts
+// JSON.
+func WithStdinFormat(format string) Option {
+	return func(c *Config) {
+		c.stdinFormat = format
+	}
+}
+
+// WithStdin makes the config file StdinName read from r instead of os.Stdin.
+func WithStdin(r io.Reader) Option {
+	return func(c *Config) {
+		c.stdin = &stdinReader{r: r}
+	}
+}
+
+// stdinReader reads standard input once, so that reloads see the same
+// configuration as the first Load.
+type stdinReader struct {
+	r    io.Reader
+	once sync.Once
+	b    []byte
+	err  error
+}
+
+func (s *stdinReader) read() ([]byte, error) {
+	s.once.Do(func() {
+		s.b, s.err = io.ReadAll(s.r)
+	})
+	return s.b, s.err
+}
+
+// isStdin reports whether the config file name reads standard input.
+func isStdin(name string) bool {
+	return name == StdinName
+}
+
+// stdinFormatOrDefault returns the format of standard input.
+func (c Config) stdinFormatOrDefault() string {
+	if c.stdinFormat == "" {
+		return FormatYAML
+	}
+	return c.stdinFormat
+}
+
+// stdinSource returns the reader of standard input, shared by copies of c.
+func (c Config) stdinSource() *stdinReader {
+	if c.stdin == nil {
+		return defaultStdin
+	}
+	return c.stdin
+}
+
+// defaultStdin reads os.Stdin, which can only be read once per process.
+var defaultStdin = &stdinReader{r: os.Stdin}
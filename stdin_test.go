@@ -0,0 +1,95 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestLoadStdin(t *testing.T) {
+	cases := []struct {
+		name    string
+		arg     string
+		input   string
+		opts    []Option
+		want    testConfig
+		wantErr error
+	}{
+		{
+			name:  "yaml by default",
+			arg:   StdinName,
+			input: fmt.Sprintf("%s: %d\n", testKey1, testValue1),
+			want:  testConfig{Value1: testValue1},
+		},
+		{
+			name:  "json is yaml",
+			arg:   StdinName,
+			input: fmt.Sprintf(`{"%s": %d}`, testKey2, testValue2),
+			want:  testConfig{Value2: testValue2},
+		},
+		{
+			name:  "selected format",
+			arg:   StdinName,
+			input: fmt.Sprintf("%sVALUE3=%d\n", testPrefix, testValue3),
+			opts:  []Option{WithStdinFormat(FormatEnv)},
+			want:  testConfig{Value3: testValue3},
+		},
+		{
+			name:    "unknown format",
+			arg:     StdinName,
+			opts:    []Option{WithStdinFormat("xml")},
+			wantErr: UnknownFormatError,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			AddConfigFlag(f)
+			if err := f.Parse([]string{"--" + FileArgName + "=" + tc.arg}); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+			opts := append(tc.opts, WithStdin(strings.NewReader(tc.input)), WithBaseDir(t.TempDir()))
+			c, err := New(testPrefix, testDelimiter, opts...)
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+
+			// Standard input is read once and reused by later loads.
+			for i := 0; i < 2; i++ {
+				var got testConfig
+				err = c.Load(f, &got)
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("Load %d err: got=%v want=%v", i, err, tc.wantErr)
+				}
+				if got != tc.want {
+					t.Errorf("Load %d: got=%+v want=%+v", i, got, tc.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/knadh/koanf/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// WithStreamingParse makes Load decode local JSON and YAML config files of at
+// least threshold bytes directly from the file, instead of reading the whole
+// file into memory before parsing it. This lowers the peak memory used to load
+// very large files, such as generated routing tables. A threshold of zero or
+// less streams every local JSON and YAML file.
+func WithStreamingParse(threshold int64) Option {
+	return func(c *Config) {
+		c.streaming = true
+		c.streamThreshold = threshold
+	}
+}
+
+// streamProvider returns a provider that decodes the config file named by
+// name while reading it, and whether name should be streamed.
+func (c Config) streamProvider(name string) (koanf.Provider, bool) {
+	// Files the policy does not allow are reported by the regular provider,
+	// as are errors from Stat.
+	if !c.streaming || c.fsys != nil || isStdin(name) || strings.Contains(name, "://") || c.policy.checkScheme(FileScheme) != nil {
+		return nil, false
+	}
+	format := detectFormat(name)
+	if format != FormatJSON && format != FormatYAML {
+		return nil, false
+	}
+	if c.streamThreshold > 0 {
+		fi, err := os.Stat(name)
+		if err != nil || fi.Size() < c.streamThreshold {
+			return nil, false
+		}
+	}
+	return streamingProvider{name: name, format: format}, true
+}
+
+// streamingProvider is a koanf.Provider that decodes a local JSON or YAML
+// file as it is read.
+type streamingProvider struct {
+	name   string
+	format string
+}
+
+// ReadBytes is not supported because the file is decoded as it is read.
+func (streamingProvider) ReadBytes() ([]byte, error) {
+	return nil, errors.New("streamingProvider does not support ReadBytes")
+}
+
+// Read decodes the file.
+func (p streamingProvider) Read() (map[string]interface{}, error) {
+	f, err := os.Open(p.name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	m := make(map[string]interface{})
+	switch p.format {
+	case FormatYAML:
+		err = yaml.NewDecoder(r).Decode(&m)
+	default:
+		err = json.NewDecoder(r).Decode(&m)
+	}
+	if err != nil && !(errors.Is(err, io.EOF) && p.format == FormatYAML) {
+		return nil, fmt.Errorf("decode %s: %w", p.name, err)
+	}
+	return m, nil
+}
@@ -0,0 +1,129 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/pflag"
+)
+
+type testRoute struct {
+	Path    string `koanf:"path"`
+	Backend string `koanf:"backend"`
+	Weight  int    `koanf:"weight"`
+}
+
+type testRoutesConfig struct {
+	Routes []testRoute `koanf:"routes"`
+}
+
+// writeTestRoutes writes a YAML or JSON file containing n routes and returns
+// its name.
+func writeTestRoutes(tb testing.TB, format string, n int) string {
+	tb.Helper()
+	var sb strings.Builder
+	switch format {
+	case FormatYAML:
+		sb.WriteString("routes:\n")
+		for i := 0; i < n; i++ {
+			fmt.Fprintf(&sb, "  - path: /route/%d\n    backend: backend-%d:8080\n    weight: %d\n", i, i%10, i%100)
+		}
+	default:
+		sb.WriteString(`{"routes": [`)
+		for i := 0; i < n; i++ {
+			if i > 0 {
+				sb.WriteString(",")
+			}
+			fmt.Fprintf(&sb, `{"path": "/route/%d", "backend": "backend-%d:8080", "weight": %d}`, i, i%10, i%100)
+		}
+		sb.WriteString("]}")
+	}
+	name := filepath.Join(tb.TempDir(), "routes."+format)
+	if err := os.WriteFile(name, []byte(sb.String()), 0o600); err != nil {
+		tb.Fatalf("WriteFile failed unexpectedly: %v", err)
+	}
+	return name
+}
+
+func loadTestRoutes(tb testing.TB, name string, opts ...Option) testRoutesConfig {
+	tb.Helper()
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	f.StringSlice(FileArgName, nil, testNoHelpMessage)
+	if err := f.Parse([]string{fmt.Sprintf("--%s=%s", FileArgName, name)}); err != nil {
+		tb.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	c, err := New(testPrefix, testDelimiter, opts...)
+	if err != nil {
+		tb.Fatalf("New failed unexpectedly: %v", err)
+	}
+	var cfg testRoutesConfig
+	if err := c.Load(f, &cfg); err != nil {
+		tb.Fatalf("Load err: got=%v want=nil", err)
+	}
+	return cfg
+}
+
+func TestStreamingParseMatchesRegular(t *testing.T) {
+	for _, format := range []string{FormatJSON, FormatYAML} {
+		t.Run(format, func(t *testing.T) {
+			name := writeTestRoutes(t, format, 100)
+			want := loadTestRoutes(t, name)
+			got := loadTestRoutes(t, name, WithStreamingParse(0))
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("Load mismatch (-regular +streaming):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestStreamingParseEmptyYAML(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "empty.yaml")
+	writeTestFile(t, name, "")
+	if got := loadTestRoutes(t, name, WithStreamingParse(0)); len(got.Routes) != 0 {
+		t.Errorf("Routes: got=%d want=0", len(got.Routes))
+	}
+}
+
+func benchmarkLoadRoutes(b *testing.B, format string, opts ...Option) {
+	name := writeTestRoutes(b, format, 50000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		loadTestRoutes(b, name, opts...)
+	}
+}
+
+func BenchmarkLoadYAML(b *testing.B) { benchmarkLoadRoutes(b, FormatYAML) }
+func BenchmarkLoadYAMLStreaming(b *testing.B) {
+	benchmarkLoadRoutes(b, FormatYAML, WithStreamingParse(0))
+}
+func BenchmarkLoadJSON(b *testing.B) { benchmarkLoadRoutes(b, FormatJSON) }
+func BenchmarkLoadJSONStreaming(b *testing.B) {
+	benchmarkLoadRoutes(b, FormatJSON, WithStreamingParse(0))
+}
@@ -0,0 +1,185 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+var (
+	UnknownKeyError = errors.New("unknown configuration keys")
+)
+
+// WithStrict makes Load fail with UnknownKeyError if a source supplies a key
+// that does not correspond to a field of the configuration struct, which
+// usually is a typo. The error suggests the closest known key:
+//
+//	Load unknown key "nested.vall" from config.yaml, did you mean "nested.val"?
+//
+// Keys are matched ignoring case, as unmarshal does. Flag defaults and metadata
+// are not checked, and neither is the config file flag.
+func WithStrict() Option {
+	return func(c *Config) {
+		c.strict = true
+	}
+}
+
+// knownKeys returns the lower cased keys of the leaf fields of cfg.
+func (c Config) knownKeys(cfg interface{}) ([]string, error) {
+	v, err := structValue(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	err = walkFields(v, "", c.delimiter, func(key string, _ reflect.StructField, _ reflect.Value) error {
+		keys = append(keys, strings.ToLower(key))
+		return nil
+	})
+	return keys, err
+}
+
+// checkUnknown returns an error describing every key supplied to l that is not
+// a field of cfg. Keys below a leaf field, such as the entries of a map, are
+// known.
+func (c Config) checkUnknown(l *loaded, f *pflag.FlagSet, cfg interface{}) error {
+	if !c.strict {
+		return nil
+	}
+	v, err := structValue(cfg)
+	if err != nil {
+		return err
+	}
+	known, err := c.knownKeys(cfg)
+	if err != nil {
+		return err
+	}
+	isKnown := func(key string) bool {
+		return c.knownKey(v.Type(), c.Key(strings.ToLower(key)))
+	}
+
+	// LoadKey only checks the keys below its prefix.
+	checked := func(key string) bool {
+		return c.strictPrefix == "" || c.Key(strings.ToLower(key)).HasPrefix(c.Key(strings.ToLower(c.strictPrefix)))
+	}
+
+	var problems []string
+	keys := l.k.Keys()
+	sort.Strings(keys)
+	for _, key := range keys {
+		source := l.sources[key]
+		if c.controlKey(key) || source == SourceMetadata || !checked(key) || !l.supplied(f, key) || isKnown(key) {
+			continue
+		}
+		p := fmt.Sprintf("unknown key %q from %s", key, source)
+		if s, ok := suggest(strings.ToLower(key), known); ok {
+			p += fmt.Sprintf(", did you mean %q?", s)
+		}
+		problems = append(problems, p)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("Load %s: %w", strings.Join(problems, "; "), UnknownKeyError)
+	}
+	return nil
+}
+
+// knownKey reports whether key is a field of the struct type t, or is below a
+// leaf field such as a map. It follows key through t, rather than listing
+// every field, so that recursive types are handled.
+func (c Config) knownKey(t reflect.Type, key Key) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, ok := fieldName(sf)
+		if !ok {
+			continue
+		}
+		k := c.Key(strings.ToLower(name))
+		if !key.HasPrefix(k) {
+			continue
+		}
+		if !isNested(sf.Type) || c.knownKey(sf.Type, key[len(k):]) {
+			return true
+		}
+	}
+	return false
+}
+
+// suggest returns the key in known closest to key, if it is close enough to be
+// a likely typo.
+func suggest(key string, known []string) (string, bool) {
+	best, bestDist := "", -1
+	for _, k := range known {
+		d := levenshtein(key, k)
+		if bestDist < 0 || d < bestDist {
+			best, bestDist = k, d
+		}
+	}
+	maxDist := len(key) / 3
+	if maxDist < 2 {
+		maxDist = 2
+	}
+	if bestDist < 0 || bestDist > maxDist {
+		return "", false
+	}
+	return best, true
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,125 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+type testStrictConfig struct {
+	Name   string            `koanf:"name"`
+	Labels map[string]string `koanf:"labels"`
+	Nested struct {
+		Val int `koanf:"val"`
+	} `koanf:"nested"`
+}
+
+func TestLoadStrict(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		args    []string
+		wantErr string
+	}{
+		{
+			name:    "known keys",
+			content: "name: a\nlabels:\n  team: x\nNested:\n  Val: 1\n",
+		},
+		{
+			name:    "typo",
+			content: "nested:\n  vall: 1\n",
+			wantErr: `unknown key "nested.vall" from %s, did you mean "nested.val"?`,
+		},
+		{
+			name:    "no suggestion",
+			content: "completely_different: 1\n",
+			wantErr: `unknown key "completely_different" from %s: `,
+		},
+		{
+			name:    "flag",
+			args:    []string{"--nmae=a"},
+			wantErr: `unknown key "nmae" from flags, did you mean "name"?`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			name := filepath.Join(t.TempDir(), "config.yaml")
+			writeTestFile(t, name, tc.content)
+
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			f.StringSlice(FileArgName, nil, testNoHelpMessage)
+			f.String("nmae", "", testNoHelpMessage)
+			f.String("unused", "", testNoHelpMessage)
+			args := append([]string{fmt.Sprintf("--%s=%s", FileArgName, name)}, tc.args...)
+			if err := f.Parse(args); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+			c, err := New(testPrefix, testDelimiter, WithStrict())
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+
+			var cfg testStrictConfig
+			err = c.Load(f, &cfg)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Errorf("Load err: got=%v want=nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, UnknownKeyError) {
+				t.Fatalf("Load err: got=%v want=%v", err, UnknownKeyError)
+			}
+			want := tc.wantErr
+			if strings.Contains(want, "%s") {
+				want = fmt.Sprintf(want, name)
+			}
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("Load err: got=%q want=%q", err, want)
+			}
+		})
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"val", "vall", 1},
+		{"kitten", "sitting", 3},
+		{"name", "nmae", 2},
+	}
+	for _, tc := range cases {
+		if got := levenshtein(tc.a, tc.b); got != tc.want {
+			t.Errorf("levenshtein(%q, %q): got=%d want=%d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
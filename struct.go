@@ -0,0 +1,54 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"strings"
+
+	"github.com/bretmckee/goconfig/core"
+)
+
+var (
+	NotStructError = core.NotStructError
+)
+
+// The struct conventions shared with package core.
+var (
+	fieldName         = core.FieldName
+	hasTagOption      = core.HasTagOption
+	isSecret          = core.IsSecret
+	isNested          = core.IsNested
+	isTextUnmarshaler = core.IsTextUnmarshaler
+	structValue       = core.StructValue
+	walkFields        = core.WalkFields
+)
+
+// flatten returns the leaf values of cfg keyed by their delimited keys.
+func (c Config) flatten(cfg interface{}) (map[string]interface{}, error) {
+	return core.Flatten(cfg, c.delimiter)
+}
+
+// envName returns the environment variable that sets key.
+func (c Config) envName(key string) string {
+	return c.prefix + strings.ToUpper(strings.ReplaceAll(key, c.delimiter, c.envSeparator()))
+}
@@ -0,0 +1,68 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_flatten(t *testing.T) {
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	cfg := testConfig{
+		Value1: testValue1,
+		Nested: testConfig1{
+			NestedVal: testValue2,
+		},
+	}
+	got, err := c.flatten(&cfg)
+	if err != nil {
+		t.Fatalf("flatten err: got=%v want=nil", err)
+	}
+	want := map[string]interface{}{
+		testKey1:                            testValue1,
+		testKey2:                            0,
+		testKey3:                            0,
+		testNestedTag + "." + testNestedKey: testValue2,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("flatten mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_flattenNotStruct(t *testing.T) {
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	if _, err := c.flatten(testValue1); !errors.Is(err, NotStructError) {
+		t.Errorf("flatten err: got=%v want=%v", err, NotStructError)
+	}
+}
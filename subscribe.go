@@ -0,0 +1,172 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"context"
+	"sync"
+
+	"github.com/spf13/pflag"
+)
+
+// Snapshot is a version of the configuration delivered to subscribers.
+type Snapshot[T any] struct {
+	// Version starts at 1 for the initial load and increases by one for
+	// every accepted reload.
+	Version uint64
+	Config  T
+	// Changes lists the keys that changed since the previous version. It is
+	// empty for the initial load.
+	Changes []KeyChange
+}
+
+// Watcher watches configuration like Watch and delivers every new version to
+// any number of independent subscribers.
+type Watcher[T any] struct {
+	c       Config
+	mu      sync.Mutex
+	current Snapshot[T]
+	subs    []subscription[T]
+	done    bool
+}
+
+// subscription is a channel that receives the snapshots changing keys under
+// prefix, or every snapshot if prefix is empty.
+type subscription[T any] struct {
+	ch     chan Snapshot[T]
+	prefix string
+}
+
+// NewWatcher loads cfg and then watches for changes as Watch does. Each
+// successful reload produces a new Snapshot that is delivered to every
+// subscriber. NewWatcher returns after the initial load. Watching stops, and
+// every subscription channel is closed, when ctx is done.
+func NewWatcher[T any](ctx context.Context, c Config, f *pflag.FlagSet, cfg *T) (*Watcher[T], error) {
+	w := &Watcher[T]{c: c}
+	r, err := newReloader(ctx, c, f, cfg, func(_, _ T) error { return nil })
+	if err != nil {
+		return nil, err
+	}
+	r.onReload = w.publish
+	w.current = Snapshot[T]{Version: 1, Config: *cfg}
+
+	if err := startWatch(ctx, r); err != nil {
+		return nil, err
+	}
+	go func() {
+		<-ctx.Done()
+		w.close()
+	}()
+	return w, nil
+}
+
+// Current returns the latest snapshot.
+func (w *Watcher[T]) Current() Snapshot[T] {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.current
+}
+
+// Subscribe returns a channel that receives the current snapshot immediately
+// and every later one. The channel buffers a single snapshot: if a subscriber
+// is slow, a pending snapshot it has not received yet is replaced by the
+// newer one, so a subscriber may skip versions but always receives the
+// latest. Use Snapshot.Version to detect skipped versions. The channel is
+// closed when watching stops.
+func (w *Watcher[T]) Subscribe() <-chan Snapshot[T] {
+	return w.SubscribePrefix("")
+}
+
+// SubscribePrefix is like Subscribe, but after the current snapshot the
+// channel only receives snapshots that change prefix or a key below it, for
+// example "db" matches "db" and "db.pool.size" but not "dbx". Changes in those
+// snapshots is limited to the matching keys, so all the changes a reload makes
+// to the subtree are delivered together. An empty prefix matches every key.
+func (w *Watcher[T]) SubscribePrefix(prefix string) <-chan Snapshot[T] {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ch := make(chan Snapshot[T], 1)
+	if w.done {
+		close(ch)
+		return ch
+	}
+	ch <- w.current
+	w.subs = append(w.subs, subscription[T]{ch: ch, prefix: prefix})
+	return ch
+}
+
+// underPrefix returns the changes to prefix or the keys below it.
+func underPrefix(changes []KeyChange, prefix, delim string) []KeyChange {
+	if prefix == "" {
+		return changes
+	}
+	p := ParseKey(prefix, delim)
+	var matched []KeyChange
+	for _, ch := range changes {
+		if ParseKey(ch.Path, delim).HasPrefix(p) {
+			matched = append(matched, ch)
+		}
+	}
+	return matched
+}
+
+// publish records a new version and delivers it to the subscribers.
+func (w *Watcher[T]) publish(next T, changes []KeyChange) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.current = Snapshot[T]{
+		Version: w.current.Version + 1,
+		Config:  next,
+		Changes: changes,
+	}
+	for _, sub := range w.subs {
+		snap := w.current
+		if sub.prefix != "" {
+			snap.Changes = underPrefix(changes, sub.prefix, w.c.delimiter)
+			if len(snap.Changes) == 0 {
+				continue
+			}
+		}
+		// Drop a snapshot the subscriber has not received yet, so the send
+		// never blocks.
+		select {
+		case <-sub.ch:
+		default:
+		}
+		sub.ch <- snap
+	}
+}
+
+// close closes every subscription channel.
+func (w *Watcher[T]) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.done = true
+	for _, sub := range w.subs {
+		close(sub.ch)
+	}
+	w.subs = nil
+}
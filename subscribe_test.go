@@ -0,0 +1,140 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+func TestWatcherSubscribe(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "config.json")
+	writeTestFile(t, name, fmt.Sprintf(`{"%s": %d}`, testKey1, testValue1))
+
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	f.StringSlice(FileArgName, nil, testNoHelpMessage)
+	if err := f.Parse([]string{fmt.Sprintf("--%s=%s", FileArgName, name)}); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var cfg testConfig
+	w, err := NewWatcher(ctx, c, f, &cfg)
+	if err != nil {
+		t.Fatalf("NewWatcher err: got=%v want=nil", err)
+	}
+
+	subs := []<-chan Snapshot[testConfig]{w.Subscribe(), w.Subscribe()}
+	for i, sub := range subs {
+		got := <-sub
+		if got.Version != 1 || got.Config.Value1 != testValue1 {
+			t.Errorf("subscriber %d initial snapshot: got=%+v", i, got)
+		}
+	}
+
+	writeTestFile(t, name, fmt.Sprintf(`{"%s": %d}`, testKey1, testValue2))
+
+	for i, sub := range subs {
+		select {
+		case got := <-sub:
+			if got, want := got.Version, uint64(2); got != want {
+				t.Errorf("subscriber %d Version: got=%d want=%d", i, got, want)
+			}
+			if got, want := got.Config.Value1, testValue2; got != want {
+				t.Errorf("subscriber %d Value1: got=%d want=%d", i, got, want)
+			}
+			if got, want := len(got.Changes), 1; got != want {
+				t.Errorf("subscriber %d changes: got=%d want=%d", i, got, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("subscriber %d timed out waiting for snapshot", i)
+		}
+	}
+
+	cancel()
+	for i, sub := range subs {
+		select {
+		case _, ok := <-sub:
+			if ok {
+				t.Errorf("subscriber %d: got snapshot want closed channel", i)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("subscriber %d timed out waiting for close", i)
+		}
+	}
+}
+
+func TestWatcherSlowSubscriberGetsLatest(t *testing.T) {
+	w := &Watcher[testConfig]{current: Snapshot[testConfig]{Version: 1}}
+	sub := w.Subscribe()
+
+	w.publish(testConfig{Value1: testValue1}, nil)
+	w.publish(testConfig{Value1: testValue2}, nil)
+
+	got := <-sub
+	if got, want := got.Version, uint64(3); got != want {
+		t.Errorf("Version: got=%d want=%d", got, want)
+	}
+	if got, want := got.Config.Value1, testValue2; got != want {
+		t.Errorf("Value1: got=%d want=%d", got, want)
+	}
+}
+
+func TestWatcherSubscribePrefix(t *testing.T) {
+	w := &Watcher[testConfig]{c: Config{delimiter: testDelimiter}, current: Snapshot[testConfig]{Version: 1}}
+	db, log := w.SubscribePrefix("db"), w.SubscribePrefix("log")
+	<-db
+	<-log
+
+	w.publish(testConfig{}, []KeyChange{
+		{Path: "db.host", New: "a"},
+		{Path: "db.pool.size", New: 2},
+		{Path: "dbx", New: true},
+	})
+
+	select {
+	case got := <-db:
+		if got, want := len(got.Changes), 2; got != want {
+			t.Errorf("db changes: got=%d want=%d", got, want)
+		}
+	default:
+		t.Errorf("db: got no snapshot want one")
+	}
+	select {
+	case got := <-log:
+		t.Errorf("log: got snapshot %+v want none", got)
+	default:
+	}
+}
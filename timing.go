@@ -0,0 +1,105 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"time"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// Stages of Load reported by Result.Timings.
+const (
+	// StageRead is reading a source, such as a file, the environment or
+	// the flags.
+	StageRead = "read"
+	// StageParse is parsing the contents of a source.
+	StageParse = "parse"
+	// StageMerge is merging a source with the ones loaded before it.
+	StageMerge = "merge"
+	// StageUnmarshal is unmarshaling the merged values into the
+	// configuration struct.
+	StageUnmarshal = "unmarshal"
+	// StageValidate is checking required keys and validating the
+	// configuration struct.
+	StageValidate = "validate"
+)
+
+// StageTiming is the time taken by a single stage of Load.
+type StageTiming struct {
+	Stage string
+	// Source is the name of the source the stage processed, as in
+	// SourceStatus, or empty for stages that process every source.
+	Source   string
+	Duration time.Duration
+}
+
+// WithTiming makes Load record how long each of its stages takes, which is
+// reported by Result.Timings. It helps find the stage that slows down the
+// start of a process with a large configuration.
+func WithTiming() Option {
+	return func(c *Config) {
+		c.timing = true
+	}
+}
+
+// since records the time taken by stage since start if timing is enabled.
+func (l *loaded) since(stage, source string, start time.Time) {
+	if l.timings == nil {
+		return
+	}
+	*l.timings = append(*l.timings, StageTiming{Stage: stage, Source: source, Duration: time.Since(start)})
+}
+
+// timedProvider is a koanf.Provider that records the time taken to read.
+type timedProvider struct {
+	p      koanf.Provider
+	l      *loaded
+	source string
+}
+
+func (t timedProvider) ReadBytes() ([]byte, error) {
+	defer t.l.since(StageRead, t.source, time.Now())
+	return t.p.ReadBytes()
+}
+
+func (t timedProvider) Read() (map[string]interface{}, error) {
+	defer t.l.since(StageRead, t.source, time.Now())
+	return t.p.Read()
+}
+
+// timedParser is a koanf.Parser that records the time taken to parse.
+type timedParser struct {
+	p      koanf.Parser
+	l      *loaded
+	source string
+}
+
+func (t timedParser) Unmarshal(b []byte) (map[string]interface{}, error) {
+	defer t.l.since(StageParse, t.source, time.Now())
+	return t.p.Unmarshal(b)
+}
+
+func (t timedParser) Marshal(m map[string]interface{}) ([]byte, error) {
+	return t.p.Marshal(m)
+}
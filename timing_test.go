@@ -0,0 +1,79 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/spf13/pflag"
+)
+
+func TestResultTimings(t *testing.T) {
+	file := testFileName(testGoodJSONConfig)
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	f.StringSlice(FileArgName, nil, testNoHelpMessage)
+	if err := f.Parse([]string{fmt.Sprintf("--%s=%s", FileArgName, file)}); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+
+	for _, timing := range []bool{false, true} {
+		t.Run(fmt.Sprintf("timing=%v", timing), func(t *testing.T) {
+			var opts []Option
+			if timing {
+				opts = append(opts, WithTiming())
+			}
+			c, err := New(testPrefix, testDelimiter, opts...)
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+
+			var cfg testConfig
+			r, err := c.LoadWithResult(f, &cfg)
+			if err != nil {
+				t.Fatalf("LoadWithResult err: got=%v want=nil", err)
+			}
+
+			var want []StageTiming
+			if timing {
+				want = []StageTiming{
+					{Stage: StageRead, Source: file},
+					{Stage: StageParse, Source: file},
+					{Stage: StageMerge, Source: file},
+					{Stage: StageRead, Source: SourceEnv},
+					{Stage: StageMerge, Source: SourceEnv},
+					{Stage: StageRead, Source: SourceFlags},
+					{Stage: StageMerge, Source: SourceFlags},
+					{Stage: StageUnmarshal},
+					{Stage: StageValidate},
+				}
+			}
+			ignore := cmpopts.IgnoreFields(StageTiming{}, "Duration")
+			if diff := cmp.Diff(want, r.Timings(), ignore); diff != "" {
+				t.Errorf("Timings mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
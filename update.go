@@ -0,0 +1,152 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	UnsupportedUpdateError = errors.New("format does not support updating in place")
+)
+
+// UpdateFile changes the values of keys in the YAML config file name, leaving
+// everything else, including comments and the order of keys, as it was.
+// changes maps delimited keys to their new values. Keys that do not exist are
+// added, and keys whose new value is nil are removed. The file is replaced
+// using WriteFile with o. Only YAML can be updated in place: files in other
+// formats, including TOML and JSON, fail with UnsupportedUpdateError and are
+// left unchanged. Use Save to rewrite those files in full.
+func (c Config) UpdateFile(name string, changes map[string]interface{}, o WriteOptions) error {
+	if detectFormat(name) != FormatYAML {
+		return fmt.Errorf("UpdateFile %s: %w", name, UnsupportedUpdateError)
+	}
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return fmt.Errorf("UpdateFile: %w", err)
+	}
+	b, err = c.updateYAML(b, changes)
+	if err != nil {
+		return fmt.Errorf("UpdateFile %s: %w", name, err)
+	}
+	return WriteFile(name, b, o)
+}
+
+// updateYAML returns the YAML document src with changes applied, preserving
+// its comments and key order.
+func (c Config) updateYAML(src []byte, changes map[string]interface{}) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(src, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind == 0 {
+		// An empty document.
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) != 1 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.New("top level is not a mapping")
+	}
+
+	// Apply changes in a fixed order so that added keys are sorted.
+	keys := make([]string, 0, len(changes))
+	for k := range changes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := setYAML(doc.Content[0], strings.Split(k, c.delimiter), changes[k]); err != nil {
+			return nil, fmt.Errorf("%s: %w", k, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	e := yaml.NewEncoder(&buf)
+	e.SetIndent(2)
+	if err := e.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := e.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// setYAML sets the value at path in the mapping node m to v, or removes it if v
+// is nil.
+func setYAML(m *yaml.Node, path []string, v interface{}) error {
+	for i := 0; i < len(m.Content); i += 2 {
+		if m.Content[i].Value != path[0] {
+			continue
+		}
+		old := m.Content[i+1]
+		if len(path) > 1 {
+			if old.Kind != yaml.MappingNode {
+				return fmt.Errorf("%s is not a mapping", path[0])
+			}
+			return setYAML(old, path[1:], v)
+		}
+		if v == nil {
+			m.Content = append(m.Content[:i], m.Content[i+2:]...)
+			return nil
+		}
+		n, err := yamlNode(v)
+		if err != nil {
+			return err
+		}
+		n.HeadComment, n.LineComment, n.FootComment = old.HeadComment, old.LineComment, old.FootComment
+		m.Content[i+1] = n
+		return nil
+	}
+
+	if v == nil {
+		return nil
+	}
+	key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: path[0]}
+	if len(path) > 1 {
+		child := &yaml.Node{Kind: yaml.MappingNode}
+		m.Content = append(m.Content, key, child)
+		return setYAML(child, path[1:], v)
+	}
+	n, err := yamlNode(v)
+	if err != nil {
+		return err
+	}
+	m.Content = append(m.Content, key, n)
+	return nil
+}
+
+// yamlNode returns the YAML node representing v.
+func yamlNode(v interface{}) (*yaml.Node, error) {
+	var n yaml.Node
+	if err := n.Encode(v); err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
@@ -0,0 +1,116 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUpdateFile(t *testing.T) {
+	const src = `# Service settings.
+name: app # the name
+server:
+  # Port to listen on.
+  port: 80
+  host: localhost
+obsolete: true
+`
+	const want = `# Service settings.
+name: app # the name
+server:
+  # Port to listen on.
+  port: 8080
+  host: localhost
+  tls:
+    enabled: true
+`
+	name := filepath.Join(t.TempDir(), "config.yaml")
+	writeTestFile(t, name, src)
+
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	changes := map[string]interface{}{
+		"server.port":        8080,
+		"server.tls.enabled": true,
+		"obsolete":           nil,
+	}
+	if err := c.UpdateFile(name, changes, WriteOptions{}); err != nil {
+		t.Fatalf("UpdateFile err: got=%v want=nil", err)
+	}
+
+	b, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatalf("ReadFile failed unexpectedly: %v", err)
+	}
+	if diff := cmp.Diff(want, string(b)); diff != "" {
+		t.Errorf("UpdateFile mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestUpdateFileUnsupported(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		contents string
+	}{
+		{
+			name:     "json",
+			file:     "config.json",
+			contents: "{}",
+		},
+		{
+			name:     "toml",
+			file:     "config.toml",
+			contents: "# comment\na = 0\n",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			name := filepath.Join(t.TempDir(), tc.file)
+			writeTestFile(t, name, tc.contents)
+
+			c, err := New(testPrefix, testDelimiter)
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+			err = c.UpdateFile(name, map[string]interface{}{"a": 1}, WriteOptions{})
+			if !errors.Is(err, UnsupportedUpdateError) {
+				t.Errorf("UpdateFile err: got=%v want=%v", err, UnsupportedUpdateError)
+			}
+			b, err := os.ReadFile(name)
+			if err != nil {
+				t.Fatalf("os.ReadFile failed unexpectedly: %v", err)
+			}
+			if diff := cmp.Diff(tc.contents, string(b)); diff != "" {
+				t.Errorf("UpdateFile contents mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
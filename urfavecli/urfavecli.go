@@ -0,0 +1,127 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package urfavecli loads configuration with goconfig in programs that use
+// github.com/urfave/cli/v2 instead of pflag. Flags set on the command line
+// take precedence over the environment and config files, exactly as with
+// goconfig.Config.Load:
+//
+//	app := &cli.App{
+//		Flags: []cli.Flag{
+//			&cli.StringSliceFlag{Name: goconfig.FileArgName},
+//			&cli.IntFlag{Name: "port"},
+//		},
+//		Action: func(ctx *cli.Context) error {
+//			var cfg Config
+//			if err := urfavecli.Load(c, ctx, &cfg); err != nil {
+//				return err
+//			}
+//			...
+//		},
+//	}
+package urfavecli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bretmckee/goconfig"
+	"github.com/spf13/pflag"
+	"github.com/urfave/cli/v2"
+)
+
+// Load loads cfg as c.Load does, using the flags of ctx and its parent
+// contexts in place of a pflag.FlagSet.
+func Load(c goconfig.Config, ctx *cli.Context, cfg interface{}) error {
+	return c.Load(FlagSet(ctx), cfg)
+}
+
+// FlagSet returns a pflag.FlagSet holding the flags of ctx and its parent
+// contexts, for use with the other methods of goconfig.Config. Each flag has
+// its value in ctx, and is changed if it was set, including by the environment
+// variables of the flag. A flag is named by its first name.
+func FlagSet(ctx *cli.Context) *pflag.FlagSet {
+	f := pflag.NewFlagSet(ctx.App.Name, pflag.ContinueOnError)
+	for _, pCtx := range ctx.Lineage() {
+		var flags []cli.Flag
+		if pCtx.Command != nil {
+			flags = append(flags, pCtx.Command.Flags...)
+		}
+		if pCtx.App != nil {
+			flags = append(flags, pCtx.App.Flags...)
+		}
+		for _, fl := range flags {
+			names := fl.Names()
+			if len(names) == 0 || f.Lookup(names[0]) != nil {
+				continue
+			}
+			addFlag(f, names[0], ctx.Value(names[0]))
+			f.Lookup(names[0]).Changed = ctx.IsSet(names[0])
+		}
+	}
+	return f
+}
+
+// addFlag defines the flag name in f with the value v, which is the value of
+// a flag of urfave/cli.
+func addFlag(f *pflag.FlagSet, name string, v interface{}) {
+	switch v := v.(type) {
+	case bool:
+		f.Bool(name, v, "")
+	case int:
+		f.Int(name, v, "")
+	case int64:
+		f.Int64(name, v, "")
+	case uint:
+		f.Uint(name, v, "")
+	case uint64:
+		f.Uint64(name, v, "")
+	case float64:
+		f.Float64(name, v, "")
+	case string:
+		f.String(name, v, "")
+	case time.Duration:
+		f.Duration(name, v, "")
+	case cli.StringSlice:
+		if name == goconfig.FileArgName {
+			f.Var(goconfig.NewFileList(v.Value()...), name, "")
+		} else {
+			f.StringArray(name, v.Value(), "")
+		}
+	case cli.IntSlice:
+		f.IntSlice(name, v.Value(), "")
+	case cli.Int64Slice:
+		f.Int64Slice(name, v.Value(), "")
+	case cli.Float64Slice:
+		f.Float64Slice(name, v.Value(), "")
+	case cli.Timestamp:
+		var s string
+		if t := v.Value(); t != nil {
+			s = t.Format(time.RFC3339Nano)
+		}
+		f.String(name, s, "")
+	case nil:
+		f.String(name, "", "")
+	default:
+		f.String(name, fmt.Sprint(v), "")
+	}
+}
@@ -0,0 +1,116 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package urfavecli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bretmckee/goconfig"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/urfave/cli/v2"
+)
+
+type testConfig struct {
+	Name    string        `koanf:"name"`
+	Port    int           `koanf:"port"`
+	Debug   bool          `koanf:"debug"`
+	Timeout time.Duration `koanf:"timeout"`
+	Hosts   []string      `koanf:"hosts"`
+}
+
+func TestLoad(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(name, []byte("name: file\nport: 1\ntimeout: 1s\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile failed unexpectedly: %v", err)
+	}
+	cases := []struct {
+		name string
+		args []string
+		env  map[string]string
+		want testConfig
+	}{
+		{
+			name: "file",
+			args: []string{"--" + goconfig.FileArgName, name, "serve"},
+			want: testConfig{Name: "file", Port: 1, Timeout: time.Second},
+		},
+		{
+			name: "env overrides file",
+			args: []string{"--" + goconfig.FileArgName, name, "serve"},
+			env:  map[string]string{"TEST_PORT": "2"},
+			want: testConfig{Name: "file", Port: 2, Timeout: time.Second},
+		},
+		{
+			name: "flags override env",
+			args: []string{"--" + goconfig.FileArgName, name, "--debug", "serve", "--port=3", "--hosts=a,b", "--hosts=c"},
+			env:  map[string]string{"TEST_PORT": "2"},
+			want: testConfig{Name: "file", Port: 3, Debug: true, Timeout: time.Second, Hosts: []string{"a", "b", "c"}},
+		},
+		{
+			name: "flag defaults",
+			args: []string{"serve"},
+			want: testConfig{Name: "default", Port: 8080},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			c, err := goconfig.New("TEST_", ".")
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+			var got testConfig
+			app := &cli.App{
+				Name: "test",
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{Name: goconfig.FileArgName},
+					&cli.StringFlag{Name: "name", Value: "default"},
+					&cli.BoolFlag{Name: "debug"},
+				},
+				Commands: []*cli.Command{{
+					Name: "serve",
+					Flags: []cli.Flag{
+						&cli.IntFlag{Name: "port", Aliases: []string{"p"}, Value: 8080},
+						&cli.DurationFlag{Name: "timeout"},
+						&cli.StringSliceFlag{Name: "hosts"},
+					},
+					Action: func(ctx *cli.Context) error {
+						return Load(c, ctx, &got)
+					},
+				}},
+			}
+			if err := app.Run(append([]string{"test"}, tc.args...)); err != nil {
+				t.Fatalf("Run err: got=%v want=nil", err)
+			}
+			if diff := cmp.Diff(tc.want, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("Load mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
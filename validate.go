@@ -0,0 +1,78 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Validator is implemented by configuration structs that check their own
+// values. After unmarshaling, Load calls Validate on the configuration struct
+// and every nested configuration struct that implements it, innermost first,
+// and returns the first error.
+type Validator interface {
+	Validate() error
+}
+
+// validate calls Validate on v and its nested configuration structs. key is
+// the key of v, and is empty for the configuration struct itself.
+func (c Config) validate(v reflect.Value, key Key) error {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, ok := fieldName(sf)
+		if !ok || !isNested(sf.Type) {
+			continue
+		}
+		if err := c.validate(v.Field(i), key.Join(name)); err != nil {
+			return err
+		}
+	}
+
+	target := v.Interface()
+	if v.CanAddr() {
+		target = v.Addr().Interface()
+	}
+	vd, ok := target.(Validator)
+	if !ok {
+		return nil
+	}
+	if err := vd.Validate(); err != nil {
+		if len(key) == 0 {
+			return fmt.Errorf("Load validate: %w", err)
+		}
+		return fmt.Errorf("Load validate %s: %w", key.Format(c.delimiter), err)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+var (
+	errTestPortRange = errors.New("port out of range")
+	errTestNoName    = errors.New("name is required")
+)
+
+type testValidatedNested struct {
+	Port int `koanf:"port"`
+}
+
+func (n *testValidatedNested) Validate() error {
+	if n.Port < 1 || n.Port > 65535 {
+		return errTestPortRange
+	}
+	return nil
+}
+
+type testValidatedConfig struct {
+	Name   string               `koanf:"name"`
+	Server testValidatedNested  `koanf:"server"`
+	Admin  *testValidatedNested `koanf:"admin"`
+}
+
+func (c testValidatedConfig) Validate() error {
+	if c.Name == "" {
+		return errTestNoName
+	}
+	return nil
+}
+
+func TestLoadValidates(t *testing.T) {
+	cases := []struct {
+		name    string
+		args    []string
+		wantErr error
+		wantKey string
+	}{
+		{
+			name: "valid",
+			args: []string{"--name=app", "--server.port=80"},
+		},
+		{
+			name:    "nested invalid",
+			args:    []string{"--name=app", "--server.port=0"},
+			wantErr: errTestPortRange,
+			wantKey: "server",
+		},
+		{
+			name:    "top level invalid",
+			args:    []string{"--server.port=80"},
+			wantErr: errTestNoName,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			f.String("name", "", testNoHelpMessage)
+			f.Int("server.port", 0, testNoHelpMessage)
+			if err := f.Parse(tc.args); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+
+			c, err := New(testPrefix, testDelimiter)
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+
+			var cfg testValidatedConfig
+			err = c.Load(f, &cfg)
+			if tc.wantErr == nil {
+				if err != nil {
+					t.Errorf("Load err: got=%v want=nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Load err: got=%v want=%v", err, tc.wantErr)
+			}
+			if tc.wantKey != "" && !strings.Contains(err.Error(), fmt.Sprintf(" %s:", tc.wantKey)) {
+				t.Errorf("Load err: got=%q want key %q", err, tc.wantKey)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/spf13/pflag"
+)
+
+// Validate checks the config files against the configuration struct target
+// without loading the environment or flags, for example to check the config
+// of every environment in CI. The files are merged in order, as if passed to
+// the config file flag, over the defaults of target. Validate reports every
+// unknown key, value of the wrong type and missing required field, and every
+// error from oneof and validate tags and Validator implementations, as
+// LoadErrors if there is more than one. target is not modified.
+func (c Config) Validate(files []string, target interface{}) error {
+	v, err := structValue(target)
+	if err != nil {
+		return fmt.Errorf("Validate: %w", err)
+	}
+	cfg := reflect.New(v.Type()).Interface()
+	c.strict = true
+	c.allErrors = true
+
+	args, err := c.fileArgs(files)
+	if err != nil {
+		return fmt.Errorf("Validate: %w", err)
+	}
+	var layers []layer
+	if ly, ok := c.defaultsLayer(cfg); ok {
+		layers = append(layers, ly)
+	}
+	for _, fa := range args {
+		layers = append(layers, c.fileLayer(fa))
+	}
+
+	l := c.newLoaded(cfg)
+	ec := collector{all: true}
+	for _, ly := range layers {
+		if err := l.loadLayer(ly); err != nil {
+			ec.add(layerError(ly, err))
+		}
+	}
+	f := pflag.NewFlagSet("validate", pflag.ContinueOnError)
+	return c.translate(c.finish(l, f, cfg, &ec))
+}
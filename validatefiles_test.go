@@ -0,0 +1,108 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+type testValidateFilesConfig struct {
+	Name string `koanf:"name,required"`
+	Port int    `koanf:"port" default:"8080"`
+}
+
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.yaml")
+	writeTestFile(t, base, "port: 9090\n")
+	prod := filepath.Join(dir, "prod.yaml")
+	writeTestFile(t, prod, "name: prod\n")
+	bad := filepath.Join(dir, "bad.yaml")
+	writeTestFile(t, bad, "prot: 1\nport: x\n")
+
+	t.Setenv(testPrefix+"NAME", "env")
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	cases := []struct {
+		files    []string
+		wantErrs []error
+	}{
+		{
+			files: []string{base, prod},
+		},
+		{
+			files:    []string{base},
+			wantErrs: []error{MissingRequiredError},
+		},
+		{
+			files:    []string{bad},
+			wantErrs: []error{UnknownKeyError, &UnmarshalError{}, MissingRequiredError},
+		},
+		{
+			files:    []string{filepath.Join(dir, "missing.yaml")},
+			wantErrs: []error{&FileLoadError{}, MissingRequiredError},
+		},
+	}
+	for _, tc := range cases {
+		target := testValidateFilesConfig{Name: "unchanged"}
+		err := c.Validate(tc.files, &target)
+		var errs []error
+		var le *LoadErrors
+		switch {
+		case errors.As(err, &le):
+			errs = le.Errors
+		case err != nil:
+			errs = []error{err}
+		}
+		if len(errs) != len(tc.wantErrs) {
+			t.Errorf("Validate(%q) err: got=%v want %d errors", tc.files, err, len(tc.wantErrs))
+			continue
+		}
+		for i, want := range tc.wantErrs {
+			if !isError(errs[i], want) {
+				t.Errorf("Validate(%q) error %d: got=%v want=%T %v", tc.files, i, errs[i], want, want)
+			}
+		}
+		if target.Name != "unchanged" {
+			t.Errorf("Validate(%q) modified target: got=%q want=%q", tc.files, target.Name, "unchanged")
+		}
+	}
+}
+
+// isError reports whether err is want or, if want is a pointer to an error
+// type, has that type.
+func isError(err, want error) bool {
+	switch want.(type) {
+	case *UnmarshalError:
+		var ue *UnmarshalError
+		return errors.As(err, &ue)
+	case *FileLoadError:
+		var fe *FileLoadError
+		return errors.As(err, &fe)
+	}
+	return errors.Is(err, want)
+}
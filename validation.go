@@ -0,0 +1,120 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError describes a field that failed a validate tag check.
+type FieldError struct {
+	// Key is the delimited configuration key of the field.
+	Key string
+	// Tag is the check that failed, for example "min", and Param its
+	// parameter, for example "1".
+	Tag   string
+	Param string
+	// Value is the value of the field, or RedactedValue if it is tagged
+	// secret.
+	Value interface{}
+}
+
+func (e FieldError) String() string {
+	if e.Param == "" {
+		return fmt.Sprintf("%s: failed %q check", e.Key, e.Tag)
+	}
+	return fmt.Sprintf("%s: failed %q check (%s)", e.Key, e.Tag, e.Param)
+}
+
+// ValidationError is returned by Load when fields fail validate tag checks.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	ss := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		ss[i] = f.String()
+	}
+	return "validation failed: " + strings.Join(ss, "; ")
+}
+
+// WithValidation makes Load enforce validate struct tags, as defined by
+// github.com/go-playground/validator, after unmarshaling:
+//
+//	type Config struct {
+//	  Port int `koanf:"port" validate:"required,min=1,max=65535"`
+//	}
+//
+// Failures are returned as a *ValidationError naming the configuration key of
+// every offending field.
+func WithValidation() Option {
+	return func(c *Config) {
+		v := validator.New()
+		v.RegisterTagNameFunc(func(sf reflect.StructField) string {
+			if name, ok := fieldName(sf); ok {
+				return name
+			}
+			return sf.Name
+		})
+		c.validator = v
+	}
+}
+
+// validateTags checks the validate tags of cfg if WithValidation was used.
+func (c Config) validateTags(cfg interface{}) error {
+	if c.validator == nil {
+		return nil
+	}
+	err := c.validator.Struct(cfg)
+	if err == nil {
+		return nil
+	}
+	var ves validator.ValidationErrors
+	if !errors.As(err, &ves) {
+		return fmt.Errorf("Load validate: %w", err)
+	}
+	secrets := c.secretKeys(cfg)
+	ve := &ValidationError{}
+	for _, fe := range ves {
+		// The namespace starts with the name of the struct type.
+		_, key, _ := strings.Cut(fe.Namespace(), ".")
+		key = strings.ReplaceAll(key, ".", c.delimiter)
+		value := fe.Value()
+		if secrets[key] {
+			value = RedactedValue
+		}
+		ve.Fields = append(ve.Fields, FieldError{
+			Key:   key,
+			Tag:   fe.Tag(),
+			Param: fe.Param(),
+			Value: value,
+		})
+	}
+	return fmt.Errorf("Load validate: %w", ve)
+}
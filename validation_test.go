@@ -0,0 +1,99 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+type testTaggedServer struct {
+	Port int `koanf:"port" validate:"required,min=1,max=65535"`
+}
+
+type testTaggedConfig struct {
+	Name   string           `koanf:"name" validate:"required"`
+	Server testTaggedServer `koanf:"server"`
+}
+
+func TestLoadWithValidation(t *testing.T) {
+	cases := []struct {
+		name       string
+		args       []string
+		opts       []Option
+		wantFields []FieldError
+	}{
+		{
+			name: "valid",
+			args: []string{"--name=app", "--server.port=80"},
+			opts: []Option{WithValidation()},
+		},
+		{
+			name: "disabled",
+			args: []string{"--server.port=70000"},
+		},
+		{
+			name: "invalid",
+			args: []string{"--server.port=70000"},
+			opts: []Option{WithValidation()},
+			wantFields: []FieldError{
+				{Key: "name", Tag: "required", Value: ""},
+				{Key: "server.port", Tag: "max", Param: "65535", Value: 70000},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+			f.String("name", "", testNoHelpMessage)
+			f.Int("server.port", 0, testNoHelpMessage)
+			if err := f.Parse(tc.args); err != nil {
+				t.Fatalf("f.Parse failed unexpectedly: %v", err)
+			}
+
+			c, err := New(testPrefix, testDelimiter, tc.opts...)
+			if err != nil {
+				t.Fatalf("New failed unexpectedly: %v", err)
+			}
+
+			var cfg testTaggedConfig
+			err = c.Load(f, &cfg)
+			if tc.wantFields == nil {
+				if err != nil {
+					t.Errorf("Load err: got=%v want=nil", err)
+				}
+				return
+			}
+			var ve *ValidationError
+			if !errors.As(err, &ve) {
+				t.Fatalf("Load err: got=%v want *ValidationError", err)
+			}
+			if !reflect.DeepEqual(ve.Fields, tc.wantFields) {
+				t.Errorf("Load fields: got=%+v want=%+v", ve.Fields, tc.wantFields)
+			}
+		})
+	}
+}
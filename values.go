@@ -0,0 +1,220 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bretmckee/goconfig/core"
+	"github.com/knadh/koanf/v2"
+	"github.com/spf13/pflag"
+)
+
+// Values gives access to loaded values by key, with accessors named like
+// those of viper, so that code can move from viper to goconfig one package
+// at a time:
+//
+//	r, err := c.LoadWithResult(f, &cfg)
+//	...
+//	v := r.Values()
+//	host := v.GetString("db.host") // was viper.GetString("db.host")
+//
+// Keys are matched ignoring case. Values are converted as viper converts them
+// where possible, and the accessors return the zero value for keys that are not
+// set or cannot be converted. New code should read the configuration struct.
+//
+// Values can be kept after Load, for example by plugins or admin endpoints,
+// and changed with Set. It is safe for concurrent use.
+type Values struct {
+	mu sync.RWMutex
+	k  *koanf.Koanf
+}
+
+// Values returns the values merged by Load, before they were unmarshaled,
+// without the keys that control Load, such as the config file flag.
+func (r *Result) Values() *Values {
+	k := koanf.New(r.l.k.Delim())
+	lower := make(map[string]interface{})
+	for key, v := range r.l.k.All() {
+		if !r.controlKey(key) {
+			lower[strings.ToLower(key)] = v
+		}
+	}
+	// mapProvider cannot fail.
+	_ = k.Load(mapProvider{m: lower, delim: k.Delim()}, nil)
+	return &Values{k: k}
+}
+
+// Get returns the value of key, or nil if it is not set.
+func (v *Values) Get(key string) interface{} {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.k.Get(strings.ToLower(key))
+}
+
+// GetString returns the value of key as a string.
+func (v *Values) GetString(key string) string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.k.String(strings.ToLower(key))
+}
+
+// GetBool returns the value of key as a bool.
+func (v *Values) GetBool(key string) bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.k.Bool(strings.ToLower(key))
+}
+
+// GetInt returns the value of key as an int.
+func (v *Values) GetInt(key string) int {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.k.Int(strings.ToLower(key))
+}
+
+// GetInt64 returns the value of key as an int64.
+func (v *Values) GetInt64(key string) int64 {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.k.Int64(strings.ToLower(key))
+}
+
+// GetFloat64 returns the value of key as a float64.
+func (v *Values) GetFloat64(key string) float64 {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.k.Float64(strings.ToLower(key))
+}
+
+// GetDuration returns the value of key as a time.Duration. Strings are parsed
+// by time.ParseDuration and numbers are nanoseconds.
+func (v *Values) GetDuration(key string) time.Duration {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.k.Duration(strings.ToLower(key))
+}
+
+// GetStringSlice returns the value of key as a slice of strings. A string is
+// split on commas, as Load does.
+func (v *Values) GetStringSlice(key string) []string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	key = strings.ToLower(key)
+	if s, ok := v.k.Get(key).(string); ok {
+		if s == "" {
+			return []string{}
+		}
+		return strings.Split(s, ",")
+	}
+	return v.k.Strings(key)
+}
+
+// GetStringMapString returns the keys below key and their values as strings.
+func (v *Values) GetStringMapString(key string) map[string]string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	key = strings.ToLower(key)
+	out := make(map[string]string)
+	for k := range v.k.Cut(key).All() {
+		out[k] = v.k.String(key + v.k.Delim() + k)
+	}
+	return out
+}
+
+// Exists is IsSet.
+func (v *Values) Exists(key string) bool {
+	return v.IsSet(key)
+}
+
+// Set sets key to value, replacing any value at or below key. It changes only
+// v, not the configuration struct, which can be updated with Unmarshal.
+func (v *Values) Set(key string, value interface{}) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	key = strings.ToLower(key)
+	v.k.Delete(key)
+	return v.k.Set(key, value)
+}
+
+// Unmarshal stores the values in cfg, converting them as Load does.
+func (v *Values) Unmarshal(cfg interface{}) error {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	uc := koanf.UnmarshalConf{DecoderConfig: core.DecoderConfig(cfg)}
+	if err := v.k.UnmarshalWithConf("", cfg, uc); err != nil {
+		return &UnmarshalError{Err: err}
+	}
+	return nil
+}
+
+// IsSet reports whether key, or a key below it, is set.
+func (v *Values) IsSet(key string) bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.k.Exists(strings.ToLower(key))
+}
+
+// AllKeys returns every key that is set, sorted.
+func (v *Values) AllKeys() []string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	keys := v.k.Keys()
+	sort.Strings(keys)
+	return keys
+}
+
+// AllSettings returns a copy of every value, nested by key.
+func (v *Values) AllSettings() map[string]interface{} {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.k.Raw()
+}
+
+// All returns every value loaded from config files, sources, the environment
+// and flags, nested by key, for programs that read arbitrary keys rather than
+// a configuration struct. Values are not converted, and default tags and
+// checks that need a struct, such as WithStrict, do not apply. Keys that
+// control loading, such as the config file flag, are left out.
+func (c Config) All(f *pflag.FlagSet) (map[string]interface{}, error) {
+	l, err := c.merge(context.Background(), f, nil)
+	if err == nil {
+		err = c.decryptValues(l.k)
+	}
+	if err == nil && c.references {
+		err = resolveReferences(l.k)
+	}
+	if err != nil {
+		return nil, c.translate(err)
+	}
+	for _, key := range l.k.Keys() {
+		if c.controlKey(key) {
+			l.k.Delete(key)
+		}
+	}
+	return l.k.Raw(), nil
+}
@@ -0,0 +1,221 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/pflag"
+)
+
+func TestValues(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "config.yaml")
+	writeTestFile(t, name, `Name: app
+port: "8080"
+debug: true
+ratio: 0.5
+timeout: 5s
+hosts: [a, b]
+labels:
+  team: core
+  tier: 1
+`)
+	t.Setenv(testPrefix+"ZONES", "x,y")
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	AddConfigFlag(f)
+	if err := f.Parse([]string{"--" + FileArgName + "=" + name}); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	var cfg struct {
+		Name string `koanf:"name"`
+	}
+	r, err := c.LoadWithResult(f, &cfg)
+	if err != nil {
+		t.Fatalf("LoadWithResult err: got=%v want=nil", err)
+	}
+	v := r.Values()
+
+	got := map[string]interface{}{
+		"Get":                v.Get("NAME"),
+		"GetString":          v.GetString("name"),
+		"GetInt":             v.GetInt("port"),
+		"GetInt64":           v.GetInt64("port"),
+		"GetBool":            v.GetBool("debug"),
+		"GetFloat64":         v.GetFloat64("ratio"),
+		"GetDuration":        v.GetDuration("timeout"),
+		"GetStringSlice":     v.GetStringSlice("hosts"),
+		"GetStringSlice env": v.GetStringSlice("zones"),
+		"GetStringMapString": v.GetStringMapString("labels"),
+		"IsSet":              v.IsSet("labels"),
+		"IsSet missing":      v.IsSet("missing"),
+		"GetInt missing":     v.GetInt("missing"),
+		"AllKeys":            v.AllKeys(),
+	}
+	want := map[string]interface{}{
+		"Get":                "app",
+		"GetString":          "app",
+		"GetInt":             8080,
+		"GetInt64":           int64(8080),
+		"GetBool":            true,
+		"GetFloat64":         0.5,
+		"GetDuration":        5 * time.Second,
+		"GetStringSlice":     []string{"a", "b"},
+		"GetStringSlice env": []string{"x", "y"},
+		"GetStringMapString": map[string]string{"team": "core", "tier": "1"},
+		"IsSet":              true,
+		"IsSet missing":      false,
+		"GetInt missing":     0,
+		"AllKeys":            []string{"debug", "hosts", "labels.team", "labels.tier", "name", "port", "ratio", "timeout", "zones"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Values mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestAll(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "config.yaml")
+	writeTestFile(t, name, "name: app\ndb:\n  host: file\n  port: 5432\n")
+	t.Setenv(testPrefix+"DB_HOST", "env")
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	AddConfigFlag(f)
+	f.Bool("debug", false, "")
+	if err := f.Parse([]string{"--" + FileArgName + "=" + name, "--debug"}); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	got, err := c.All(f)
+	if err != nil {
+		t.Fatalf("All err: got=%v want=nil", err)
+	}
+	want := map[string]interface{}{
+		"name":  "app",
+		"debug": true,
+		"db":    map[string]interface{}{"host": "env", "port": 5432},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("All mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestValuesSet(t *testing.T) {
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	if err := f.Parse(nil); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	t.Setenv(testPrefix+"DB_HOST", "env")
+	t.Setenv(testPrefix+"DB_PORT", "5432")
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	var cfg testMountConfig
+	r, err := c.LoadWithResult(f, &cfg)
+	if err != nil {
+		t.Fatalf("LoadWithResult err: got=%v want=nil", err)
+	}
+	v := r.Values()
+
+	if err := v.Set("DB.Host", "admin"); err != nil {
+		t.Fatalf("Set err: got=%v want=nil", err)
+	}
+	if err := v.Set("timeout", "5s"); err != nil {
+		t.Fatalf("Set err: got=%v want=nil", err)
+	}
+	if got := v.GetString("db.host"); got != "admin" {
+		t.Errorf("GetString(db.host): got=%q want=%q", got, "admin")
+	}
+	if got := v.GetDuration("timeout"); got != 5*time.Second {
+		t.Errorf("GetDuration(timeout): got=%v want=%v", got, 5*time.Second)
+	}
+	if !v.Exists("db.port") || v.Exists("db.user") {
+		t.Errorf("Exists: got db.port=%t db.user=%t want true, false", v.Exists("db.port"), v.Exists("db.user"))
+	}
+	if err := v.Set("db", map[string]interface{}{"host": "replaced"}); err != nil {
+		t.Fatalf("Set err: got=%v want=nil", err)
+	}
+	if v.Exists("db.port") {
+		t.Errorf("Exists(db.port) after Set(db): got=true want=false")
+	}
+
+	var got testMountConfig
+	if err := v.Unmarshal(&got); err != nil {
+		t.Fatalf("Unmarshal err: got=%v want=nil", err)
+	}
+	var want testMountConfig
+	want.DB.Host = "replaced"
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unmarshal mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestValuesConcurrent(t *testing.T) {
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	if err := f.Parse(nil); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+	var cfg testMountConfig
+	r, err := c.LoadWithResult(f, &cfg)
+	if err != nil {
+		t.Fatalf("LoadWithResult err: got=%v want=nil", err)
+	}
+	v := r.Values()
+
+	// Run with -race to check that Set is safe alongside the readers.
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if i == 0 {
+					_ = v.Set("db.host", fmt.Sprintf("h%d", j))
+					continue
+				}
+				_ = v.Get("db.host")
+				_ = v.GetString("db.host")
+				_ = v.AllSettings()
+			}
+		}(i)
+	}
+	wg.Wait()
+	if got, want := v.GetString("db.host"), "h99"; got != want {
+		t.Errorf("GetString(db.host): got=%q want=%q", got, want)
+	}
+}
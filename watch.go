@@ -0,0 +1,247 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/pflag"
+)
+
+var (
+	NoSubscriberError = errors.New("source cannot subscribe to changes")
+)
+
+// watchDebounce is how long Watch waits for further changes before reloading,
+// since saving a file often produces several events.
+const watchDebounce = 100 * time.Millisecond
+
+// SourceWatcher is implemented by Source providers, such as RedisProvider,
+// that can report changes to their configuration. Watch starts watching and
+// returns; cb is then called, from any goroutine, after every change, with a
+// nil error once Read returns the new configuration, or with an error if
+// watching failed. Providers that also implement Close are closed when the
+// Watch context is done. A provider that cannot watch as configured, such as a
+// RedisProvider without a channel, returns NoSubscriberError, and is polled
+// instead if WithPollInterval was used. The providertest package checks
+// providers against this contract.
+type SourceWatcher interface {
+	Watch(cb func(event interface{}, err error)) error
+}
+
+// Watch loads cfg and then watches the local config files passed via
+// the file flag, and any sources that support watching, for changes. Remote
+// config files and other sources are polled if WithPollInterval was used.
+// When a change is detected the full Load is run again, with the same
+// precedence, and if the result differs from the current value onChange is
+// called with the previous and new values. If the
+// reload or onChange fails, the error is logged and the previous value is kept
+// as the current one.
+//
+// Watch returns after the initial load. Watching stops when ctx is done. cfg
+// is not modified after Watch returns.
+func Watch[T any](ctx context.Context, c Config, f *pflag.FlagSet, cfg *T, onChange func(old, new T) error) error {
+	r, err := newReloader(ctx, c, f, cfg, onChange)
+	if err != nil {
+		return err
+	}
+	return startWatch(ctx, r)
+}
+
+// startWatch watches the inputs of r for changes, reloading r when they
+// change, until ctx is done.
+func startWatch[T any](ctx context.Context, r *reloader[T]) error {
+	c, f := r.c, r.f
+	args, err := c.configFiles(f)
+	if err != nil {
+		return fmt.Errorf("Watch: %w", err)
+	}
+	files := fileNames(args)
+
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("Watch: %w", err)
+	}
+	var watched []string
+	if c.fsys == nil {
+		watched = files
+	}
+	fw := newFileWatch(watched)
+	for _, dir := range fw.dirs() {
+		if err := w.Add(dir); err != nil {
+			w.Close()
+			return fmt.Errorf("Watch %s: %w", dir, err)
+		}
+	}
+
+	changed := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	}
+
+	var polled, watching []Source
+	for _, s := range c.sources {
+		s := s
+		sw, ok := s.Provider.(SourceWatcher)
+		if !ok {
+			polled = append(polled, s)
+			continue
+		}
+		err := sw.Watch(func(_ interface{}, err error) {
+			if err != nil {
+				c.logf("Watch source %s: %v", s.Name, err)
+				return
+			}
+			notify()
+		})
+		switch {
+		case errors.Is(err, NoSubscriberError):
+			polled = append(polled, s)
+		case err != nil:
+			w.Close()
+			c.closeSources(watching)
+			return fmt.Errorf("Watch source %s: %w", s.Name, err)
+		default:
+			watching = append(watching, s)
+		}
+	}
+
+	interval := c.pollInterval
+	if c.fsys != nil {
+		interval = c.fsPollInterval()
+	}
+	if interval > 0 {
+		if targets := c.pollTargets(ctx, files, polled); len(targets) > 0 {
+			go c.pollLoop(ctx, interval, targets, notify)
+		}
+	}
+
+	go func() {
+		defer w.Close()
+		defer c.closeSources(c.sources)
+
+		var debounce <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-w.Events:
+				if !ok {
+					return
+				}
+				if fw.matches(event.Name) {
+					notify()
+				}
+			case err, ok := <-w.Errors:
+				if !ok {
+					return
+				}
+				c.logf("Watch: %v", err)
+			case <-changed:
+				debounce = c.clock().After(watchDebounce)
+			case <-debounce:
+				debounce = nil
+				r.reload(ctx)
+			}
+		}
+	}()
+	return nil
+}
+
+// closeSources closes the providers of sources that support it.
+func (c Config) closeSources(sources []Source) {
+	for _, s := range sources {
+		if cl, ok := s.Provider.(interface{ Close() error }); ok {
+			if err := cl.Close(); err != nil {
+				c.logf("close source %s: %v", s.Name, err)
+			}
+		}
+	}
+}
+
+// fileWatch tracks the local config files being watched. The parent
+// directories are watched so that files replaced by renaming, or by changing a
+// symlink as Kubernetes does for ConfigMaps, are noticed.
+type fileWatch struct {
+	files map[string]string // cleaned path to resolved path
+}
+
+func newFileWatch(files []string) *fileWatch {
+	fw := &fileWatch{files: make(map[string]string)}
+	for _, name := range files {
+		if strings.Contains(name, "://") || isStdin(name) {
+			continue
+		}
+		name = filepath.Clean(name)
+		fw.files[name] = resolvePath(name)
+	}
+	return fw
+}
+
+// dirs returns the directories that must be watched.
+func (fw *fileWatch) dirs() []string {
+	seen := make(map[string]bool)
+	var dirs []string
+	for name := range fw.files {
+		dir := filepath.Dir(name)
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// matches reports whether an event on name may have changed a watched file.
+func (fw *fileWatch) matches(name string) bool {
+	name = filepath.Clean(name)
+	matched := false
+	for file, resolved := range fw.files {
+		if name == file || name == resolved {
+			matched = true
+		}
+		if cur := resolvePath(file); cur != resolved {
+			fw.files[file] = cur
+			matched = true
+		}
+	}
+	return matched
+}
+
+// resolvePath returns name with symlinks resolved, or name if that fails.
+func resolvePath(name string) string {
+	resolved, err := filepath.EvalSymlinks(name)
+	if err != nil {
+		return name
+	}
+	return filepath.Clean(resolved)
+}
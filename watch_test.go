@@ -0,0 +1,160 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/knadh/koanf/parsers/json"
+	"github.com/spf13/pflag"
+)
+
+func writeTestFile(t *testing.T, name, contents string) {
+	t.Helper()
+	if err := os.WriteFile(name, []byte(contents), 0o600); err != nil {
+		t.Fatalf("os.WriteFile failed unexpectedly: %v", err)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "config.json")
+	writeTestFile(t, name, fmt.Sprintf(`{"%s": %d}`, testKey1, testValue1))
+
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	f.StringSlice(FileArgName, nil, testNoHelpMessage)
+	if err := f.Parse([]string{fmt.Sprintf("--%s=%s", FileArgName, name)}); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	type change struct {
+		old, new testConfig
+	}
+	changes := make(chan change, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var cfg testConfig
+	err = Watch(ctx, c, f, &cfg, func(old, new testConfig) error {
+		changes <- change{old, new}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Watch err: got=%v want=nil", err)
+	}
+	if got, want := cfg.Value1, testValue1; got != want {
+		t.Errorf("initial Value1: got=%d want=%d", got, want)
+	}
+
+	writeTestFile(t, name, fmt.Sprintf(`{"%s": %d}`, testKey1, testValue2))
+
+	select {
+	case got := <-changes:
+		if got, want := got.old.Value1, testValue1; got != want {
+			t.Errorf("old Value1: got=%d want=%d", got, want)
+		}
+		if got, want := got.new.Value1, testValue2; got != want {
+			t.Errorf("new Value1: got=%d want=%d", got, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for change")
+	}
+}
+
+func TestWatchFailsForBadFile(t *testing.T) {
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	f.StringSlice(FileArgName, nil, testNoHelpMessage)
+	if err := f.Parse([]string{fmt.Sprintf("--%s=%s", FileArgName, testBadFileName)}); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+
+	c, err := New(testPrefix, testDelimiter)
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	var cfg testConfig
+	err = Watch(context.Background(), c, f, &cfg, func(old, new testConfig) error { return nil })
+	if err == nil {
+		t.Fatalf("Watch: got=nil want=non-nil")
+	}
+}
+
+// testWatchSource is a source whose Watch returns err and that records
+// whether it was closed.
+type testWatchSource struct {
+	err    error
+	closed bool
+}
+
+func (s *testWatchSource) ReadBytes() ([]byte, error) {
+	return []byte("{}"), nil
+}
+
+func (s *testWatchSource) Read() (map[string]interface{}, error) {
+	return nil, errors.New("testWatchSource does not support Read")
+}
+
+func (s *testWatchSource) Watch(func(interface{}, error)) error {
+	return s.err
+}
+
+func (s *testWatchSource) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestWatchClosesSourcesOnError(t *testing.T) {
+	watchErr := errors.New("cannot watch")
+	started, failed := &testWatchSource{}, &testWatchSource{err: watchErr}
+	f := pflag.NewFlagSet(testFlagsetName, pflag.ContinueOnError)
+	if err := f.Parse(nil); err != nil {
+		t.Fatalf("f.Parse failed unexpectedly: %v", err)
+	}
+	c, err := New(testPrefix, testDelimiter,
+		WithSource(Source{Name: "started", Provider: started, Parser: json.Parser()}),
+		WithSource(Source{Name: "failed", Provider: failed, Parser: json.Parser()}))
+	if err != nil {
+		t.Fatalf("New failed unexpectedly: %v", err)
+	}
+
+	var cfg testConfig
+	err = Watch(context.Background(), c, f, &cfg, func(old, new testConfig) error { return nil })
+	if !errors.Is(err, watchErr) {
+		t.Errorf("Watch err: got=%v want=%v", err, watchErr)
+	}
+	if !started.closed {
+		t.Errorf("started source closed: got=false want=true")
+	}
+}
@@ -0,0 +1,171 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultFilePerm is the permission used by WriteFile for new files.
+const DefaultFilePerm fs.FileMode = 0o644
+
+// backupSuffix ends the names of backups made by WriteFile.
+const backupSuffix = ".bak"
+
+// backupTimeFormat is used in the names of backups so that they sort in the
+// order they were made.
+const backupTimeFormat = "20060102T150405.000000000Z"
+
+// WriteOptions controls how WriteFile replaces a file.
+type WriteOptions struct {
+	// Perm is the permission of the file if it does not exist yet. If
+	// zero, DefaultFilePerm is used. Existing files keep their permission.
+	Perm fs.FileMode
+	// Backups is the number of backups of previous contents to keep. Each
+	// backup is named after the file, followed by the time it was made and
+	// .bak. If zero, no backups are made.
+	Backups int
+}
+
+// WriteFile replaces the contents of the file name with data so that a crash
+// leaves either the old or the new contents, never a partial file: data is
+// written to a temporary file in the same directory, which is synced and then
+// renamed over name.
+func WriteFile(name string, data []byte, o WriteOptions) error {
+	perm := o.Perm
+	if perm == 0 {
+		perm = DefaultFilePerm
+	}
+	fi, err := os.Stat(name)
+	switch {
+	case err == nil:
+		perm = fi.Mode().Perm()
+		if o.Backups > 0 {
+			if err := backup(name, perm, o.Backups); err != nil {
+				return fmt.Errorf("WriteFile backup %s: %w", name, err)
+			}
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("WriteFile: %w", err)
+	}
+	if err := writeAtomic(name, data, perm); err != nil {
+		return fmt.Errorf("WriteFile %s: %w", name, err)
+	}
+	return nil
+}
+
+// writeAtomic writes data to name using a temporary file that is renamed over
+// it.
+func writeAtomic(name string, data []byte, perm fs.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".*")
+	if err != nil {
+		return err
+	}
+	// Removing fails harmlessly once the file has been renamed.
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		return err
+	}
+	syncDir(filepath.Dir(name))
+	return nil
+}
+
+// syncDir makes a rename in dir durable. Not every platform supports syncing
+// directories, so errors are ignored.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	_ = d.Sync()
+	d.Close()
+}
+
+// backup copies the current contents of name to a new backup and removes all
+// but the newest keep backups.
+func backup(name string, perm fs.FileMode, keep int) error {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	stamp := time.Now().UTC().Format(backupTimeFormat)
+	if err := writeAtomic(name+"."+stamp+backupSuffix, b, perm); err != nil {
+		return err
+	}
+
+	backups, err := listBackups(name)
+	if err != nil {
+		return err
+	}
+	for len(backups) > keep {
+		if err := os.Remove(backups[0]); err != nil {
+			return err
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
+
+// listBackups returns the backups of name, oldest first.
+func listBackups(name string) ([]string, error) {
+	dir, base := filepath.Split(name)
+	if dir == "" {
+		dir = "."
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var backups []string
+	for _, e := range entries {
+		n := e.Name()
+		if strings.HasPrefix(n, base+".") && strings.HasSuffix(n, backupSuffix) && len(n) == len(base)+1+len(backupTimeFormat)+len(backupSuffix) {
+			backups = append(backups, filepath.Join(dir, n))
+		}
+	}
+	sort.Strings(backups)
+	return backups, nil
+}
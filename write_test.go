@@ -0,0 +1,85 @@
+// MIT License
+//
+// Copyright (c) 2023 Bret McKee
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package goconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "config.yaml")
+
+	if err := WriteFile(name, []byte("v: 0\n"), WriteOptions{Perm: 0o600}); err != nil {
+		t.Fatalf("WriteFile err: got=%v want=nil", err)
+	}
+	fi, err := os.Stat(name)
+	if err != nil {
+		t.Fatalf("Stat failed unexpectedly: %v", err)
+	}
+	if got, want := fi.Mode().Perm(), os.FileMode(0o600); got != want {
+		t.Errorf("new file perm: got=%v want=%v", got, want)
+	}
+
+	if err := os.Chmod(name, 0o640); err != nil {
+		t.Fatalf("Chmod failed unexpectedly: %v", err)
+	}
+	for i := 1; i <= 4; i++ {
+		if err := WriteFile(name, []byte(fmt.Sprintf("v: %d\n", i)), WriteOptions{Backups: 2}); err != nil {
+			t.Fatalf("WriteFile %d err: got=%v want=nil", i, err)
+		}
+	}
+
+	b, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatalf("ReadFile failed unexpectedly: %v", err)
+	}
+	if got, want := string(b), "v: 4\n"; got != want {
+		t.Errorf("contents: got=%q want=%q", got, want)
+	}
+	fi, err = os.Stat(name)
+	if err != nil {
+		t.Fatalf("Stat failed unexpectedly: %v", err)
+	}
+	if got, want := fi.Mode().Perm(), os.FileMode(0o640); got != want {
+		t.Errorf("existing file perm: got=%v want=%v", got, want)
+	}
+
+	backups, err := listBackups(name)
+	if err != nil {
+		t.Fatalf("listBackups failed unexpectedly: %v", err)
+	}
+	var got []string
+	for _, backup := range backups {
+		b, err := os.ReadFile(backup)
+		if err != nil {
+			t.Fatalf("ReadFile failed unexpectedly: %v", err)
+		}
+		got = append(got, string(b))
+	}
+	if want := []string{"v: 2\n", "v: 3\n"}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("backups: got=%q want=%q", got, want)
+	}
+}